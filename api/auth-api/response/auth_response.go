@@ -0,0 +1,13 @@
+package auth_response
+
+type TokenResponse struct {
+	CustomerId   string `json:"customerId"`
+	AccessToken  string `json:"accessToken"`
+	RefreshToken string `json:"refreshToken"`
+	ExpiresIn    int64  `json:"expiresIn"`
+}
+
+type AuthResponse struct {
+	Code    int32  `json:"code"`
+	Message string `json:"message"`
+}
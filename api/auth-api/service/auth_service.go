@@ -0,0 +1,362 @@
+package auth_handler
+
+import (
+	auth_request "mock-golang/api/auth-api/request"
+	auth_response "mock-golang/api/auth-api/response"
+	"mock-golang/apperror"
+	"mock-golang/helper"
+	"mock-golang/logging"
+	"mock-golang/protobuf"
+	"mock-golang/validation"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type AuthHandler interface {
+	Login(c *gin.Context)
+	RefreshToken(c *gin.Context)
+	ForgotPassword(c *gin.Context)
+	ResetPassword(c *gin.Context)
+	SendEmailVerification(c *gin.Context)
+	VerifyEmail(c *gin.Context)
+	SendPhoneOtp(c *gin.Context)
+	VerifyPhone(c *gin.Context)
+	UnlockAccount(c *gin.Context)
+	RequestAccountClaim(c *gin.Context)
+	ClaimAccount(c *gin.Context)
+}
+
+type authHandler struct {
+	authClient protobuf.RPCAuthClient
+}
+
+func NewAuthHandler(authClient protobuf.RPCAuthClient) AuthHandler {
+	return &authHandler{
+		authClient: authClient,
+	}
+}
+
+func (h *authHandler) Login(c *gin.Context) {
+	req := auth_request.LoginRequest{}
+
+	if err := c.ShouldBind(&req); err != nil {
+		validation.AbortWithBindError(c, err)
+		return
+	}
+
+	// Passwords are stored encrypted, compare in the same form
+	encText, err := helper.Encrypt(req.Password)
+	if err != nil {
+		logging.FromContext(c.Request.Context(), zap.L()).Error("encrypt password", zap.Error(err))
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	pReq := &protobuf.LoginRequest{
+		Email:    req.Email,
+		Password: encText,
+	}
+
+	pRes, err := h.authClient.Login(c.Request.Context(), pReq)
+	if err != nil {
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusText(http.StatusOK),
+		"payload": toTokenResponse(pRes),
+	})
+}
+
+func (h *authHandler) RefreshToken(c *gin.Context) {
+	req := auth_request.RefreshTokenRequest{}
+
+	if err := c.ShouldBind(&req); err != nil {
+		validation.AbortWithBindError(c, err)
+		return
+	}
+
+	pReq := &protobuf.RefreshTokenRequest{
+		RefreshToken: req.RefreshToken,
+	}
+
+	pRes, err := h.authClient.RefreshToken(c.Request.Context(), pReq)
+	if err != nil {
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusText(http.StatusOK),
+		"payload": toTokenResponse(pRes),
+	})
+}
+
+func (h *authHandler) ForgotPassword(c *gin.Context) {
+	req := auth_request.ForgotPasswordRequest{}
+
+	if err := c.ShouldBind(&req); err != nil {
+		validation.AbortWithBindError(c, err)
+		return
+	}
+
+	pReq := &protobuf.ForgotPasswordRequest{
+		Email: req.Email,
+	}
+
+	pRes, err := h.authClient.ForgotPassword(c.Request.Context(), pReq)
+	if err != nil {
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": http.StatusText(http.StatusOK),
+		"payload": &auth_response.AuthResponse{
+			Code:    pRes.Code,
+			Message: pRes.Message,
+		},
+	})
+}
+
+func (h *authHandler) ResetPassword(c *gin.Context) {
+	req := auth_request.ResetPasswordRequest{}
+
+	if err := c.ShouldBind(&req); err != nil {
+		validation.AbortWithBindError(c, err)
+		return
+	}
+
+	encText, err := helper.Encrypt(req.NewPassword)
+	if err != nil {
+		logging.FromContext(c.Request.Context(), zap.L()).Error("encrypt password", zap.Error(err))
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	pReq := &protobuf.ResetPasswordRequest{
+		Token:       req.Token,
+		NewPassword: encText,
+	}
+
+	pRes, err := h.authClient.ResetPassword(c.Request.Context(), pReq)
+	if err != nil {
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": http.StatusText(http.StatusOK),
+		"payload": &auth_response.AuthResponse{
+			Code:    pRes.Code,
+			Message: pRes.Message,
+		},
+	})
+}
+
+func (h *authHandler) SendEmailVerification(c *gin.Context) {
+	req := auth_request.VerificationRequest{}
+
+	if err := c.ShouldBind(&req); err != nil {
+		validation.AbortWithBindError(c, err)
+		return
+	}
+
+	pReq := &protobuf.VerificationRequest{
+		CustomerId: req.CustomerId,
+	}
+
+	pRes, err := h.authClient.SendEmailVerification(c.Request.Context(), pReq)
+	if err != nil {
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": http.StatusText(http.StatusOK),
+		"payload": &auth_response.AuthResponse{
+			Code:    pRes.Code,
+			Message: pRes.Message,
+		},
+	})
+}
+
+func (h *authHandler) VerifyEmail(c *gin.Context) {
+	req := auth_request.VerifyEmailRequest{}
+
+	if err := c.ShouldBind(&req); err != nil {
+		validation.AbortWithBindError(c, err)
+		return
+	}
+
+	pReq := &protobuf.VerifyEmailRequest{
+		Token: req.Token,
+	}
+
+	pRes, err := h.authClient.VerifyEmail(c.Request.Context(), pReq)
+	if err != nil {
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": http.StatusText(http.StatusOK),
+		"payload": &auth_response.AuthResponse{
+			Code:    pRes.Code,
+			Message: pRes.Message,
+		},
+	})
+}
+
+func (h *authHandler) SendPhoneOtp(c *gin.Context) {
+	req := auth_request.VerificationRequest{}
+
+	if err := c.ShouldBind(&req); err != nil {
+		validation.AbortWithBindError(c, err)
+		return
+	}
+
+	pReq := &protobuf.VerificationRequest{
+		CustomerId: req.CustomerId,
+	}
+
+	pRes, err := h.authClient.SendPhoneOtp(c.Request.Context(), pReq)
+	if err != nil {
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": http.StatusText(http.StatusOK),
+		"payload": &auth_response.AuthResponse{
+			Code:    pRes.Code,
+			Message: pRes.Message,
+		},
+	})
+}
+
+func (h *authHandler) VerifyPhone(c *gin.Context) {
+	req := auth_request.VerifyPhoneRequest{}
+
+	if err := c.ShouldBind(&req); err != nil {
+		validation.AbortWithBindError(c, err)
+		return
+	}
+
+	pReq := &protobuf.VerifyPhoneRequest{
+		CustomerId: req.CustomerId,
+		Code:       req.Code,
+	}
+
+	pRes, err := h.authClient.VerifyPhone(c.Request.Context(), pReq)
+	if err != nil {
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": http.StatusText(http.StatusOK),
+		"payload": &auth_response.AuthResponse{
+			Code:    pRes.Code,
+			Message: pRes.Message,
+		},
+	})
+}
+
+func (h *authHandler) UnlockAccount(c *gin.Context) {
+	req := auth_request.VerificationRequest{}
+
+	if err := c.ShouldBind(&req); err != nil {
+		validation.AbortWithBindError(c, err)
+		return
+	}
+
+	pReq := &protobuf.VerificationRequest{
+		CustomerId: req.CustomerId,
+	}
+
+	pRes, err := h.authClient.UnlockAccount(c.Request.Context(), pReq)
+	if err != nil {
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": http.StatusText(http.StatusOK),
+		"payload": &auth_response.AuthResponse{
+			Code:    pRes.Code,
+			Message: pRes.Message,
+		},
+	})
+}
+
+func (h *authHandler) RequestAccountClaim(c *gin.Context) {
+	req := auth_request.ForgotPasswordRequest{}
+
+	if err := c.ShouldBind(&req); err != nil {
+		validation.AbortWithBindError(c, err)
+		return
+	}
+
+	pReq := &protobuf.ForgotPasswordRequest{
+		Email: req.Email,
+	}
+
+	pRes, err := h.authClient.RequestAccountClaim(c.Request.Context(), pReq)
+	if err != nil {
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": http.StatusText(http.StatusOK),
+		"payload": &auth_response.AuthResponse{
+			Code:    pRes.Code,
+			Message: pRes.Message,
+		},
+	})
+}
+
+func (h *authHandler) ClaimAccount(c *gin.Context) {
+	req := auth_request.ClaimAccountRequest{}
+
+	if err := c.ShouldBind(&req); err != nil {
+		validation.AbortWithBindError(c, err)
+		return
+	}
+
+	encText, err := helper.Encrypt(req.Password)
+	if err != nil {
+		logging.FromContext(c.Request.Context(), zap.L()).Error("encrypt password", zap.Error(err))
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	pReq := &protobuf.ClaimAccountRequest{
+		Token:    req.Token,
+		Password: encText,
+	}
+
+	pRes, err := h.authClient.ClaimAccount(c.Request.Context(), pReq)
+	if err != nil {
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusText(http.StatusOK),
+		"payload": toTokenResponse(pRes),
+	})
+}
+
+func toTokenResponse(pRes *protobuf.TokenResponse) *auth_response.TokenResponse {
+	return &auth_response.TokenResponse{
+		CustomerId:   pRes.CustomerId,
+		AccessToken:  pRes.AccessToken,
+		RefreshToken: pRes.RefreshToken,
+		ExpiresIn:    pRes.ExpiresIn,
+	}
+}
@@ -1,21 +1,34 @@
 package booking_request
 
 type CustomerBookingRequest struct {
-	Slot       int32  `json:"slot" binding:"required"`
-	CustomerId string `json:"customerId" binding:"required"`
-	FlightId   string `json:"flightId" binding:"required"`
+	Slot       int32              `json:"slot" binding:"required_without=Passengers"`
+	CustomerId string             `json:"customerId" binding:"required"`
+	FlightId   string             `json:"flightId" binding:"required"`
+	Passengers []PassengerRequest `json:"passengers" binding:"omitempty,dive"`
+	FareClass  string             `json:"fareClass" binding:"omitempty,oneof=economy premium_economy business first"`
+}
+
+// PassengerRequest references a saved traveller or carries the passenger inline
+type PassengerRequest struct {
+	TravellerId    string `json:"travellerId"`
+	Name           string `json:"name" binding:"required_without=TravellerId"`
+	DateOfBirth    string `json:"dateOfBirth" binding:"required_without=TravellerId,omitempty,isodate"`
+	DocumentType   string `json:"documentType" binding:"omitempty,oneof=passport id_card"`
+	DocumentNumber string `json:"documentNumber" binding:"required_without=TravellerId"`
+	DocumentExpiry string `json:"documentExpiry" binding:"required_without=TravellerId,omitempty,isodate"`
+	Nationality    string `json:"nationality" binding:"omitempty,len=2"`
 }
 
 type GuestBookingRequest struct {
 	Name           string `json:"name" binding:"required"`
-	Email          string `json:"email" binding:"required"`
-	PhoneNumber    string `json:"phoneNumber" binding:"required"`
-	DateOfBith     string `json:"dateOfBith" binding:"required"`
-	IdentityCard   string `json:"identityCard" binding:"required"`
+	Email          string `json:"email" binding:"required,email"`
+	PhoneNumber    string `json:"phoneNumber" binding:"required,phone"`
+	DateOfBith     string `json:"dateOfBith" binding:"required,isodate"`
+	IdentityCard   string `json:"identityCard" binding:"required,idcard"`
 	Address        string `json:"address" binding:"max=256,min=6"`
 	MembershipCard string `json:"membershipCard"`
 	FlightId       string `json:"flightId" binding:"required"`
-	Slot           int32  `json:"slot" binding:"required"`
+	Slot           int32  `json:"slot" binding:"required,min=1"`
 }
 
 type CancelBookingRequest struct {
@@ -31,6 +44,12 @@ type SearchBookingRequest struct {
 	FlightId   string `json:"flightId"`
 	Code       string `json:"code"`
 	Status     string `json:"status"`
-	FromDate   string `json:"fromDate"`
-	ToDate     string `json:"toDate"`
+	// YYYY-MM-DD, both days included
+	FromDate string `json:"fromDate" binding:"omitempty,isodate"`
+	ToDate   string `json:"toDate" binding:"omitempty,isodate"`
+}
+
+type BookingDecisionRequest struct {
+	BookingId string `json:"bookingId" binding:"required"`
+	Comment   string `json:"comment"`
 }
@@ -1,13 +1,21 @@
 package booking_handler
 
 import (
+	"context"
 	"math/rand"
 	booking_request "mock-golang/api/booking-api/request"
+	"mock-golang/apperror"
+	"mock-golang/helper"
+	"mock-golang/pagination"
 	"mock-golang/protobuf"
+	"mock-golang/rbac"
+	"mock-golang/validation"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/go-playground/validator/v10"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type BookingHandler interface {
@@ -16,6 +24,9 @@ type BookingHandler interface {
 	CancelBooking(c *gin.Context)
 	BookingHistory(c *gin.Context)
 	SearchBooking(c *gin.Context)
+	ListPendingApprovals(c *gin.Context)
+	ApproveBooking(c *gin.Context)
+	RejectBooking(c *gin.Context)
 }
 
 type bookingHandler struct {
@@ -39,32 +50,17 @@ func (h *bookingHandler) CustomerBooking(c *gin.Context) {
 	req := booking_request.CustomerBookingRequest{}
 
 	if err := c.ShouldBind(&req); err != nil {
-		if validateErrors, ok := err.(validator.ValidationErrors); ok {
-			errMessages := make([]string, 0)
-			for _, v := range validateErrors {
-				errMessages = append(errMessages, v.Error())
-			}
-
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-				"status": http.StatusText(http.StatusBadRequest),
-				"error":  errMessages,
-			})
-			return
-		}
-
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"status": http.StatusText(http.StatusBadRequest),
-			"error":  err.Error(),
-		})
-
+		validation.AbortWithBindError(c, err)
 		return
 	}
 
-	if req.Slot <= 0 {
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"status": "99",
-			"error":  "Booking Slot is invalid",
+	if req.Slot <= 0 && len(req.Passengers) == 0 {
+		validation.AbortWithFieldErrors(c, http.StatusUnprocessableEntity, validation.FieldError{
+			Field:   "slot",
+			Code:    "min",
+			Message: "must be at least 1 when no passengers are given",
 		})
+		return
 	}
 
 	// Gen Booking Code
@@ -76,14 +72,25 @@ func (h *bookingHandler) CustomerBooking(c *gin.Context) {
 		BookedSlot: req.Slot,
 		Code:       bookingCode,
 		Status:     "Active",
+		FareClass:  req.FareClass,
+	}
+
+	for _, passenger := range req.Passengers {
+		pReq.Passenger = append(pReq.Passenger, &protobuf.Passenger{
+			TravellerId:    passenger.TravellerId,
+			Name:           passenger.Name,
+			DateOfBirth:    passenger.DateOfBirth,
+			DocumentType:   passenger.DocumentType,
+			DocumentNumber: passenger.DocumentNumber,
+			DocumentExpiry: passenger.DocumentExpiry,
+			Nationality:    passenger.Nationality,
+		})
 	}
 
 	pRes, err := h.bookingClient.CreateBooking(c.Request.Context(), pReq)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"status": http.StatusText(http.StatusInternalServerError),
-			"error":  err.Error(),
-		})
+		// FailedPrecondition (422) e.g. for a passport expiring before the departure
+		apperror.AbortWithRPCError(c, err)
 		return
 	}
 
@@ -94,10 +101,7 @@ func (h *bookingHandler) CustomerBooking(c *gin.Context) {
 
 	pResFind, err := h.bookingClient.FindById(c.Request.Context(), pReqFind)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"status": http.StatusText(http.StatusInternalServerError),
-			"error":  err.Error(),
-		})
+		apperror.AbortWithRPCError(c, err)
 		return
 	}
 
@@ -112,48 +116,26 @@ func (h *bookingHandler) GuestBooking(c *gin.Context) {
 	req := booking_request.GuestBookingRequest{}
 
 	if err := c.ShouldBind(&req); err != nil {
-		if validateErrors, ok := err.(validator.ValidationErrors); ok {
-			errMessages := make([]string, 0)
-			for _, v := range validateErrors {
-				errMessages = append(errMessages, v.Error())
-			}
-
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-				"status": http.StatusText(http.StatusBadRequest),
-				"error":  errMessages,
-			})
-			return
-		}
-
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"status": http.StatusText(http.StatusBadRequest),
-			"error":  err.Error(),
-		})
-
+		validation.AbortWithBindError(c, err)
 		return
 	}
 
-	if req.Slot <= 0 {
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"status": "99",
-			"error":  "Booking Slot is invalid",
-		})
-	}
+	// The guest has no account, the lookups below run as the gateway itself
+	ctx := rbac.ServiceContext(c.Request.Context())
 
 	// Kiem tra xem thong tin nguoi dung da dang ky chua ?
+	// Only verified contacts can link a guest to an existing customer
 	pReqCus := &protobuf.SearchCustomerRequest{
 		Email:        req.Email,
 		PhoneNumber:  req.PhoneNumber,
 		IdentityCard: req.IdentityCard,
+		VerifiedOnly: true,
 	}
 
-	pResCus, err := h.customerClient.SearchCustomer(c.Request.Context(), pReqCus)
+	pResCus, err := h.customerClient.SearchCustomer(ctx, pReqCus)
 
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"status": http.StatusText(http.StatusInternalServerError),
-			"error":  err.Error(),
-		})
+		apperror.AbortWithRPCError(c, err)
 		return
 	}
 
@@ -175,12 +157,9 @@ func (h *bookingHandler) GuestBooking(c *gin.Context) {
 			Status:         1,
 		}
 
-		pResCreateCust, err := h.customerClient.CreateCustomer(c.Request.Context(), pReqCreateCust)
+		pResCreateCust, err := h.customerClient.CreateCustomer(ctx, pReqCreateCust)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-				"status": http.StatusText(http.StatusInternalServerError),
-				"error":  err.Error(),
-			})
+			apperror.AbortWithRPCError(c, err)
 			return
 		}
 
@@ -199,12 +178,9 @@ func (h *bookingHandler) GuestBooking(c *gin.Context) {
 		Status:     "Active",
 	}
 
-	pRes, err := h.bookingClient.CreateBooking(c.Request.Context(), pReq)
+	pRes, err := h.bookingClient.CreateBooking(ctx, pReq)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"status": http.StatusText(http.StatusInternalServerError),
-			"error":  err.Error(),
-		})
+		apperror.AbortWithRPCError(c, err)
 		return
 	}
 
@@ -213,25 +189,17 @@ func (h *bookingHandler) GuestBooking(c *gin.Context) {
 		Id: req.FlightId,
 	}
 
-	pResFlight, err := h.flightClient.FindById(c.Request.Context(), pReqFlight)
+	pResFlight, err := h.flightClient.FindById(ctx, pReqFlight)
 
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"status": http.StatusText(http.StatusInternalServerError),
-			"error":  err.Error(),
-		})
+		apperror.AbortWithRPCError(c, err)
 		return
 	}
 
 	pResFlight.AvailableSlot = pResFlight.AvailableSlot - req.Slot
 
-	pResFlight2, err := h.flightClient.UpdateFlight(c.Request.Context(), pResFlight)
-
-	if err != nil && pResFlight2 != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"status": http.StatusText(http.StatusInternalServerError),
-			"error":  err.Error(),
-		})
+	if _, err := h.flightClient.UpdateFlight(ctx, pResFlight); err != nil {
+		apperror.AbortWithRPCError(c, err)
 		return
 	}
 
@@ -240,12 +208,9 @@ func (h *bookingHandler) GuestBooking(c *gin.Context) {
 		Id: pRes.Id,
 	}
 
-	pResFind, err := h.bookingClient.FindById(c.Request.Context(), pReqFind)
+	pResFind, err := h.bookingClient.FindById(ctx, pReqFind)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"status": http.StatusText(http.StatusInternalServerError),
-			"error":  err.Error(),
-		})
+		apperror.AbortWithRPCError(c, err)
 		return
 	}
 
@@ -259,24 +224,7 @@ func (h *bookingHandler) CancelBooking(c *gin.Context) {
 	req := booking_request.CancelBookingRequest{}
 
 	if err := c.ShouldBind(&req); err != nil {
-		if validateErrors, ok := err.(validator.ValidationErrors); ok {
-			errMessages := make([]string, 0)
-			for _, v := range validateErrors {
-				errMessages = append(errMessages, v.Error())
-			}
-
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-				"status": http.StatusText(http.StatusBadRequest),
-				"error":  errMessages,
-			})
-			return
-		}
-
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"status": http.StatusText(http.StatusBadRequest),
-			"error":  err.Error(),
-		})
-
+		validation.AbortWithBindError(c, err)
 		return
 	}
 
@@ -287,18 +235,12 @@ func (h *bookingHandler) CancelBooking(c *gin.Context) {
 
 	pResFind, err := h.bookingClient.FindById(c.Request.Context(), pReqFind)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"status": http.StatusText(http.StatusInternalServerError),
-			"error":  err.Error(),
-		})
+		apperror.AbortWithRPCError(c, err)
 		return
 	}
 
 	if pResFind.Id == "" {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"status": "99",
-			"error":  "ID not existed",
-		})
+		apperror.AbortWithRPCError(c, status.Errorf(codes.NotFound, "booking %s not found", req.Id))
 		return
 	}
 
@@ -306,10 +248,7 @@ func (h *bookingHandler) CancelBooking(c *gin.Context) {
 
 	pRes, err := h.bookingClient.UpdateBooking(c.Request.Context(), pResFind)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"status": http.StatusText(http.StatusInternalServerError),
-			"error":  err.Error(),
-		})
+		apperror.AbortWithRPCError(c, err)
 		return
 	}
 
@@ -320,10 +259,7 @@ func (h *bookingHandler) CancelBooking(c *gin.Context) {
 
 	pResFindBk, err := h.bookingClient.FindById(c.Request.Context(), pReqFindBk)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"status": http.StatusText(http.StatusInternalServerError),
-			"error":  err.Error(),
-		})
+		apperror.AbortWithRPCError(c, err)
 		return
 	}
 
@@ -337,43 +273,37 @@ func (h *bookingHandler) BookingHistory(c *gin.Context) {
 	req := booking_request.ViewBookingRequest{}
 
 	if err := c.ShouldBind(&req); err != nil {
-		if validateErrors, ok := err.(validator.ValidationErrors); ok {
-			errMessages := make([]string, 0)
-			for _, v := range validateErrors {
-				errMessages = append(errMessages, v.Error())
-			}
-
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-				"status": http.StatusText(http.StatusBadRequest),
-				"error":  errMessages,
-			})
-			return
-		}
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"status": http.StatusText(http.StatusBadRequest),
-			"error":  err.Error(),
-		})
+		validation.AbortWithBindError(c, err)
+		return
+	}
 
+	// Paging comes in the query string, the filters stay in the body
+	page := pagination.Query{}
+	if err := c.ShouldBindQuery(&page); err != nil {
+		validation.AbortWithBindError(c, err)
 		return
 	}
 
 	pReq := &protobuf.SearchBookingRequest{
 		CustomerId: req.CustomerId,
+		PageSize:   page.PageSize,
+		PageToken:  page.PageToken,
+		OrderBy:    page.OrderBy,
 	}
 
 	pRes, err := h.bookingClient.SearchBooking(c.Request.Context(), pReq)
 
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"status": http.StatusText(http.StatusInternalServerError),
-			"error":  err.Error(),
-		})
+		apperror.AbortWithRPCError(c, err)
 		return
 	}
 
+	pagination.SetLinkHeader(c, pRes.NextPageToken)
 	c.JSON(http.StatusOK, gin.H{
-		"status":  http.StatusText(http.StatusOK),
-		"payload": pRes.Booking,
+		"status":        http.StatusText(http.StatusOK),
+		"payload":       pRes.Booking,
+		"nextPageToken": pRes.NextPageToken,
+		"totalSize":     pRes.TotalSize,
 	})
 }
 
@@ -381,23 +311,14 @@ func (h *bookingHandler) SearchBooking(c *gin.Context) {
 	req := booking_request.SearchBookingRequest{}
 
 	if err := c.ShouldBind(&req); err != nil {
-		if validateErrors, ok := err.(validator.ValidationErrors); ok {
-			errMessages := make([]string, 0)
-			for _, v := range validateErrors {
-				errMessages = append(errMessages, v.Error())
-			}
-
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-				"status": http.StatusText(http.StatusBadRequest),
-				"error":  errMessages,
-			})
-			return
-		}
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"status": http.StatusText(http.StatusBadRequest),
-			"error":  err.Error(),
-		})
+		validation.AbortWithBindError(c, err)
+		return
+	}
 
+	// Paging comes in the query string, the filters stay in the body
+	page := pagination.Query{}
+	if err := c.ShouldBindQuery(&page); err != nil {
+		validation.AbortWithBindError(c, err)
 		return
 	}
 
@@ -406,15 +327,36 @@ func (h *bookingHandler) SearchBooking(c *gin.Context) {
 		FlightId:   req.FlightId,
 		Code:       req.Code,
 		Status:     req.Status,
+		PageSize:   page.PageSize,
+		PageToken:  page.PageToken,
+		OrderBy:    page.OrderBy,
 	}
+	pReq.FromDate, pReq.ToDate = helper.DateRange(req.FromDate, req.ToDate)
 
 	pRes, err := h.bookingClient.SearchBooking(c.Request.Context(), pReq)
 
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"status": http.StatusText(http.StatusInternalServerError),
-			"error":  err.Error(),
-		})
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	pagination.SetLinkHeader(c, pRes.NextPageToken)
+	c.JSON(http.StatusOK, gin.H{
+		"status":        http.StatusText(http.StatusOK),
+		"payload":       pRes.Booking,
+		"nextPageToken": pRes.NextPageToken,
+		"totalSize":     pRes.TotalSize,
+	})
+}
+
+// ListPendingApprovals lists the bookings of an organization waiting for its approvers
+func (h *bookingHandler) ListPendingApprovals(c *gin.Context) {
+	// The route always has the parameter, the booking service rejects an id that is not a uuid
+	pRes, err := h.bookingClient.ListPendingApprovals(c.Request.Context(), &protobuf.PendingApprovalsRequest{
+		OrganizationId: c.Param("organizationId"),
+	})
+	if err != nil {
+		apperror.AbortWithRPCError(c, err)
 		return
 	}
 
@@ -424,6 +366,37 @@ func (h *bookingHandler) SearchBooking(c *gin.Context) {
 	})
 }
 
+func (h *bookingHandler) ApproveBooking(c *gin.Context) {
+	h.decideBooking(c, h.bookingClient.ApproveBooking)
+}
+
+func (h *bookingHandler) RejectBooking(c *gin.Context) {
+	h.decideBooking(c, h.bookingClient.RejectBooking)
+}
+
+func (h *bookingHandler) decideBooking(c *gin.Context, decide func(ctx context.Context, in *protobuf.BookingDecisionRequest, opts ...grpc.CallOption) (*protobuf.Booking, error)) {
+	req := booking_request.BookingDecisionRequest{}
+
+	if err := c.ShouldBind(&req); err != nil {
+		validation.AbortWithBindError(c, err)
+		return
+	}
+
+	pRes, err := decide(c.Request.Context(), &protobuf.BookingDecisionRequest{
+		BookingId: req.BookingId,
+		Comment:   req.Comment,
+	})
+	if err != nil {
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusText(http.StatusOK),
+		"payload": pRes,
+	})
+}
+
 func generateCode(n int) string {
 	var chars = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0987654321")
 	str := make([]rune, n)
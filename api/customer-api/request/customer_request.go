@@ -1,12 +1,14 @@
 package customer_request
 
+import "mock-golang/pagination"
+
 type CreateCustomerRequest struct {
 	Role           int32  `json:"role" binding:"required"`
 	Name           string `json:"name" binding:"required"`
-	Email          string `json:"email" binding:"required"`
-	PhoneNumber    string `json:"phoneNumber" binding:"required"`
-	DateOfBith     string `json:"dateOfBith" binding:"required"`
-	IdentityCard   string `json:"identityCard" binding:"required"`
+	Email          string `json:"email" binding:"required,email"`
+	PhoneNumber    string `json:"phoneNumber" binding:"required,phone"`
+	DateOfBith     string `json:"dateOfBith" binding:"required,isodate"`
+	IdentityCard   string `json:"identityCard" binding:"required,idcard"`
 	Address        string `json:"address" binding:"max=256,min=6"`
 	MembershipCard string `json:"membershipCard"`
 	Password       string `json:"password"`
@@ -15,15 +17,13 @@ type CreateCustomerRequest struct {
 
 type UpdateCustomerRequest struct {
 	Id             string `json:"id" binding:"required"`
-	Role           int32  `json:"role"`
 	Name           string `json:"name"`
-	Email          string `json:"email"`
-	PhoneNumber    string `json:"phoneNumber"`
-	DateOfBith     string `json:"dateOfBith"`
-	IdentityCard   string `json:"identityCard"`
-	Address        string `json:"address"`
+	Email          string `json:"email" binding:"omitempty,email"`
+	PhoneNumber    string `json:"phoneNumber" binding:"omitempty,phone"`
+	DateOfBith     string `json:"dateOfBith" binding:"omitempty,isodate"`
+	IdentityCard   string `json:"identityCard" binding:"omitempty,idcard"`
+	Address        string `json:"address" binding:"omitempty,max=256,min=6"`
 	MembershipCard string `json:"membershipCard"`
-	Password       string `json:"password"`
 	Status         int32  `json:"status"`
 }
 
@@ -31,5 +31,76 @@ type ChangePasswordRequest struct {
 	Id              string `json:"id" binding:"required"`
 	OldPassword     string `json:"oldPassword" binding:"required"`
 	NewPassword     string `json:"newPassword" binding:"required"`
-	ConfirmPassword string `json:"confirmPassword" binding:"required"`
+	ConfirmPassword string `json:"confirmPassword" binding:"required,eqfield=NewPassword"`
+}
+
+type AssignRoleRequest struct {
+	CustomerId string `json:"customerId" binding:"required"`
+	Role       string `json:"role" binding:"required,oneof=guest customer agent ops admin"`
+}
+
+type MergeCustomersRequest struct {
+	TargetId  string   `json:"targetId" binding:"required"`
+	SourceIds []string `json:"sourceIds" binding:"required,min=1"`
+}
+
+type DuplicateCustomersRequest struct {
+	Limit int32 `form:"limit"`
+}
+
+type CreateTravellerRequest struct {
+	CustomerId     string `json:"customerId" binding:"required"`
+	Name           string `json:"name" binding:"required"`
+	DateOfBirth    string `json:"dateOfBirth" binding:"required,isodate"`
+	DocumentType   string `json:"documentType" binding:"required,oneof=passport id_card"`
+	DocumentNumber string `json:"documentNumber" binding:"required"`
+	DocumentExpiry string `json:"documentExpiry" binding:"required,isodate"`
+	Nationality    string `json:"nationality" binding:"required,len=2"`
+}
+
+type UpdateTravellerRequest struct {
+	Id             string `json:"id" binding:"required"`
+	Name           string `json:"name"`
+	DateOfBirth    string `json:"dateOfBirth" binding:"omitempty,isodate"`
+	DocumentType   string `json:"documentType" binding:"omitempty,oneof=passport id_card"`
+	DocumentNumber string `json:"documentNumber"`
+	DocumentExpiry string `json:"documentExpiry" binding:"omitempty,isodate"`
+	Nationality    string `json:"nationality" binding:"omitempty,len=2"`
+}
+
+type ExportCustomerDataRequest struct {
+	Format string `form:"format" binding:"omitempty,oneof=json zip"`
+}
+
+type EraseCustomerRequest struct {
+	CustomerId string `json:"customerId" binding:"required"`
+	Reason     string `json:"reason" binding:"required,max=512"`
+}
+
+type DeactivateCustomerRequest struct {
+	CustomerId string `json:"customerId" binding:"required"`
+	Reason     string `json:"reason" binding:"max=512"`
+}
+
+type SearchCustomerRequest struct {
+	Name           string `form:"name"`
+	Email          string `form:"email" binding:"omitempty,email"`
+	EmailPrefix    string `form:"emailPrefix"`
+	PhoneNumber    string `form:"phoneNumber"`
+	PhoneSuffix    string `form:"phoneSuffix" binding:"omitempty,numeric,min=3"`
+	IdentityCard   string `form:"identityCard"`
+	IncludeDeleted bool   `form:"includeDeleted"`
+	pagination.Query
+}
+
+// NotificationPreferencesRequest replaces all preferences, so every flag is required
+type NotificationPreferencesRequest struct {
+	CustomerId     string `json:"customerId" binding:"required"`
+	EmailEnabled   *bool  `json:"emailEnabled" binding:"required"`
+	SmsEnabled     *bool  `json:"smsEnabled" binding:"required"`
+	PushEnabled    *bool  `json:"pushEnabled" binding:"required"`
+	BookingUpdates *bool  `json:"bookingUpdates" binding:"required"`
+	FlightChanges  *bool  `json:"flightChanges" binding:"required"`
+	Promotions     *bool  `json:"promotions" binding:"required"`
+	Language       string `json:"language" binding:"omitempty,oneof=vi en"`
 }
@@ -1,16 +1,23 @@
 package customer_response
 
+import "time"
+
 type CustomerResponse struct {
-	Id             string `json:"id"`
-	Role           int32  `json:"role"`
-	Name           string `json:"name"`
-	Email          string `json:"email"`
-	PhoneNumber    string `json:"phoneNumber"`
-	DateOfBith     string `json:"dateOfBith"`
-	IdentityCard   string `json:"identityCard"`
-	Address        string `json:"address"`
-	MembershipCard string `json:"membershipCard"`
-	Status         int32  `json:"status"`
+	Id             string     `json:"id"`
+	Role           int32      `json:"role"`
+	RoleName       string     `json:"roleName"`
+	Name           string     `json:"name"`
+	Email          string     `json:"email"`
+	PhoneNumber    string     `json:"phoneNumber"`
+	DateOfBith     string     `json:"dateOfBith"`
+	IdentityCard   string     `json:"identityCard"`
+	Address        string     `json:"address"`
+	MembershipCard string     `json:"membershipCard"`
+	Status         int32      `json:"status"`
+	EmailVerified  bool       `json:"emailVerified"`
+	PhoneVerified  bool       `json:"phoneVerified"`
+	LoyaltyTier    string     `json:"loyaltyTier,omitempty"`
+	DeletedAt      *time.Time `json:"deletedAt,omitempty"`
 }
 
 type ChangePasswordResponse struct {
@@ -18,3 +25,51 @@ type ChangePasswordResponse struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
 }
+
+type MergeCustomersResponse struct {
+	Customer      *CustomerResponse `json:"customer"`
+	MovedBookings int64             `json:"movedBookings"`
+}
+
+type DuplicateGroupResponse struct {
+	MatchField string              `json:"matchField"`
+	Customers  []*CustomerResponse `json:"customers"`
+}
+
+type TravellerResponse struct {
+	Id             string `json:"id"`
+	CustomerId     string `json:"customerId"`
+	Name           string `json:"name"`
+	DateOfBirth    string `json:"dateOfBirth"`
+	DocumentType   string `json:"documentType"`
+	DocumentNumber string `json:"documentNumber"`
+	DocumentExpiry string `json:"documentExpiry"`
+	Nationality    string `json:"nationality"`
+}
+
+type EraseCustomerResponse struct {
+	CustomerId           string    `json:"customerId"`
+	ErasedAt             time.Time `json:"erasedAt"`
+	AnonymizedPassengers int64     `json:"anonymizedPassengers"`
+	DeletedTravellers    int64     `json:"deletedTravellers"`
+}
+
+type NotificationPreferencesResponse struct {
+	CustomerId     string             `json:"customerId"`
+	EmailEnabled   bool               `json:"emailEnabled"`
+	SmsEnabled     bool               `json:"smsEnabled"`
+	PushEnabled    bool               `json:"pushEnabled"`
+	BookingUpdates bool               `json:"bookingUpdates"`
+	FlightChanges  bool               `json:"flightChanges"`
+	Promotions     bool               `json:"promotions"`
+	Language       string             `json:"language"`
+	UpdatedAt      *time.Time         `json:"updatedAt,omitempty"`
+	Consents       []*ConsentResponse `json:"consents"`
+}
+
+type ConsentResponse struct {
+	Purpose   string    `json:"purpose"`
+	Granted   bool      `json:"granted"`
+	ActorId   string    `json:"actorId"`
+	CreatedAt time.Time `json:"createdAt"`
+}
@@ -1,32 +1,42 @@
 package customer_handler
 
 import (
-	"crypto/aes"
-	"crypto/cipher"
-	"encoding/base64"
 	"fmt"
 	customer_request "mock-golang/api/customer-api/request"
 	customer_response "mock-golang/api/customer-api/response"
+	"mock-golang/apperror"
+	"mock-golang/helper"
+	"mock-golang/logging"
+	"mock-golang/pagination"
 	"mock-golang/protobuf"
+	"mock-golang/rbac"
+	"mock-golang/validation"
 	"net/http"
-	"net/mail"
 	"strings"
-	"time"
-	"unicode"
 
 	"github.com/gin-gonic/gin"
-	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
-var bytes = []byte{35, 46, 57, 24, 85, 35, 24, 74, 87, 35, 88, 98, 66, 32, 14, 05}
-
-// This should be in an env file in production
-const MySecret string = "abc&1*~#^2^#s0^=)^^7%b34"
-
 type CustomerHandler interface {
 	CreateCustomer(c *gin.Context)
 	UpdateCustomer(c *gin.Context)
 	ChangePassword(c *gin.Context)
+	AssignRole(c *gin.Context)
+	MergeCustomers(c *gin.Context)
+	FindDuplicateCustomers(c *gin.Context)
+	ListTravellers(c *gin.Context)
+	CreateTraveller(c *gin.Context)
+	UpdateTraveller(c *gin.Context)
+	DeleteTraveller(c *gin.Context)
+	ExportCustomerData(c *gin.Context)
+	EraseCustomer(c *gin.Context)
+	DeactivateCustomer(c *gin.Context)
+	SearchCustomer(c *gin.Context)
+	GetNotificationPreferences(c *gin.Context)
+	UpdateNotificationPreferences(c *gin.Context)
 }
 
 type customerHandler struct {
@@ -43,24 +53,7 @@ func (h *customerHandler) CreateCustomer(c *gin.Context) {
 	req := customer_request.CreateCustomerRequest{}
 
 	if err := c.ShouldBind(&req); err != nil {
-		if validateErrors, ok := err.(validator.ValidationErrors); ok {
-			errMessages := make([]string, 0)
-			for _, v := range validateErrors {
-				errMessages = append(errMessages, v.Error())
-			}
-
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-				"status": http.StatusText(http.StatusBadRequest),
-				"error":  errMessages,
-			})
-			return
-		}
-
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"status": http.StatusText(http.StatusBadRequest),
-			"error":  err.Error(),
-		})
-
+		validation.AbortWithBindError(c, err)
 		return
 	}
 
@@ -76,55 +69,13 @@ func (h *customerHandler) CreateCustomer(c *gin.Context) {
 		Password:       req.Password,
 		Status:         req.Status,
 	}
-	//validate Date
-	if len(strings.TrimSpace(req.DateOfBith)) > 0 {
-		// To validate Email
-		err := isDateValue(req.DateOfBith)
-		if err != true {
-			fmt.Println("error encrypting your classified text: ", err)
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-				"status": http.StatusText(http.StatusInternalServerError),
-				"error":  "DateOfBith not valid",
-			})
-			return
-		}
-	}
-	//validate Email
-	if len(strings.TrimSpace(req.Email)) > 0 {
-		// To validate Email
-		err := validateEmail(req.Email)
-		if err != true {
-			fmt.Println("error encrypting your classified text: ", err)
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-				"status": http.StatusText(http.StatusInternalServerError),
-				"error":  "email not valid",
-			})
-			return
-		}
-	}
-	//validate String
-	if len(strings.TrimSpace(req.IdentityCard)) > 0 {
-		// To validate IdentityCard
-		err := isInt(req.IdentityCard)
-		if err != true {
-			fmt.Println("error encrypting your classified text: ", err)
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-				"status": http.StatusText(http.StatusInternalServerError),
-				"error":  "IdentityCard not valid",
-			})
-			return
-		}
-	}
 	// encrypt pwd
 	if len(strings.TrimSpace(req.Password)) > 0 {
 		// To encrypt the StringToEncrypt
-		encText, err := Encrypt(req.Password)
+		encText, err := helper.Encrypt(req.Password)
 		if err != nil {
-			fmt.Println("error encrypting your classified text: ", err)
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-				"status": http.StatusText(http.StatusInternalServerError),
-				"error":  err.Error(),
-			})
+			logging.FromContext(c.Request.Context(), zap.L()).Error("encrypt password", zap.Error(err))
+			apperror.AbortWithRPCError(c, err)
 			return
 		}
 
@@ -133,16 +84,14 @@ func (h *customerHandler) CreateCustomer(c *gin.Context) {
 
 	pRes, err := h.customerClient.CreateCustomer(c.Request.Context(), pReq)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"status": http.StatusText(http.StatusInternalServerError),
-			"error":  err.Error(),
-		})
+		apperror.AbortWithRPCError(c, err)
 		return
 	}
 
 	dto := &customer_response.CustomerResponse{
 		Id:             pRes.Id,
 		Role:           pRes.Role,
+		RoleName:       rbac.Role(pRes.Role).String(),
 		Name:           pRes.Name,
 		Email:          pRes.Email,
 		PhoneNumber:    pRes.PhoneNumber,
@@ -151,6 +100,9 @@ func (h *customerHandler) CreateCustomer(c *gin.Context) {
 		Address:        pRes.Address,
 		MembershipCard: pRes.MembershipCard,
 		Status:         pRes.Status,
+		EmailVerified:  pRes.EmailVerifiedAt != nil,
+		PhoneVerified:  pRes.PhoneVerifiedAt != nil,
+		LoyaltyTier:    pRes.LoyaltyTier,
 	}
 	c.JSON(http.StatusOK, gin.H{
 		"status":  http.StatusText(http.StatusOK),
@@ -160,32 +112,13 @@ func (h *customerHandler) CreateCustomer(c *gin.Context) {
 
 func (h *customerHandler) UpdateCustomer(c *gin.Context) {
 	req := customer_request.UpdateCustomerRequest{}
-	fmt.Printf("id", c.Param("id"))
 	if err := c.ShouldBind(&req); err != nil {
-		if validateErrors, ok := err.(validator.ValidationErrors); ok {
-			errMessages := make([]string, 0)
-			for _, v := range validateErrors {
-				errMessages = append(errMessages, v.Error())
-			}
-
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-				"status": http.StatusText(http.StatusBadRequest),
-				"error":  errMessages,
-			})
-			return
-		}
-
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"status": http.StatusText(http.StatusBadRequest),
-			"error":  err.Error(),
-		})
-
+		validation.AbortWithBindError(c, err)
 		return
 	}
 
 	pReq := &protobuf.Customer{
 		Id:             req.Id,
-		Role:           req.Role,
 		Name:           req.Name,
 		Email:          req.Email,
 		PhoneNumber:    req.PhoneNumber,
@@ -196,34 +129,16 @@ func (h *customerHandler) UpdateCustomer(c *gin.Context) {
 		Status:         req.Status,
 	}
 
-	// encrypt pwd
-	if len(strings.TrimSpace(req.Password)) > 0 {
-		// To encrypt the StringToEncrypt
-		encText, err := Encrypt(req.Password)
-		if err != nil {
-			fmt.Println("error encrypting your classified text: ", err)
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-				"status": http.StatusText(http.StatusInternalServerError),
-				"error":  err.Error(),
-			})
-			return
-		}
-
-		pReq.Password = encText
-	}
-
 	pRes, err := h.customerClient.UpdateCustomer(c.Request.Context(), pReq)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"status": http.StatusText(http.StatusInternalServerError),
-			"error":  err.Error(),
-		})
+		apperror.AbortWithRPCError(c, err)
 		return
 	}
 
 	dto := &customer_response.CustomerResponse{
 		Id:             pRes.Id,
 		Role:           pRes.Role,
+		RoleName:       rbac.Role(pRes.Role).String(),
 		Name:           pRes.Name,
 		Email:          pRes.Email,
 		PhoneNumber:    pRes.PhoneNumber,
@@ -232,6 +147,9 @@ func (h *customerHandler) UpdateCustomer(c *gin.Context) {
 		Address:        pRes.Address,
 		MembershipCard: pRes.MembershipCard,
 		Status:         pRes.Status,
+		EmailVerified:  pRes.EmailVerifiedAt != nil,
+		PhoneVerified:  pRes.PhoneVerifiedAt != nil,
+		LoyaltyTier:    pRes.LoyaltyTier,
 	}
 	c.JSON(http.StatusOK, gin.H{
 		"status":  http.StatusText(http.StatusOK),
@@ -243,24 +161,7 @@ func (h *customerHandler) ChangePassword(c *gin.Context) {
 	req := customer_request.ChangePasswordRequest{}
 
 	if err := c.ShouldBind(&req); err != nil {
-		if validateErrors, ok := err.(validator.ValidationErrors); ok {
-			errMessages := make([]string, 0)
-			for _, v := range validateErrors {
-				errMessages = append(errMessages, v.Error())
-			}
-
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-				"status": http.StatusText(http.StatusBadRequest),
-				"error":  errMessages,
-			})
-			return
-		}
-
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"status": http.StatusText(http.StatusBadRequest),
-			"error":  err.Error(),
-		})
-
+		validation.AbortWithBindError(c, err)
 		return
 	}
 
@@ -270,62 +171,35 @@ func (h *customerHandler) ChangePassword(c *gin.Context) {
 	// Check existed
 	pResCheck, err := h.customerClient.FindById(c.Request.Context(), pReqCheck)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"status": http.StatusText(http.StatusInternalServerError),
-			"error":  err.Error(),
-		})
+		apperror.AbortWithRPCError(c, err)
 		return
 	}
 
 	if pResCheck == nil || pResCheck.Id == "" {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"status": http.StatusText(http.StatusNotFound),
-			"error":  "ID does not exist in the system",
-		})
+		apperror.AbortWithRPCError(c, status.Errorf(codes.NotFound, "customer %s not found", req.Id))
 		return
 	}
 
-	// Check old password not match
-	decText, err := Decrypt(pResCheck.Password)
+	// Old password is checked by the gRPC service, which throttles wrong guesses
+	oldEncText, err := helper.Encrypt(req.OldPassword)
 	if err != nil {
-		fmt.Println("error decrypting your classified text: ", err)
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"status": http.StatusText(http.StatusInternalServerError),
-			"error":  err.Error(),
-		})
-		return
-	}
-
-	if req.OldPassword != decText {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"status": 99,
-			"error":  "Old password not match",
-		})
-		return
-	}
-
-	if req.NewPassword != req.ConfirmPassword {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"status": 99,
-			"error":  "New password not match with Confirm password",
-		})
+		logging.FromContext(c.Request.Context(), zap.L()).Error("encrypt password", zap.Error(err))
+		apperror.AbortWithRPCError(c, err)
 		return
 	}
 
 	pReq := &protobuf.ChangePasswordRequest{
-		CustomerId: req.Id,
+		CustomerId:  req.Id,
+		OldPassword: oldEncText,
 	}
 
 	// encrypt pwd
 	if len(strings.TrimSpace(req.NewPassword)) > 0 {
 		// To encrypt the StringToEncrypt
-		encText, err := Encrypt(req.NewPassword)
+		encText, err := helper.Encrypt(req.NewPassword)
 		if err != nil {
-			fmt.Println("error encrypting your classified text: ", err)
-			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-				"status": http.StatusText(http.StatusInternalServerError),
-				"error":  err.Error(),
-			})
+			logging.FromContext(c.Request.Context(), zap.L()).Error("encrypt password", zap.Error(err))
+			apperror.AbortWithRPCError(c, err)
 			return
 		}
 
@@ -334,10 +208,7 @@ func (h *customerHandler) ChangePassword(c *gin.Context) {
 
 	pRes, err := h.customerClient.ChangePassword(c.Request.Context(), pReq)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"status": http.StatusText(http.StatusInternalServerError),
-			"error":  err.Error(),
-		})
+		apperror.AbortWithRPCError(c, err)
 		return
 	}
 
@@ -347,56 +218,445 @@ func (h *customerHandler) ChangePassword(c *gin.Context) {
 	})
 }
 
-func Encode(b []byte) string {
-	return base64.StdEncoding.EncodeToString(b)
+func (h *customerHandler) AssignRole(c *gin.Context) {
+	req := customer_request.AssignRoleRequest{}
+
+	if err := c.ShouldBind(&req); err != nil {
+		validation.AbortWithBindError(c, err)
+		return
+	}
+
+	pReq := &protobuf.AssignRoleRequest{
+		CustomerId: req.CustomerId,
+		Role:       req.Role,
+	}
+
+	pRes, err := h.customerClient.AssignRole(c.Request.Context(), pReq)
+	if err != nil {
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	dto := &customer_response.CustomerResponse{
+		Id:             pRes.Id,
+		Role:           pRes.Role,
+		RoleName:       rbac.Role(pRes.Role).String(),
+		Name:           pRes.Name,
+		Email:          pRes.Email,
+		PhoneNumber:    pRes.PhoneNumber,
+		DateOfBith:     pRes.DateOfBith,
+		IdentityCard:   pRes.IdentityCard,
+		Address:        pRes.Address,
+		MembershipCard: pRes.MembershipCard,
+		Status:         pRes.Status,
+		EmailVerified:  pRes.EmailVerifiedAt != nil,
+		PhoneVerified:  pRes.PhoneVerifiedAt != nil,
+		LoyaltyTier:    pRes.LoyaltyTier,
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusText(http.StatusOK),
+		"payload": dto,
+	})
 }
-func Decode(s string) []byte {
-	data, err := base64.StdEncoding.DecodeString(s)
+
+func (h *customerHandler) SearchCustomer(c *gin.Context) {
+	req := customer_request.SearchCustomerRequest{}
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		validation.AbortWithBindError(c, err)
+		return
+	}
+
+	pRes, err := h.customerClient.SearchCustomer(c.Request.Context(), &protobuf.SearchCustomerRequest{
+		Name:           req.Name,
+		Email:          req.Email,
+		EmailPrefix:    req.EmailPrefix,
+		PhoneNumber:    req.PhoneNumber,
+		PhoneSuffix:    req.PhoneSuffix,
+		IdentityCard:   req.IdentityCard,
+		IncludeDeleted: req.IncludeDeleted,
+		PageSize:       req.PageSize,
+		PageToken:      req.PageToken,
+		OrderBy:        req.OrderBy,
+	})
 	if err != nil {
-		panic(err)
+		apperror.AbortWithRPCError(c, err)
+		return
 	}
-	return data
+
+	dtos := []*customer_response.CustomerResponse{}
+	for _, customer := range pRes.Customer {
+		dtos = append(dtos, toCustomerResponse(customer))
+	}
+
+	pagination.SetLinkHeader(c, pRes.NextPageToken)
+	c.JSON(http.StatusOK, gin.H{
+		"status":        http.StatusText(http.StatusOK),
+		"payload":       dtos,
+		"nextPageToken": pRes.NextPageToken,
+		"totalSize":     pRes.TotalSize,
+	})
 }
 
-// Encrypt method is to encrypt or hide any classified text
-func Encrypt(textStr string) (string, error) {
-	block, err := aes.NewCipher([]byte(MySecret))
+func (h *customerHandler) MergeCustomers(c *gin.Context) {
+	req := customer_request.MergeCustomersRequest{}
+
+	if err := c.ShouldBind(&req); err != nil {
+		validation.AbortWithBindError(c, err)
+		return
+	}
+
+	pReq := &protobuf.MergeCustomersRequest{
+		TargetId:  req.TargetId,
+		SourceIds: req.SourceIds,
+	}
+
+	pRes, err := h.customerClient.MergeCustomers(c.Request.Context(), pReq)
 	if err != nil {
-		return "", err
+		apperror.AbortWithRPCError(c, err)
+		return
 	}
-	plainText := []byte(textStr)
-	cfb := cipher.NewCFBEncrypter(block, bytes)
-	cipherText := make([]byte, len(plainText))
-	cfb.XORKeyStream(cipherText, plainText)
-	return Encode(cipherText), nil
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": http.StatusText(http.StatusOK),
+		"payload": &customer_response.MergeCustomersResponse{
+			Customer:      toCustomerResponse(pRes.Customer),
+			MovedBookings: pRes.MovedBookings,
+		},
+	})
 }
 
-// Decrypt method is to extract back the encrypted text
-func Decrypt(textStr string) (string, error) {
-	block, err := aes.NewCipher([]byte(MySecret))
+func (h *customerHandler) FindDuplicateCustomers(c *gin.Context) {
+	req := customer_request.DuplicateCustomersRequest{}
+
+	if err := c.ShouldBind(&req); err != nil {
+		validation.AbortWithBindError(c, err)
+		return
+	}
+
+	pRes, err := h.customerClient.FindDuplicateCustomers(c.Request.Context(), &protobuf.DuplicateCustomersRequest{
+		Limit: req.Limit,
+	})
 	if err != nil {
-		return "", err
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	dto := []*customer_response.DuplicateGroupResponse{}
+	for _, group := range pRes.Group {
+		customers := []*customer_response.CustomerResponse{}
+		for _, customer := range group.Customer {
+			customers = append(customers, toCustomerResponse(customer))
+		}
+
+		dto = append(dto, &customer_response.DuplicateGroupResponse{
+			MatchField: group.MatchField,
+			Customers:  customers,
+		})
 	}
-	cipherText := Decode(textStr)
-	cfb := cipher.NewCFBDecrypter(block, bytes)
-	plainText := make([]byte, len(cipherText))
-	cfb.XORKeyStream(plainText, cipherText)
-	return string(plainText), nil
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusText(http.StatusOK),
+		"payload": dto,
+	})
 }
-func validateEmail(email string) bool {
-	_, err := mail.ParseAddress(email)
-	return err == nil
+
+func (h *customerHandler) ListTravellers(c *gin.Context) {
+	id := c.Param("customerId")
+	if _, err := apperror.ParseID("customerId", id); err != nil {
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	pRes, err := h.customerClient.ListTravellers(c.Request.Context(), &protobuf.CustomerParamId{
+		Id: id,
+	})
+	if err != nil {
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	dto := []*customer_response.TravellerResponse{}
+	for _, traveller := range pRes.Traveller {
+		dto = append(dto, toTravellerResponse(traveller))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusText(http.StatusOK),
+		"payload": dto,
+	})
 }
-func isInt(s string) bool {
-	for _, c := range s {
-		if !unicode.IsDigit(c) {
-			return false
-		}
+
+func (h *customerHandler) CreateTraveller(c *gin.Context) {
+	req := customer_request.CreateTravellerRequest{}
+
+	if err := c.ShouldBind(&req); err != nil {
+		validation.AbortWithBindError(c, err)
+		return
+	}
+
+	pRes, err := h.customerClient.CreateTraveller(c.Request.Context(), &protobuf.Traveller{
+		CustomerId:     req.CustomerId,
+		Name:           req.Name,
+		DateOfBirth:    req.DateOfBirth,
+		DocumentType:   req.DocumentType,
+		DocumentNumber: req.DocumentNumber,
+		DocumentExpiry: req.DocumentExpiry,
+		Nationality:    req.Nationality,
+	})
+	if err != nil {
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusText(http.StatusOK),
+		"payload": toTravellerResponse(pRes),
+	})
+}
+
+func (h *customerHandler) UpdateTraveller(c *gin.Context) {
+	req := customer_request.UpdateTravellerRequest{}
+
+	if err := c.ShouldBind(&req); err != nil {
+		validation.AbortWithBindError(c, err)
+		return
+	}
+
+	pRes, err := h.customerClient.UpdateTraveller(c.Request.Context(), &protobuf.Traveller{
+		Id:             req.Id,
+		Name:           req.Name,
+		DateOfBirth:    req.DateOfBirth,
+		DocumentType:   req.DocumentType,
+		DocumentNumber: req.DocumentNumber,
+		DocumentExpiry: req.DocumentExpiry,
+		Nationality:    req.Nationality,
+	})
+	if err != nil {
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusText(http.StatusOK),
+		"payload": toTravellerResponse(pRes),
+	})
+}
+
+func (h *customerHandler) DeleteTraveller(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := apperror.ParseID("id", id); err != nil {
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	pRes, err := h.customerClient.DeleteTraveller(c.Request.Context(), &protobuf.TravellerParamId{
+		Id: id,
+	})
+	if err != nil {
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusText(http.StatusOK),
+		"payload": toTravellerResponse(pRes),
+	})
+}
+
+func (h *customerHandler) ExportCustomerData(c *gin.Context) {
+	id := c.Param("customerId")
+	if _, err := apperror.ParseID("customerId", id); err != nil {
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	req := customer_request.ExportCustomerDataRequest{}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		validation.AbortWithBindError(c, err)
+		return
+	}
+
+	pRes, err := h.customerClient.ExportCustomerData(c.Request.Context(), &protobuf.ExportCustomerDataRequest{
+		CustomerId: id,
+		Format:     req.Format,
+	})
+	if err != nil {
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", pRes.FileName))
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, pRes.ContentType, pRes.Content)
+}
+
+func (h *customerHandler) EraseCustomer(c *gin.Context) {
+	req := customer_request.EraseCustomerRequest{}
+
+	if err := c.ShouldBind(&req); err != nil {
+		validation.AbortWithBindError(c, err)
+		return
+	}
+
+	pRes, err := h.customerClient.EraseCustomer(c.Request.Context(), &protobuf.EraseCustomerRequest{
+		CustomerId: req.CustomerId,
+		Reason:     req.Reason,
+	})
+	if err != nil {
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": http.StatusText(http.StatusOK),
+		"payload": &customer_response.EraseCustomerResponse{
+			CustomerId:           pRes.CustomerId,
+			ErasedAt:             pRes.ErasedAt.AsTime(),
+			AnonymizedPassengers: pRes.AnonymizedPassengers,
+			DeletedTravellers:    pRes.DeletedTravellers,
+		},
+	})
+}
+
+func (h *customerHandler) DeactivateCustomer(c *gin.Context) {
+	req := customer_request.DeactivateCustomerRequest{}
+
+	if err := c.ShouldBind(&req); err != nil {
+		validation.AbortWithBindError(c, err)
+		return
+	}
+
+	pRes, err := h.customerClient.DeactivateCustomer(c.Request.Context(), &protobuf.DeactivateCustomerRequest{
+		CustomerId: req.CustomerId,
+		Reason:     req.Reason,
+	})
+	if err != nil {
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusText(http.StatusOK),
+		"payload": toCustomerResponse(pRes),
+	})
+}
+
+func (h *customerHandler) GetNotificationPreferences(c *gin.Context) {
+	id := c.Param("customerId")
+	if _, err := apperror.ParseID("customerId", id); err != nil {
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	pRes, err := h.customerClient.GetNotificationPreferences(c.Request.Context(), &protobuf.CustomerParamId{
+		Id: id,
+	})
+	if err != nil {
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusText(http.StatusOK),
+		"payload": toNotificationPreferencesResponse(pRes),
+	})
+}
+
+func (h *customerHandler) UpdateNotificationPreferences(c *gin.Context) {
+	req := customer_request.NotificationPreferencesRequest{}
+
+	if err := c.ShouldBind(&req); err != nil {
+		validation.AbortWithBindError(c, err)
+		return
+	}
+
+	pRes, err := h.customerClient.UpdateNotificationPreferences(c.Request.Context(), &protobuf.NotificationPreferences{
+		CustomerId:     req.CustomerId,
+		EmailEnabled:   *req.EmailEnabled,
+		SmsEnabled:     *req.SmsEnabled,
+		PushEnabled:    *req.PushEnabled,
+		BookingUpdates: *req.BookingUpdates,
+		FlightChanges:  *req.FlightChanges,
+		Promotions:     *req.Promotions,
+		Language:       req.Language,
+	})
+	if err != nil {
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusText(http.StatusOK),
+		"payload": toNotificationPreferencesResponse(pRes),
+	})
+}
+
+func toNotificationPreferencesResponse(pRes *protobuf.NotificationPreferences) *customer_response.NotificationPreferencesResponse {
+	res := &customer_response.NotificationPreferencesResponse{
+		CustomerId:     pRes.CustomerId,
+		EmailEnabled:   pRes.EmailEnabled,
+		SmsEnabled:     pRes.SmsEnabled,
+		PushEnabled:    pRes.PushEnabled,
+		BookingUpdates: pRes.BookingUpdates,
+		FlightChanges:  pRes.FlightChanges,
+		Promotions:     pRes.Promotions,
+		Language:       pRes.Language,
+		Consents:       []*customer_response.ConsentResponse{},
 	}
-	return true
+
+	if pRes.UpdatedAt != nil {
+		updatedAt := pRes.UpdatedAt.AsTime()
+		res.UpdatedAt = &updatedAt
+	}
+
+	for _, consent := range pRes.Consent {
+		res.Consents = append(res.Consents, &customer_response.ConsentResponse{
+			Purpose:   consent.Purpose,
+			Granted:   consent.Granted,
+			ActorId:   consent.ActorId,
+			CreatedAt: consent.CreatedAt.AsTime(),
+		})
+	}
+
+	return res
 }
-func isDateValue(stringDate string) bool {
-	layout := "2006-01-02T15:04:05.000Z"
-	_, err := time.Parse(layout, stringDate)
-	return err == nil
+
+func toTravellerResponse(pRes *protobuf.Traveller) *customer_response.TravellerResponse {
+	return &customer_response.TravellerResponse{
+		Id:             pRes.Id,
+		CustomerId:     pRes.CustomerId,
+		Name:           pRes.Name,
+		DateOfBirth:    pRes.DateOfBirth,
+		DocumentType:   pRes.DocumentType,
+		DocumentNumber: pRes.DocumentNumber,
+		DocumentExpiry: pRes.DocumentExpiry,
+		Nationality:    pRes.Nationality,
+	}
+}
+
+func toCustomerResponse(pRes *protobuf.Customer) *customer_response.CustomerResponse {
+	res := &customer_response.CustomerResponse{
+		Id:             pRes.Id,
+		Role:           pRes.Role,
+		RoleName:       rbac.Role(pRes.Role).String(),
+		Name:           pRes.Name,
+		Email:          pRes.Email,
+		PhoneNumber:    pRes.PhoneNumber,
+		DateOfBith:     pRes.DateOfBith,
+		IdentityCard:   pRes.IdentityCard,
+		Address:        pRes.Address,
+		MembershipCard: pRes.MembershipCard,
+		Status:         pRes.Status,
+		EmailVerified:  pRes.EmailVerifiedAt != nil,
+		PhoneVerified:  pRes.PhoneVerifiedAt != nil,
+		LoyaltyTier:    pRes.LoyaltyTier,
+	}
+
+	if pRes.DeletedAt != nil {
+		deletedAt := pRes.DeletedAt.AsTime()
+		res.DeletedAt = &deletedAt
+	}
+
+	return res
 }
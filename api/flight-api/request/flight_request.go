@@ -4,8 +4,8 @@ type CreateFlightRequest struct {
 	Name          string `json:"name" binding:"required"`
 	From          string `json:"from" binding:"required"`
 	To            string `json:"to" binding:"required"`
-	DepartDate    string `json:"departDate" binding:"required"`
-	DepartTime    string `json:"departTime" binding:"required"`
+	DepartDate    string `json:"departDate" binding:"required,datetime=2006/01/02"`
+	DepartTime    string `json:"departTime" binding:"required,datetime=15:04:05"`
 	Status        string `json:"status"`
 	AvailableSlot int32  `json:"slot"`
 }
@@ -15,16 +15,19 @@ type UpdateFlightRequest struct {
 	Name          string `json:"name"`
 	From          string `json:"from"`
 	To            string `json:"to"`
-	DepartDate    string `json:"departDate"`
-	DepartTime    string `json:"departTime"`
+	DepartDate    string `json:"departDate" binding:"omitempty,datetime=2006/01/02"`
+	DepartTime    string `json:"departTime" binding:"omitempty,datetime=15:04:05"`
 	Status        string `json:"status"`
 	AvailableSlot int32  `json:"slot"`
 }
 
 type SearchFlightRequest struct {
-	Name     string `json:"name"`
-	From     string `json:"from"`
-	To       string `json:"to"`
-	FromDate string `json:"fromDate"`
-	ToDate   string `json:"toDate"`
+	Name string `json:"name"`
+	From string `json:"from"`
+	To   string `json:"to"`
+	// YYYY-MM-DD, both days included
+	FromDate string `json:"fromDate" binding:"omitempty,isodate"`
+	ToDate   string `json:"toDate" binding:"omitempty,isodate"`
+	// Admin only
+	IncludeDeleted bool `json:"includeDeleted" form:"includeDeleted"`
 }
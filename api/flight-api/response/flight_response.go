@@ -21,4 +21,5 @@ type FlightResponse struct {
 	DepatureDate  string `json:"depature_date"`
 	CreatedAt     string `json:"created_at"`
 	UpdatedAt     string `json:"updated_at"`
+	DeletedAt     string `json:"deleted_at,omitempty"`
 }
@@ -3,13 +3,16 @@ package flight_handler
 import (
 	flight_request "mock-golang/api/flight-api/request"
 	flight_response "mock-golang/api/flight-api/response"
+	"mock-golang/apperror"
+	"mock-golang/helper"
+	"mock-golang/pagination"
 	"mock-golang/protobuf"
+	"mock-golang/validation"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/go-playground/validator/v10"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -18,6 +21,7 @@ type FlightHandler interface {
 	UpdateFlight(c *gin.Context)
 	SearchFlight(c *gin.Context)
 	SearchFlightById(c *gin.Context)
+	DeleteFlight(c *gin.Context)
 }
 
 type flightHandler struct {
@@ -26,12 +30,8 @@ type flightHandler struct {
 
 func (h *flightHandler) SearchFlightById(c *gin.Context) {
 	id := c.Param("id")
-	if len(id) < 1 {
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"status": http.StatusText(http.StatusBadRequest),
-			"error":  "id invalid",
-		})
-
+	if _, err := apperror.ParseID("id", id); err != nil {
+		apperror.AbortWithRPCError(c, err)
 		return
 	}
 	pReqFlight := &protobuf.FlightParamId{
@@ -40,10 +40,7 @@ func (h *flightHandler) SearchFlightById(c *gin.Context) {
 	pResFlight, err := h.flightClient.FindById(c.Request.Context(), pReqFlight)
 
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"status": http.StatusText(http.StatusInternalServerError),
-			"error":  err.Error(),
-		})
+		apperror.AbortWithRPCError(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{
@@ -62,24 +59,7 @@ func (h *flightHandler) CreateFlight(c *gin.Context) {
 	req := flight_request.CreateFlightRequest{}
 
 	if err := c.ShouldBind(&req); err != nil {
-		if validateErrors, ok := err.(validator.ValidationErrors); ok {
-			errMessages := make([]string, 0)
-			for _, v := range validateErrors {
-				errMessages = append(errMessages, v.Error())
-			}
-
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-				"status": http.StatusText(http.StatusBadRequest),
-				"error":  errMessages,
-			})
-			return
-		}
-
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"status": http.StatusText(http.StatusBadRequest),
-			"error":  err.Error(),
-		})
-
+		validation.AbortWithBindError(c, err)
 		return
 	}
 
@@ -96,10 +76,7 @@ func (h *flightHandler) CreateFlight(c *gin.Context) {
 
 	pRes, err := h.flightClient.CreateFlight(c.Request.Context(), pReq)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"status": http.StatusText(http.StatusInternalServerError),
-			"error":  err.Error(),
-		})
+		apperror.AbortWithRPCError(c, err)
 		return
 	}
 
@@ -123,24 +100,7 @@ func (h *flightHandler) UpdateFlight(c *gin.Context) {
 	req := flight_request.UpdateFlightRequest{}
 	//fmt.Printf("id", c.Param("id"))
 	if err := c.ShouldBind(&req); err != nil {
-		if validateErrors, ok := err.(validator.ValidationErrors); ok {
-			errMessages := make([]string, 0)
-			for _, v := range validateErrors {
-				errMessages = append(errMessages, v.Error())
-			}
-
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-				"status": http.StatusText(http.StatusBadRequest),
-				"error":  errMessages,
-			})
-			return
-		}
-
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"status": http.StatusText(http.StatusBadRequest),
-			"error":  err.Error(),
-		})
-
+		validation.AbortWithBindError(c, err)
 		return
 	}
 
@@ -160,10 +120,7 @@ func (h *flightHandler) UpdateFlight(c *gin.Context) {
 
 	pRes, err := h.flightClient.UpdateFlight(c.Request.Context(), pReq)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"status": http.StatusText(http.StatusInternalServerError),
-			"error":  err.Error(),
-		})
+		apperror.AbortWithRPCError(c, err)
 		return
 	}
 
@@ -178,44 +135,32 @@ func (h *flightHandler) SearchFlight(c *gin.Context) {
 	req := flight_request.SearchFlightRequest{}
 
 	if err := c.ShouldBind(&req); err != nil {
-		if validateErrors, ok := err.(validator.ValidationErrors); ok {
-			errMessages := make([]string, 0)
-			for _, v := range validateErrors {
-				errMessages = append(errMessages, v.Error())
-			}
-
-			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-				"status": http.StatusText(http.StatusBadRequest),
-				"error":  errMessages,
-			})
-			return
-		}
-		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
-			"status": http.StatusText(http.StatusBadRequest),
-			"error":  err.Error(),
-		})
-
+		validation.AbortWithBindError(c, err)
 		return
 	}
 
-	// pFromDate, _ := time.Parse("2006/01/02 15:04:05", req.FromDate)
-	// pToDate, _ := time.Parse("2006/01/02 15:04:05", req.ToDate)
+	page := pagination.Query{}
+	if err := c.ShouldBindQuery(&page); err != nil {
+		validation.AbortWithBindError(c, err)
+		return
+	}
 
 	pReq := &protobuf.SearchFlightRequest{
-		Name: req.Name,
-		From: req.From,
-		To:   req.To,
-		// FromDate: timestamppb.New(pFromDate),
-		// ToDate:   timestamppb.New(pToDate),
+		Name:      req.Name,
+		From:      req.From,
+		To:        req.To,
+		PageSize:  page.PageSize,
+		PageToken: page.PageToken,
+		OrderBy:   page.OrderBy,
 	}
 
+	pReq.IncludeDeleted = req.IncludeDeleted
+	pReq.FromDate, pReq.ToDate = helper.DateRange(req.FromDate, req.ToDate)
+
 	pRes, err := h.flightClient.SearchFlight(c.Request.Context(), pReq)
 
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"status": http.StatusText(http.StatusInternalServerError),
-			"error":  err.Error(),
-		})
+		apperror.AbortWithRPCError(c, err)
 		return
 	}
 
@@ -227,9 +172,12 @@ func (h *flightHandler) SearchFlight(c *gin.Context) {
 		dtos = append(dtos, dto)
 	}
 
+	pagination.SetLinkHeader(c, pRes.NextPageToken)
 	c.JSON(http.StatusOK, gin.H{
-		"status":  http.StatusText(http.StatusOK),
-		"payload": dtos,
+		"status":        http.StatusText(http.StatusOK),
+		"payload":       dtos,
+		"nextPageToken": pRes.NextPageToken,
+		"totalSize":     pRes.TotalSize,
 	})
 }
 
@@ -246,5 +194,30 @@ func ToApiResponse(pRes *protobuf.Flight) *flight_response.FlightResponse {
 		UpdatedAt:     pRes.UpdatedAt.AsTime().Format("2006-01-02 15:04:05"),
 	}
 
+	if pRes.DeletedAt != nil {
+		res.DeletedAt = pRes.DeletedAt.AsTime().Format("2006-01-02 15:04:05")
+	}
+
 	return res
 }
+
+func (h *flightHandler) DeleteFlight(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := apperror.ParseID("id", id); err != nil {
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	pRes, err := h.flightClient.DeleteFlight(c.Request.Context(), &protobuf.FlightParamId{
+		Id: id,
+	})
+	if err != nil {
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusText(http.StatusOK),
+		"payload": ToApiResponse(pRes),
+	})
+}
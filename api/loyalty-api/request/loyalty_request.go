@@ -0,0 +1,18 @@
+package loyalty_request
+
+import "time"
+
+type StatementRequest struct {
+	CustomerId string     `json:"customerId" binding:"required"`
+	FromDate   *time.Time `json:"fromDate"`
+	ToDate     *time.Time `json:"toDate"`
+	Limit      int32      `json:"limit" binding:"omitempty,min=1,max=200"`
+}
+
+type RedeemPointsRequest struct {
+	CustomerId  string `json:"customerId" binding:"required"`
+	Points      int64  `json:"points" binding:"required,min=1"`
+	Description string `json:"description"`
+	// RequestId is generated by the client once per redemption and sent again on retries
+	RequestId string `json:"requestId" binding:"required,max=64"`
+}
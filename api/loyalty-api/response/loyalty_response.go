@@ -0,0 +1,31 @@
+package loyalty_response
+
+import "time"
+
+type BalanceResponse struct {
+	CustomerId       string `json:"customerId"`
+	Balance          int64  `json:"balance"`
+	Tier             string `json:"tier"`
+	QualifyingPoints int64  `json:"qualifyingPoints"`
+	NextTier         string `json:"nextTier,omitempty"`
+	PointsToNextTier int64  `json:"pointsToNextTier,omitempty"`
+}
+
+type TransactionResponse struct {
+	Id          string    `json:"id"`
+	BookingId   string    `json:"bookingId,omitempty"`
+	Kind        string    `json:"kind"`
+	Points      int64     `json:"points"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type StatementResponse struct {
+	Balance      *BalanceResponse       `json:"balance"`
+	Transactions []*TransactionResponse `json:"transactions"`
+}
+
+type RedeemPointsResponse struct {
+	Transaction *TransactionResponse `json:"transaction"`
+	Balance     *BalanceResponse     `json:"balance"`
+}
@@ -0,0 +1,139 @@
+package loyalty_handler
+
+import (
+	loyalty_request "mock-golang/api/loyalty-api/request"
+	loyalty_response "mock-golang/api/loyalty-api/response"
+	"mock-golang/apperror"
+	"mock-golang/protobuf"
+	"mock-golang/validation"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type LoyaltyHandler interface {
+	GetBalance(c *gin.Context)
+	GetStatement(c *gin.Context)
+	RedeemPoints(c *gin.Context)
+}
+
+type loyaltyHandler struct {
+	loyaltyClient protobuf.RPCLoyaltyClient
+}
+
+func NewLoyaltyHandler(loyaltyClient protobuf.RPCLoyaltyClient) LoyaltyHandler {
+	return &loyaltyHandler{
+		loyaltyClient: loyaltyClient,
+	}
+}
+
+func (h *loyaltyHandler) GetBalance(c *gin.Context) {
+	id := c.Param("customerId")
+	if _, err := apperror.ParseID("customerId", id); err != nil {
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	pRes, err := h.loyaltyClient.GetBalance(c.Request.Context(), &protobuf.LoyaltyParamId{
+		CustomerId: id,
+	})
+	if err != nil {
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusText(http.StatusOK),
+		"payload": toBalanceResponse(pRes),
+	})
+}
+
+func (h *loyaltyHandler) GetStatement(c *gin.Context) {
+	req := loyalty_request.StatementRequest{}
+
+	if err := c.ShouldBind(&req); err != nil {
+		validation.AbortWithBindError(c, err)
+		return
+	}
+
+	pReq := &protobuf.StatementRequest{
+		CustomerId: req.CustomerId,
+		Limit:      req.Limit,
+	}
+	if req.FromDate != nil {
+		pReq.FromDate = timestamppb.New(*req.FromDate)
+	}
+	if req.ToDate != nil {
+		pReq.ToDate = timestamppb.New(*req.ToDate)
+	}
+
+	pRes, err := h.loyaltyClient.GetStatement(c.Request.Context(), pReq)
+	if err != nil {
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	dto := &loyalty_response.StatementResponse{
+		Balance:      toBalanceResponse(pRes.Balance),
+		Transactions: []*loyalty_response.TransactionResponse{},
+	}
+	for _, transaction := range pRes.Transaction {
+		dto.Transactions = append(dto.Transactions, toTransactionResponse(transaction))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusText(http.StatusOK),
+		"payload": dto,
+	})
+}
+
+func (h *loyaltyHandler) RedeemPoints(c *gin.Context) {
+	req := loyalty_request.RedeemPointsRequest{}
+
+	if err := c.ShouldBind(&req); err != nil {
+		validation.AbortWithBindError(c, err)
+		return
+	}
+
+	pRes, err := h.loyaltyClient.RedeemPoints(c.Request.Context(), &protobuf.RedeemPointsRequest{
+		CustomerId:  req.CustomerId,
+		Points:      req.Points,
+		Description: req.Description,
+		RequestId:   req.RequestId,
+	})
+	if err != nil {
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": http.StatusText(http.StatusOK),
+		"payload": &loyalty_response.RedeemPointsResponse{
+			Transaction: toTransactionResponse(pRes.Transaction),
+			Balance:     toBalanceResponse(pRes.Balance),
+		},
+	})
+}
+
+func toBalanceResponse(pRes *protobuf.LoyaltyBalance) *loyalty_response.BalanceResponse {
+	return &loyalty_response.BalanceResponse{
+		CustomerId:       pRes.CustomerId,
+		Balance:          pRes.Balance,
+		Tier:             pRes.Tier,
+		QualifyingPoints: pRes.QualifyingPoints,
+		NextTier:         pRes.NextTier,
+		PointsToNextTier: pRes.PointsToNextTier,
+	}
+}
+
+func toTransactionResponse(pRes *protobuf.LoyaltyTransaction) *loyalty_response.TransactionResponse {
+	return &loyalty_response.TransactionResponse{
+		Id:          pRes.Id,
+		BookingId:   pRes.BookingId,
+		Kind:        pRes.Kind,
+		Points:      pRes.Points,
+		Description: pRes.Description,
+		CreatedAt:   pRes.CreatedAt.AsTime(),
+	}
+}
@@ -1,63 +1,99 @@
 package main
 
 import (
-	booking_handler "mock-golang/api/booking-api/service"
-	customer_handler "mock-golang/api/customer-api/service"
-	flight_handler "mock-golang/api/flight-api/service"
-	"mock-golang/middleware"
-	"mock-golang/protobuf"
+	"context"
+	"flag"
+	"mock-golang/helper"
+	"mock-golang/rbac"
+	"mock-golang/tracing"
 	"net/http"
 	"os"
 
-	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 )
 
+var (
+	configFile = flag.String("config-file", "../helper/config.yml", "Location of config file")
+)
+
 func main() {
-	//Create grpc client connect
-	conn, err := grpc.Dial(":9112", grpc.WithInsecure())
+	// Parsed here rather than in init, go test passes its own flags to the package
+	flag.Parse()
+
+	err := helper.AutoBindConfig(*configFile)
+	if err != nil {
+		panic(err)
+	}
+
+	// Sent on the calls the gateway makes on its own behalf
+	if _, err := rbac.LoadServiceKey(); err != nil {
+		panic(err)
+	}
+
+	shutdownTracing, err := tracing.Init("flight-booking-api")
 	if err != nil {
 		panic(err)
 	}
 
-	//Singleton
-	customerClient := protobuf.NewRPCCustomerClient(conn)
-	bookingClient := protobuf.NewRPCBookingClient(conn)
-	flightClient := protobuf.NewRPCFlightClient(conn)
+	//Create grpc client connect, the client interceptor forwards the trace context
+	conn, err := grpc.Dial(":9112", grpc.WithInsecure(), grpc.WithUnaryInterceptor(otelgrpc.UnaryClientInterceptor()))
+	if err != nil {
+		panic(err)
+	}
 
 	logger, _ := zap.NewProduction()
 	defer logger.Sync()
+	// zap.L() for code outside a request, a request carries its own logger, see logging.FromContext
+	zap.ReplaceGlobals(logger)
 
-	//Handler for GIN Gonic
-	hCustomer := customer_handler.NewCustomerHandler(customerClient)
-	hFlight := flight_handler.NewFlightHandler(flightClient)
-	hBooking := booking_handler.NewBookingHandler(bookingClient, customerClient, flightClient)
 	os.Setenv("GIN_MODE", "debug")
-	g := gin.Default()
-	g.Use(middleware.LoggingMiddleware(logger))
-
-	//Create routes
-	gr := g.Group("/v1/api")
-
-	// API Customer
-	gr.POST("/customer", hCustomer.CreateCustomer)
-	gr.PUT("/customer", hCustomer.UpdateCustomer)
-	gr.POST("/customer/changePassword", hCustomer.ChangePassword)
-	gr.POST("/customer/viewBookingHistory", hBooking.BookingHistory)
-	gr.POST("/customer/searchBooking", hBooking.SearchBooking)
-
-	// API Booking
-	gr.POST("/booking", hBooking.CustomerBooking)
-	gr.POST("/booking/guest", hBooking.GuestBooking)
-	gr.POST("/booking/cancel", hBooking.CancelBooking)
-
-	// API Flight
-	gr.POST("/flight", hFlight.CreateFlight)
-	gr.PUT("/flight", hFlight.UpdateFlight)
-	gr.GET("/flight/search", hFlight.SearchFlight)
-	gr.GET("/flight/:id", hFlight.SearchFlightById)
+	g, err := newRouter(conn, logger)
+	if err != nil {
+		panic(err)
+	}
+
+	srv := &http.Server{Addr: ":8080", Handler: g}
+
+	ctx, stop := helper.ShutdownContext()
+	defer stop()
 
 	//Listen and serve
-	http.ListenAndServe(":8080", g)
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err = <-serveErr:
+		logger.Error("http server stopped", zap.Error(err))
+	case <-ctx.Done():
+		stop()
+		logger.Info("shutting down, draining in-flight requests", zap.Duration("timeout", helper.ShutdownTimeout()))
+
+		// New connections are refused at once, the running requests get the timeout to finish
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), helper.ShutdownTimeout())
+		defer cancel()
+		if errShutdown := srv.Shutdown(shutdownCtx); errShutdown != nil {
+			logger.Warn("shutdown timeout reached, closing the remaining connections", zap.Error(errShutdown))
+			srv.Close()
+		}
+	}
+
+	// The gRPC connection outlives the handlers that use it
+	if errClose := conn.Close(); errClose != nil {
+		logger.Error("close grpc connection", zap.Error(errClose))
+	}
+	flushCtx, cancelFlush := context.WithTimeout(context.Background(), helper.ShutdownTimeout())
+	defer cancelFlush()
+	if errTracing := shutdownTracing(flushCtx); errTracing != nil {
+		logger.Error("flush traces", zap.Error(errTracing))
+	}
+	logger.Info("stopped")
+
+	if err != nil {
+		logger.Sync()
+		os.Exit(1)
+	}
 }
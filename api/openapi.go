@@ -0,0 +1,247 @@
+package main
+
+import (
+	auth_request "mock-golang/api/auth-api/request"
+	auth_response "mock-golang/api/auth-api/response"
+	booking_request "mock-golang/api/booking-api/request"
+	customer_request "mock-golang/api/customer-api/request"
+	customer_response "mock-golang/api/customer-api/response"
+	flight_request "mock-golang/api/flight-api/request"
+	flight_response "mock-golang/api/flight-api/response"
+	loyalty_request "mock-golang/api/loyalty-api/request"
+	loyalty_response "mock-golang/api/loyalty-api/response"
+	organization_request "mock-golang/api/organization-api/request"
+	organization_response "mock-golang/api/organization-api/response"
+	"mock-golang/health"
+	"mock-golang/openapi"
+	"mock-golang/protobuf"
+	"mock-golang/rbac"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+var apiInfo = openapi.Info{
+	Title:       "Flight booking API",
+	Version:     "1.0.0",
+	Description: "Gateway of the flight booking services. Errors answer {status, code, error, field}.",
+}
+
+var (
+	customerWrite = []rbac.Permission{rbac.PermCustomerWriteOwn, rbac.PermCustomerWriteAny}
+	customerRead  = []rbac.Permission{rbac.PermCustomerReadOwn, rbac.PermCustomerReadAny}
+	bookingRead   = []rbac.Permission{rbac.PermBookingReadOwn, rbac.PermBookingReadAny}
+	bookingWrite  = []rbac.Permission{rbac.PermBookingWriteOwn, rbac.PermBookingWriteAny}
+	approver      = []rbac.Permission{rbac.PermBookingWriteOwn, rbac.PermOrganizationManage}
+)
+
+// transcodedServices are the services newRESTProxy registers
+var transcodedServices = []protoreflect.ServiceDescriptor{
+	protobuf.File_rpc_customer_proto.Services().ByName("RPCCustomer"),
+	protobuf.File_rpc_booking_proto.Services().ByName("RPCBooking"),
+	protobuf.File_rpc_flight_proto.Services().ByName("RPCFlight"),
+}
+
+// operations documents the routes of newRouter, keyed by method and gin path
+var operations = map[string]openapi.Operation{
+	"GET /metrics":             {Summary: "Prometheus metrics", ContentType: "text/plain"},
+	"GET /healthz":             {Summary: "Liveness, answers while the gateway runs", ContentType: "application/json"},
+	"GET /readyz":              {Summary: "Readiness, 503 while a gRPC service or its database is down", Payload: health.Report{}},
+	"GET /v1/api/openapi.json": {Summary: "This OpenAPI document", ContentType: "application/json"},
+	"GET /v1/api/docs":         {Summary: "API docs UI", ContentType: "text/html"},
+
+	// Auth
+	"POST /v1/api/auth/login": {
+		Summary: "Log in with email and password", Body: auth_request.LoginRequest{}, Payload: auth_response.TokenResponse{},
+	},
+	"POST /v1/api/auth/refresh": {
+		Summary: "Exchange a refresh token for a new token pair", Body: auth_request.RefreshTokenRequest{}, Payload: auth_response.TokenResponse{},
+	},
+	"POST /v1/api/auth/forgot-password": {
+		Summary: "Send a password reset link", Body: auth_request.ForgotPasswordRequest{}, Payload: auth_response.AuthResponse{},
+	},
+	"POST /v1/api/auth/reset-password": {
+		Summary: "Set a new password with a reset token", Body: auth_request.ResetPasswordRequest{}, Payload: auth_response.AuthResponse{},
+	},
+	"POST /v1/api/auth/verify-email/send": {
+		Summary: "Send an email verification link", Permissions: customerWrite,
+		Body: auth_request.VerificationRequest{}, Payload: auth_response.AuthResponse{},
+	},
+	"POST /v1/api/auth/verify-email": {
+		Summary: "Verify the email with the link token", Body: auth_request.VerifyEmailRequest{}, Payload: auth_response.AuthResponse{},
+	},
+	"POST /v1/api/auth/verify-phone/send": {
+		Summary: "Send a phone verification code", Permissions: customerWrite,
+		Body: auth_request.VerificationRequest{}, Payload: auth_response.AuthResponse{},
+	},
+	"POST /v1/api/auth/verify-phone": {
+		Summary: "Verify the phone with the code", Permissions: customerWrite,
+		Body: auth_request.VerifyPhoneRequest{}, Payload: auth_response.AuthResponse{},
+	},
+	"POST /v1/api/auth/unlock-account": {
+		Summary: "Unlock an account locked after failed logins", Permissions: []rbac.Permission{rbac.PermAccountUnlock},
+		Body: auth_request.VerificationRequest{}, Payload: auth_response.AuthResponse{},
+	},
+	"POST /v1/api/auth/claim-account/send": {
+		Summary: "Send the link claiming a guest account", Body: auth_request.ForgotPasswordRequest{}, Payload: auth_response.AuthResponse{},
+	},
+	"POST /v1/api/auth/claim-account": {
+		Summary: "Claim a guest account by setting a password", Body: auth_request.ClaimAccountRequest{}, Payload: auth_response.TokenResponse{},
+	},
+
+	// Customer
+	"POST /v1/api/customer": {
+		Summary: "Register a customer", Body: customer_request.CreateCustomerRequest{}, Payload: customer_response.CustomerResponse{},
+	},
+	"PUT /v1/api/customer": {
+		Summary: "Update a customer", Permissions: customerWrite,
+		Body: customer_request.UpdateCustomerRequest{}, Payload: customer_response.CustomerResponse{},
+	},
+	"POST /v1/api/customer/changePassword": {
+		Summary: "Change the password", Permissions: customerWrite,
+		Body: customer_request.ChangePasswordRequest{}, Payload: protobuf.ChangePasswordResponse{},
+	},
+	"POST /v1/api/customer/role": {
+		Summary: "Assign a role", Permissions: []rbac.Permission{rbac.PermRoleAssign},
+		Body: customer_request.AssignRoleRequest{}, Payload: customer_response.CustomerResponse{},
+	},
+	"POST /v1/api/customer/merge": {
+		Summary: "Merge duplicate customers into a target", Permissions: []rbac.Permission{rbac.PermCustomerMerge},
+		Body: customer_request.MergeCustomersRequest{}, Payload: customer_response.MergeCustomersResponse{},
+	},
+	"GET /v1/api/customer/search": {
+		Summary: "Search customers by partial name, email prefix or phone suffix", Permissions: []rbac.Permission{rbac.PermCustomerReadAny},
+		Query: customer_request.SearchCustomerRequest{}, Payload: []customer_response.CustomerResponse{}, Paged: true,
+	},
+	"GET /v1/api/customer/duplicates": {
+		Summary: "Likely duplicate customers", Permissions: []rbac.Permission{rbac.PermCustomerReadAny},
+		Query: customer_request.DuplicateCustomersRequest{}, Payload: []customer_response.DuplicateGroupResponse{},
+	},
+	"GET /v1/api/customer/travellers/:customerId": {
+		Summary: "Saved travellers of a customer", Permissions: customerRead, Payload: []customer_response.TravellerResponse{},
+	},
+	"POST /v1/api/customer/traveller": {
+		Summary: "Save a traveller", Permissions: customerWrite,
+		Body: customer_request.CreateTravellerRequest{}, Payload: customer_response.TravellerResponse{},
+	},
+	"PUT /v1/api/customer/traveller": {
+		Summary: "Update a saved traveller", Permissions: customerWrite,
+		Body: customer_request.UpdateTravellerRequest{}, Payload: customer_response.TravellerResponse{},
+	},
+	"DELETE /v1/api/customer/traveller/:id": {
+		Summary: "Delete a saved traveller", Permissions: customerWrite, Payload: customer_response.TravellerResponse{},
+	},
+	"GET /v1/api/customer/notification-preferences/:customerId": {
+		Summary: "Notification preferences and consent history", Permissions: customerRead,
+		Payload: customer_response.NotificationPreferencesResponse{},
+	},
+	"PUT /v1/api/customer/notification-preferences": {
+		Summary: "Replace the notification preferences", Permissions: customerWrite,
+		Body: customer_request.NotificationPreferencesRequest{}, Payload: customer_response.NotificationPreferencesResponse{},
+	},
+	"GET /v1/api/customer/export/:customerId": {
+		Summary: "Download everything stored about a customer", Permissions: []rbac.Permission{rbac.PermCustomerExport},
+		Query: customer_request.ExportCustomerDataRequest{}, ContentType: "application/json or application/zip",
+	},
+	"POST /v1/api/customer/erase": {
+		Summary: "Erase the personal data of a customer", Permissions: []rbac.Permission{rbac.PermCustomerErase},
+		Body: customer_request.EraseCustomerRequest{}, Payload: customer_response.EraseCustomerResponse{},
+	},
+	"POST /v1/api/customer/deactivate": {
+		Summary: "Soft delete a customer", Permissions: []rbac.Permission{rbac.PermCustomerDelete},
+		Body: customer_request.DeactivateCustomerRequest{}, Payload: customer_response.CustomerResponse{},
+	},
+	"POST /v1/api/customer/viewBookingHistory": {
+		Summary: "Bookings of a customer", Permissions: bookingRead,
+		Body: booking_request.ViewBookingRequest{}, Payload: []protobuf.Booking{}, Paged: true,
+	},
+	"POST /v1/api/customer/searchBooking": {
+		Summary: "Search bookings", Permissions: bookingRead,
+		Body: booking_request.SearchBookingRequest{}, Payload: []protobuf.Booking{}, Paged: true,
+	},
+
+	// Booking
+	"POST /v1/api/booking": {
+		Summary: "Book seats for a customer", Permissions: bookingWrite,
+		Body: booking_request.CustomerBookingRequest{}, Payload: protobuf.Booking{},
+	},
+	"POST /v1/api/booking/guest": {
+		Summary: "Book seats without an account", Body: booking_request.GuestBookingRequest{}, Payload: protobuf.Booking{},
+	},
+	"POST /v1/api/booking/cancel": {
+		Summary: "Cancel a booking", Permissions: bookingWrite,
+		Body: booking_request.CancelBookingRequest{}, Payload: protobuf.Booking{},
+	},
+	"GET /v1/api/booking/approvals/:organizationId": {
+		Summary: "Bookings of an organization waiting for approval", Permissions: approver, Payload: []protobuf.Booking{},
+	},
+	"POST /v1/api/booking/approve": {
+		Summary: "Approve a booking breaking the travel policy", Permissions: approver,
+		Body: booking_request.BookingDecisionRequest{}, Payload: protobuf.Booking{},
+	},
+	"POST /v1/api/booking/reject": {
+		Summary: "Reject a booking breaking the travel policy", Permissions: approver,
+		Body: booking_request.BookingDecisionRequest{}, Payload: protobuf.Booking{},
+	},
+
+	// Flight
+	"POST /v1/api/flight": {
+		Summary: "Create a flight", Permissions: []rbac.Permission{rbac.PermFlightWrite},
+		Body: flight_request.CreateFlightRequest{}, Payload: flight_response.CreateFlightResponse{},
+	},
+	"PUT /v1/api/flight": {
+		Summary: "Update a flight", Permissions: []rbac.Permission{rbac.PermFlightWrite},
+		Body: flight_request.UpdateFlightRequest{}, Payload: flight_response.FlightResponse{},
+	},
+	"GET /v1/api/flight/search": {
+		Summary: "Search flights", Permissions: []rbac.Permission{rbac.PermFlightRead},
+		Query: flight_request.SearchFlightRequest{}, Payload: []flight_response.FlightResponse{}, Paged: true,
+	},
+	"GET /v1/api/flight/:id": {
+		Summary: "Get a flight", Permissions: []rbac.Permission{rbac.PermFlightRead}, Payload: protobuf.Flight{},
+	},
+	"DELETE /v1/api/flight/:id": {
+		Summary: "Soft delete a flight", Permissions: []rbac.Permission{rbac.PermFlightDelete}, Payload: flight_response.FlightResponse{},
+	},
+
+	// Loyalty
+	"GET /v1/api/loyalty/:customerId": {
+		Summary: "Loyalty balance and tier", Permissions: []rbac.Permission{rbac.PermLoyaltyReadOwn, rbac.PermLoyaltyReadAny},
+		Payload: loyalty_response.BalanceResponse{},
+	},
+	"POST /v1/api/loyalty/statement": {
+		Summary: "Loyalty balance and ledger entries", Permissions: []rbac.Permission{rbac.PermLoyaltyReadOwn, rbac.PermLoyaltyReadAny},
+		Body: loyalty_request.StatementRequest{}, Payload: loyalty_response.StatementResponse{},
+	},
+	"POST /v1/api/loyalty/redeem": {
+		Summary: "Redeem loyalty points", Permissions: []rbac.Permission{rbac.PermLoyaltyRedeemOwn, rbac.PermLoyaltyRedeemAny},
+		Body: loyalty_request.RedeemPointsRequest{}, Payload: loyalty_response.RedeemPointsResponse{},
+	},
+
+	// Organization
+	"POST /v1/api/organization": {
+		Summary: "Create a corporate organization", Permissions: []rbac.Permission{rbac.PermOrganizationManage},
+		Body: organization_request.CreateOrganizationRequest{}, Payload: organization_response.OrganizationResponse{},
+	},
+	"PUT /v1/api/organization": {
+		Summary: "Update the name and travel policy", Permissions: []rbac.Permission{rbac.PermOrganizationManage},
+		Body: organization_request.UpdateOrganizationRequest{}, Payload: organization_response.OrganizationResponse{},
+	},
+	"GET /v1/api/organization/:id": {
+		Summary: "Get an organization with its members", Permissions: []rbac.Permission{rbac.PermCustomerReadOwn, rbac.PermOrganizationManage},
+		Payload: organization_response.OrganizationResponse{},
+	},
+	"POST /v1/api/organization/member": {
+		Summary: "Add a member or change its role", Permissions: []rbac.Permission{rbac.PermOrganizationManage},
+		Body: organization_request.SetMemberRequest{}, Payload: organization_response.MemberResponse{},
+	},
+	"DELETE /v1/api/organization/:id/member/:customerId": {
+		Summary: "Remove a member", Permissions: []rbac.Permission{rbac.PermOrganizationManage},
+		Payload: organization_response.MemberResponse{},
+	},
+
+	// REST proxy, the paths come from the http options of transcodedServices
+	"GET /v2/*path":    {Summary: "Transcoded gRPC reads", Transcoded: true},
+	"POST /v2/*path":   {Summary: "Transcoded gRPC creates and actions", Transcoded: true},
+	"PUT /v2/*path":    {Summary: "Transcoded gRPC updates", Transcoded: true},
+	"DELETE /v2/*path": {Summary: "Transcoded gRPC deletes", Transcoded: true},
+}
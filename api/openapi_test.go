@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"mock-golang/openapi"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+func testRouter(t *testing.T) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	// Dialing does not connect, the handlers are never called
+	conn, err := grpc.Dial("localhost:0", grpc.WithInsecure())
+	assert.Nil(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	g, err := newRouter(conn, zap.NewNop())
+	assert.Nil(t, err)
+
+	return g
+}
+
+func TestEveryRouteIsDocumented(t *testing.T) {
+	g := testRouter(t)
+
+	assert.Empty(t, openapi.Undocumented(g.Routes(), operations))
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	g := testRouter(t)
+
+	w := httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/api/openapi.json", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	doc := openapi.Document{}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	assert.Equal(t, "3.0.3", doc.OpenAPI)
+
+	// README used to list it as a GET
+	guest := doc.Paths["/v1/api/booking/guest"]
+	assert.NotNil(t, guest["post"])
+	assert.Nil(t, guest["get"])
+
+	body := guest["post"].RequestBody.Content["application/json"].Schema
+	assert.Equal(t, "#/components/schemas/GuestBookingRequest", body.Ref)
+	assert.Contains(t, doc.Components.Schemas["GuestBookingRequest"].Required, "flightId")
+
+	removeMember := doc.Paths["/v1/api/organization/{id}/member/{customerId}"]["delete"]
+	assert.Len(t, removeMember.Parameters, 2)
+	assert.NotEmpty(t, removeMember.Security)
+}
+
+func TestOpenAPIDocumentsTranscodedRoutes(t *testing.T) {
+	doc := openapi.New(apiInfo, testRouter(t).Routes(), operations, transcodedServices...)
+
+	approve := doc.Paths["/v2/bookings/{booking_id}:approve"]["post"]
+	assert.NotNil(t, approve)
+	assert.Equal(t, "RPCBooking.ApproveBooking", approve.Summary)
+	assert.Equal(t, "booking_id", approve.Parameters[0].Name)
+	assert.Equal(t, "#/components/schemas/tuns_go_flight.BookingDecisionRequest", approve.RequestBody.Content["application/json"].Schema.Ref)
+	assert.Equal(t, "#/components/schemas/tuns_go_flight.Booking", approve.Responses["200"].Content["application/json"].Schema.Ref)
+
+	search := doc.Paths["/v2/bookings"]["get"]
+	assert.NotNil(t, search)
+	assert.Nil(t, search.RequestBody)
+	assert.NotEmpty(t, search.Parameters)
+
+	assert.NotNil(t, doc.Paths["/v2/travellers/{id}"]["delete"])
+	assert.NotNil(t, doc.Paths["/v2/customers/{customer_id}/notification-preferences"]["put"])
+	// the catch-all proxy routes are not documented themselves
+	assert.Nil(t, doc.Paths["/v2/*path"])
+	assert.Equal(t, "string", doc.Components.Schemas["tuns_go_flight.Booking"].Properties["createdAt"].Type)
+}
+
+func TestRESTProxyErrorEnvelope(t *testing.T) {
+	g := testRouter(t)
+
+	w := httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v2/unknown", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	body := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "not_found", body["code"])
+}
+
+func TestRequestIDHeader(t *testing.T) {
+	g := testRouter(t)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	r.Header.Set("X-Request-ID", "abc-123")
+	g.ServeHTTP(w, r)
+	assert.Equal(t, "abc-123", w.Header().Get("X-Request-ID"))
+
+	w = httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.NotEmpty(t, w.Header().Get("X-Request-ID"))
+}
@@ -0,0 +1,20 @@
+package organization_request
+
+type CreateOrganizationRequest struct {
+	Name                string `json:"name" binding:"required"`
+	MaxFareClass        string `json:"maxFareClass" binding:"omitempty,oneof=economy premium_economy business first"`
+	AdvancePurchaseDays int32  `json:"advancePurchaseDays" binding:"min=0"`
+}
+
+type UpdateOrganizationRequest struct {
+	Id                  string `json:"id" binding:"required"`
+	Name                string `json:"name" binding:"required"`
+	MaxFareClass        string `json:"maxFareClass" binding:"omitempty,oneof=economy premium_economy business first"`
+	AdvancePurchaseDays int32  `json:"advancePurchaseDays" binding:"min=0"`
+}
+
+type SetMemberRequest struct {
+	OrganizationId string `json:"organizationId" binding:"required"`
+	CustomerId     string `json:"customerId" binding:"required"`
+	Role           string `json:"role" binding:"required,oneof=traveller booker approver"`
+}
@@ -0,0 +1,21 @@
+package organization_response
+
+import "time"
+
+type OrganizationResponse struct {
+	Id                  string            `json:"id"`
+	Name                string            `json:"name"`
+	MaxFareClass        string            `json:"maxFareClass"`
+	AdvancePurchaseDays int32             `json:"advancePurchaseDays"`
+	CreatedAt           time.Time         `json:"createdAt"`
+	UpdatedAt           time.Time         `json:"updatedAt"`
+	Members             []*MemberResponse `json:"members"`
+}
+
+type MemberResponse struct {
+	OrganizationId string    `json:"organizationId"`
+	CustomerId     string    `json:"customerId"`
+	CustomerName   string    `json:"customerName"`
+	Role           string    `json:"role"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
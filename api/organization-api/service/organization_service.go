@@ -0,0 +1,174 @@
+package organization_handler
+
+import (
+	organization_request "mock-golang/api/organization-api/request"
+	organization_response "mock-golang/api/organization-api/response"
+	"mock-golang/apperror"
+	"mock-golang/protobuf"
+	"mock-golang/validation"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OrganizationHandler interface {
+	FindById(c *gin.Context)
+	CreateOrganization(c *gin.Context)
+	UpdateOrganization(c *gin.Context)
+	SetMember(c *gin.Context)
+	RemoveMember(c *gin.Context)
+}
+
+type organizationHandler struct {
+	organizationClient protobuf.RPCOrganizationClient
+}
+
+func NewOrganizationHandler(organizationClient protobuf.RPCOrganizationClient) OrganizationHandler {
+	return &organizationHandler{
+		organizationClient: organizationClient,
+	}
+}
+
+func (h *organizationHandler) FindById(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := apperror.ParseID("id", id); err != nil {
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	pRes, err := h.organizationClient.FindById(c.Request.Context(), &protobuf.OrganizationParamId{
+		Id: id,
+	})
+	if err != nil {
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusText(http.StatusOK),
+		"payload": toOrganizationResponse(pRes),
+	})
+}
+
+func (h *organizationHandler) CreateOrganization(c *gin.Context) {
+	req := organization_request.CreateOrganizationRequest{}
+
+	if err := c.ShouldBind(&req); err != nil {
+		validation.AbortWithBindError(c, err)
+		return
+	}
+
+	pRes, err := h.organizationClient.CreateOrganization(c.Request.Context(), &protobuf.Organization{
+		Name:                req.Name,
+		MaxFareClass:        req.MaxFareClass,
+		AdvancePurchaseDays: req.AdvancePurchaseDays,
+	})
+	if err != nil {
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusText(http.StatusOK),
+		"payload": toOrganizationResponse(pRes),
+	})
+}
+
+func (h *organizationHandler) UpdateOrganization(c *gin.Context) {
+	req := organization_request.UpdateOrganizationRequest{}
+
+	if err := c.ShouldBind(&req); err != nil {
+		validation.AbortWithBindError(c, err)
+		return
+	}
+
+	pRes, err := h.organizationClient.UpdateOrganization(c.Request.Context(), &protobuf.Organization{
+		Id:                  req.Id,
+		Name:                req.Name,
+		MaxFareClass:        req.MaxFareClass,
+		AdvancePurchaseDays: req.AdvancePurchaseDays,
+	})
+	if err != nil {
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusText(http.StatusOK),
+		"payload": toOrganizationResponse(pRes),
+	})
+}
+
+func (h *organizationHandler) SetMember(c *gin.Context) {
+	req := organization_request.SetMemberRequest{}
+
+	if err := c.ShouldBind(&req); err != nil {
+		validation.AbortWithBindError(c, err)
+		return
+	}
+
+	pRes, err := h.organizationClient.SetMember(c.Request.Context(), &protobuf.OrganizationMember{
+		OrganizationId: req.OrganizationId,
+		CustomerId:     req.CustomerId,
+		Role:           req.Role,
+	})
+	if err != nil {
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusText(http.StatusOK),
+		"payload": toMemberResponse(pRes),
+	})
+}
+
+func (h *organizationHandler) RemoveMember(c *gin.Context) {
+	customerId := c.Param("customerId")
+	if _, err := apperror.ParseID("customerId", customerId); err != nil {
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	pRes, err := h.organizationClient.RemoveMember(c.Request.Context(), &protobuf.OrganizationMember{
+		OrganizationId: c.Param("id"),
+		CustomerId:     customerId,
+	})
+	if err != nil {
+		apperror.AbortWithRPCError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  http.StatusText(http.StatusOK),
+		"payload": toMemberResponse(pRes),
+	})
+}
+
+func toOrganizationResponse(pRes *protobuf.Organization) *organization_response.OrganizationResponse {
+	dto := &organization_response.OrganizationResponse{
+		Id:                  pRes.Id,
+		Name:                pRes.Name,
+		MaxFareClass:        pRes.MaxFareClass,
+		AdvancePurchaseDays: pRes.AdvancePurchaseDays,
+		CreatedAt:           pRes.CreatedAt.AsTime(),
+		UpdatedAt:           pRes.UpdatedAt.AsTime(),
+		Members:             []*organization_response.MemberResponse{},
+	}
+
+	for _, member := range pRes.Member {
+		dto.Members = append(dto.Members, toMemberResponse(member))
+	}
+
+	return dto
+}
+
+func toMemberResponse(pRes *protobuf.OrganizationMember) *organization_response.MemberResponse {
+	return &organization_response.MemberResponse{
+		OrganizationId: pRes.OrganizationId,
+		CustomerId:     pRes.CustomerId,
+		CustomerName:   pRes.CustomerName,
+		Role:           pRes.Role,
+		CreatedAt:      pRes.CreatedAt.AsTime(),
+	}
+}
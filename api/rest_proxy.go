@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"mock-golang/apperror"
+	"mock-golang/logging"
+	"mock-golang/protobuf"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/metadata"
+)
+
+// restMethods are the methods the /v2 routes hand to the REST proxy
+var restMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete}
+
+// newRESTProxy serves the /v2 routes generated from the http annotations of the protos.
+// It only forwards what the gin middlewares put in the outgoing metadata, never raw client headers.
+func newRESTProxy(customerClient protobuf.RPCCustomerClient, bookingClient protobuf.RPCBookingClient,
+	flightClient protobuf.RPCFlightClient, logger *zap.Logger) (http.Handler, error) {
+	mux := runtime.NewServeMux(
+		runtime.WithIncomingHeaderMatcher(func(string) (string, bool) { return "", false }),
+		runtime.WithMetadata(func(_ context.Context, req *http.Request) metadata.MD {
+			md, _ := metadata.FromOutgoingContext(req.Context())
+			return md
+		}),
+		runtime.WithErrorHandler(restErrorHandler(logger)),
+	)
+
+	ctx := context.Background()
+	if err := protobuf.RegisterRPCCustomerHandlerClient(ctx, mux, customerClient); err != nil {
+		return nil, err
+	}
+	if err := protobuf.RegisterRPCBookingHandlerClient(ctx, mux, bookingClient); err != nil {
+		return nil, err
+	}
+	if err := protobuf.RegisterRPCFlightHandlerClient(ctx, mux, flightClient); err != nil {
+		return nil, err
+	}
+
+	return mux, nil
+}
+
+// restErrorHandler answers the same error envelope as the gin routes
+func restErrorHandler(logger *zap.Logger) runtime.ErrorHandlerFunc {
+	return func(_ context.Context, _ *runtime.ServeMux, _ runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+		var httpErr *runtime.HTTPStatusError
+		if errors.As(err, &httpErr) {
+			err = httpErr.Err
+		}
+
+		httpStatus, body := apperror.Envelope(err)
+		if httpStatus == http.StatusInternalServerError {
+			logging.FromContext(r.Context(), logger).Error("rest proxy", zap.String("path", r.URL.Path), zap.Error(err))
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(httpStatus)
+		if err := json.NewEncoder(w).Encode(body); err != nil {
+			logger.Warn("rest proxy: write error", zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	auth_handler "mock-golang/api/auth-api/service"
+	booking_handler "mock-golang/api/booking-api/service"
+	customer_handler "mock-golang/api/customer-api/service"
+	flight_handler "mock-golang/api/flight-api/service"
+	loyalty_handler "mock-golang/api/loyalty-api/service"
+	organization_handler "mock-golang/api/organization-api/service"
+	"mock-golang/clientip"
+	"mock-golang/health"
+	"mock-golang/metrics"
+	"mock-golang/middleware"
+	"mock-golang/openapi"
+	"mock-golang/protobuf"
+	"mock-golang/rbac"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// newRouter registers the gateway routes, every route needs its entry in operations
+func newRouter(conn grpc.ClientConnInterface, logger *zap.Logger) (*gin.Engine, error) {
+	//Singleton
+	customerClient := protobuf.NewRPCCustomerClient(conn)
+	bookingClient := protobuf.NewRPCBookingClient(conn)
+	flightClient := protobuf.NewRPCFlightClient(conn)
+	authClient := protobuf.NewRPCAuthClient(conn)
+	loyaltyClient := protobuf.NewRPCLoyaltyClient(conn)
+	organizationClient := protobuf.NewRPCOrganizationClient(conn)
+	healthClient := grpc_health_v1.NewHealthClient(conn)
+
+	//Handler for GIN Gonic
+	hCustomer := customer_handler.NewCustomerHandler(customerClient)
+	hFlight := flight_handler.NewFlightHandler(flightClient)
+	hBooking := booking_handler.NewBookingHandler(bookingClient, customerClient, flightClient)
+	hAuth := auth_handler.NewAuthHandler(authClient)
+	hLoyalty := loyalty_handler.NewLoyaltyHandler(loyaltyClient)
+	hOrganization := organization_handler.NewOrganizationHandler(organizationClient)
+
+	//REST proxy generated from the protos
+	restProxy, err := newRESTProxy(customerClient, bookingClient, flightClient, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	proxies, err := clientip.FromConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	g := gin.New()
+	// First, so the other middlewares and the gRPC calls join the request span
+	g.Use(otelgin.Middleware("flight-booking-api"))
+	g.Use(middleware.RequestIDMiddleware(logger))
+	g.Use(gin.Logger())
+	g.Use(middleware.MetricsMiddleware())
+	g.Use(middleware.RecoveryMiddleware(logger))
+	g.Use(middleware.LoggingMiddleware(logger))
+	g.Use(middleware.ClientIPMiddleware(proxies))
+	g.Use(middleware.AuthMiddleware(authClient))
+
+	// Prometheus metrics
+	g.GET("/metrics", gin.WrapH(metrics.Handler()))
+
+	// Probes, readiness fails while a gRPC service or its database is down
+	g.GET("/healthz", health.LivenessHandler())
+	g.GET("/readyz", health.ReadinessHandler(healthClient, []string{
+		protobuf.RPCCustomer_ServiceDesc.ServiceName,
+		protobuf.RPCFlight_ServiceDesc.ServiceName,
+		protobuf.RPCBooking_ServiceDesc.ServiceName,
+	}))
+
+	//Create routes
+	gr := g.Group("/v1/api")
+
+	// API Docs, the document covers the routes registered below
+	var spec gin.HandlerFunc
+	gr.GET("/openapi.json", func(c *gin.Context) { spec(c) })
+	gr.GET("/docs", openapi.UIHandler(apiInfo.Title, "/v1/api/openapi.json"))
+
+	// API Auth
+	gr.POST("/auth/login", hAuth.Login)
+	gr.POST("/auth/refresh", hAuth.RefreshToken)
+	gr.POST("/auth/forgot-password", hAuth.ForgotPassword)
+	gr.POST("/auth/reset-password", hAuth.ResetPassword)
+	gr.POST("/auth/verify-email/send", middleware.RequirePermission(rbac.PermCustomerWriteOwn, rbac.PermCustomerWriteAny), hAuth.SendEmailVerification)
+	gr.POST("/auth/verify-email", hAuth.VerifyEmail)
+	gr.POST("/auth/verify-phone/send", middleware.RequirePermission(rbac.PermCustomerWriteOwn, rbac.PermCustomerWriteAny), hAuth.SendPhoneOtp)
+	gr.POST("/auth/verify-phone", middleware.RequirePermission(rbac.PermCustomerWriteOwn, rbac.PermCustomerWriteAny), hAuth.VerifyPhone)
+	gr.POST("/auth/unlock-account", middleware.RequirePermission(rbac.PermAccountUnlock), hAuth.UnlockAccount)
+	gr.POST("/auth/claim-account/send", hAuth.RequestAccountClaim)
+	gr.POST("/auth/claim-account", hAuth.ClaimAccount)
+
+	// API Customer
+	gr.POST("/customer", hCustomer.CreateCustomer)
+	gr.PUT("/customer", middleware.RequirePermission(rbac.PermCustomerWriteOwn, rbac.PermCustomerWriteAny), hCustomer.UpdateCustomer)
+	gr.POST("/customer/changePassword", middleware.RequirePermission(rbac.PermCustomerWriteOwn, rbac.PermCustomerWriteAny), hCustomer.ChangePassword)
+	gr.POST("/customer/role", middleware.RequirePermission(rbac.PermRoleAssign), hCustomer.AssignRole)
+	gr.POST("/customer/merge", middleware.RequirePermission(rbac.PermCustomerMerge), hCustomer.MergeCustomers)
+	gr.GET("/customer/search", middleware.RequirePermission(rbac.PermCustomerReadAny), hCustomer.SearchCustomer)
+	gr.GET("/customer/duplicates", middleware.RequirePermission(rbac.PermCustomerReadAny), hCustomer.FindDuplicateCustomers)
+	gr.GET("/customer/travellers/:customerId", middleware.RequirePermission(rbac.PermCustomerReadOwn, rbac.PermCustomerReadAny), hCustomer.ListTravellers)
+	gr.POST("/customer/traveller", middleware.RequirePermission(rbac.PermCustomerWriteOwn, rbac.PermCustomerWriteAny), hCustomer.CreateTraveller)
+	gr.PUT("/customer/traveller", middleware.RequirePermission(rbac.PermCustomerWriteOwn, rbac.PermCustomerWriteAny), hCustomer.UpdateTraveller)
+	gr.DELETE("/customer/traveller/:id", middleware.RequirePermission(rbac.PermCustomerWriteOwn, rbac.PermCustomerWriteAny), hCustomer.DeleteTraveller)
+	gr.GET("/customer/notification-preferences/:customerId", middleware.RequirePermission(rbac.PermCustomerReadOwn, rbac.PermCustomerReadAny), hCustomer.GetNotificationPreferences)
+	gr.PUT("/customer/notification-preferences", middleware.RequirePermission(rbac.PermCustomerWriteOwn, rbac.PermCustomerWriteAny), hCustomer.UpdateNotificationPreferences)
+	gr.GET("/customer/export/:customerId", middleware.RequirePermission(rbac.PermCustomerExport), hCustomer.ExportCustomerData)
+	gr.POST("/customer/erase", middleware.RequirePermission(rbac.PermCustomerErase), hCustomer.EraseCustomer)
+	gr.POST("/customer/deactivate", middleware.RequirePermission(rbac.PermCustomerDelete), hCustomer.DeactivateCustomer)
+	gr.POST("/customer/viewBookingHistory", middleware.RequirePermission(rbac.PermBookingReadOwn, rbac.PermBookingReadAny), hBooking.BookingHistory)
+	gr.POST("/customer/searchBooking", middleware.RequirePermission(rbac.PermBookingReadOwn, rbac.PermBookingReadAny), hBooking.SearchBooking)
+
+	// API Booking
+	gr.POST("/booking", middleware.RequirePermission(rbac.PermBookingWriteOwn, rbac.PermBookingWriteAny), hBooking.CustomerBooking)
+	gr.POST("/booking/guest", hBooking.GuestBooking)
+	gr.POST("/booking/cancel", middleware.RequirePermission(rbac.PermBookingWriteOwn, rbac.PermBookingWriteAny), hBooking.CancelBooking)
+	gr.GET("/booking/approvals/:organizationId", middleware.RequirePermission(rbac.PermBookingWriteOwn, rbac.PermOrganizationManage), hBooking.ListPendingApprovals)
+	gr.POST("/booking/approve", middleware.RequirePermission(rbac.PermBookingWriteOwn, rbac.PermOrganizationManage), hBooking.ApproveBooking)
+	gr.POST("/booking/reject", middleware.RequirePermission(rbac.PermBookingWriteOwn, rbac.PermOrganizationManage), hBooking.RejectBooking)
+
+	// API Flight
+	gr.POST("/flight", middleware.RequirePermission(rbac.PermFlightWrite), hFlight.CreateFlight)
+	gr.PUT("/flight", middleware.RequirePermission(rbac.PermFlightWrite), hFlight.UpdateFlight)
+	gr.GET("/flight/search", middleware.RequirePermission(rbac.PermFlightRead), hFlight.SearchFlight)
+	gr.GET("/flight/:id", middleware.RequirePermission(rbac.PermFlightRead), hFlight.SearchFlightById)
+	gr.DELETE("/flight/:id", middleware.RequirePermission(rbac.PermFlightDelete), hFlight.DeleteFlight)
+
+	// API Loyalty
+	gr.GET("/loyalty/:customerId", middleware.RequirePermission(rbac.PermLoyaltyReadOwn, rbac.PermLoyaltyReadAny), hLoyalty.GetBalance)
+	gr.POST("/loyalty/statement", middleware.RequirePermission(rbac.PermLoyaltyReadOwn, rbac.PermLoyaltyReadAny), hLoyalty.GetStatement)
+	gr.POST("/loyalty/redeem", middleware.RequirePermission(rbac.PermLoyaltyRedeemOwn, rbac.PermLoyaltyRedeemAny), hLoyalty.RedeemPoints)
+
+	// API Organization
+	gr.POST("/organization", middleware.RequirePermission(rbac.PermOrganizationManage), hOrganization.CreateOrganization)
+	gr.PUT("/organization", middleware.RequirePermission(rbac.PermOrganizationManage), hOrganization.UpdateOrganization)
+	gr.GET("/organization/:id", middleware.RequirePermission(rbac.PermCustomerReadOwn, rbac.PermOrganizationManage), hOrganization.FindById)
+	gr.POST("/organization/member", middleware.RequirePermission(rbac.PermOrganizationManage), hOrganization.SetMember)
+	gr.DELETE("/organization/:id/member/:customerId", middleware.RequirePermission(rbac.PermOrganizationManage), hOrganization.RemoveMember)
+
+	// API v2, plain CRUD transcoded to the gRPC services, see the google.api.http options of the protos
+	for _, method := range restMethods {
+		g.Handle(method, "/v2/*path", gin.WrapH(restProxy))
+	}
+
+	spec = openapi.Handler(openapi.New(apiInfo, g.Routes(), operations, transcodedServices...))
+
+	return g, nil
+}
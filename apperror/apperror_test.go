@@ -0,0 +1,107 @@
+package apperror
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+func TestFromDB(t *testing.T) {
+	assert.Nil(t, FromDB(nil))
+
+	for _, err := range []error{gorm.ErrRecordNotFound, sql.ErrNoRows, fmt.Errorf("find: %w", gorm.ErrRecordNotFound)} {
+		translated := FromDB(err)
+		assert.True(t, errors.Is(translated, ErrNotFound), err.Error())
+		// the cause stays reachable for the callers matching gorm errors
+		assert.True(t, errors.Is(translated, err), err.Error())
+	}
+
+	other := errors.New("connection refused")
+	assert.Equal(t, other, FromDB(other))
+}
+
+func TestFromDBUniqueViolation(t *testing.T) {
+	RegisterUniqueIndex("customers_email_key", "email", "a customer with this %s already exists", "email")
+
+	violation := &pgconn.PgError{Code: pgUniqueViolation, ConstraintName: "customers_email_key", Message: "duplicate key"}
+	translated := &Error{}
+	assert.True(t, errors.As(FromDB(violation), &translated))
+	assert.True(t, errors.Is(translated, ErrAlreadyExists))
+	assert.Equal(t, "email", translated.Field)
+	assert.Equal(t, "a customer with this email already exists", translated.Message)
+
+	unknown := &pgconn.PgError{Code: pgUniqueViolation, ConstraintName: "other_key", Message: "duplicate key"}
+	assert.True(t, errors.As(FromDB(unknown), &translated))
+	assert.Equal(t, "", translated.Field)
+	assert.Equal(t, "duplicate key", translated.Message)
+}
+
+func TestToStatus(t *testing.T) {
+	assert.Nil(t, ToStatus(nil))
+	assert.Equal(t, codes.NotFound, status.Code(ToStatus(gorm.ErrRecordNotFound)))
+	assert.Equal(t, codes.NotFound, status.Code(ToStatus(NotFound("flight %s not found", "x"))))
+	assert.Equal(t, codes.InvalidArgument, status.Code(ToStatus(InvalidArgument("bad"))))
+	assert.Equal(t, codes.FailedPrecondition, status.Code(ToStatus(FailedPrecondition("departed"))))
+	assert.Equal(t, codes.Internal, status.Code(ToStatus(errors.New("boom"))))
+
+	// status errors pass through
+	denied := status.Error(codes.PermissionDenied, "no")
+	assert.Equal(t, denied, ToStatus(denied))
+
+	st := status.Convert(ToStatus(AlreadyExists("email", "a customer with this email already exists")))
+	assert.Equal(t, codes.AlreadyExists, st.Code())
+	assert.Equal(t, "a customer with this email already exists", st.Message())
+	assert.Len(t, st.Details(), 1)
+}
+
+func TestAbortWithRPCError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := []struct {
+		err    error
+		status int
+		code   string
+		field  string
+	}{
+		{status.Error(codes.NotFound, "booking not found"), http.StatusNotFound, "not_found", ""},
+		{status.Error(codes.InvalidArgument, "bad id"), http.StatusBadRequest, "invalid_argument", ""},
+		{ToStatus(AlreadyExists("email", "taken")), http.StatusConflict, "already_exists", "email"},
+		{status.Error(codes.FailedPrecondition, "has bookings"), http.StatusUnprocessableEntity, "failed_precondition", ""},
+		{status.Error(codes.ResourceExhausted, "locked"), http.StatusTooManyRequests, "resource_exhausted", ""},
+		{errors.New("dial tcp: refused"), http.StatusInternalServerError, "internal", ""},
+	}
+
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		AbortWithRPCError(c, tc.err)
+
+		body := map[string]string{}
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, tc.status, w.Code, tc.err.Error())
+		assert.Equal(t, tc.code, body["code"], tc.err.Error())
+		assert.Equal(t, tc.field, body["field"], tc.err.Error())
+	}
+}
+
+func TestParseID(t *testing.T) {
+	id, err := ParseID("id", "7f1c7c4e-3c4e-4d5e-9a43-0a1b2c3d4e5f")
+	assert.Nil(t, err)
+	assert.Equal(t, "7f1c7c4e-3c4e-4d5e-9a43-0a1b2c3d4e5f", id.String())
+
+	_, err = ParseID("flightId", "not-a-uuid")
+	st := status.Convert(err)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	assert.Equal(t, `flightId "not-a-uuid" is not a valid id`, st.Message())
+}
@@ -0,0 +1,100 @@
+package apperror
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgconn"
+	"gorm.io/gorm"
+)
+
+// Kinds of domain errors, match them with errors.Is
+var (
+	ErrNotFound           = errors.New("not found")
+	ErrInvalidArgument    = errors.New("invalid argument")
+	ErrAlreadyExists      = errors.New("already exists")
+	ErrFailedPrecondition = errors.New("failed precondition")
+)
+
+// pgUniqueViolation is the Postgres SQLSTATE of a unique index violation
+const pgUniqueViolation = "23505"
+
+// Error is a domain error returned by the repositories and the models.
+// It matches its Kind and, through Unwrap, the error it was made from.
+type Error struct {
+	Kind    error
+	Message string
+	// Field is the api field the error is about, if any
+	Field string
+	Err   error
+}
+
+func (e *Error) Error() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+
+	return e.Kind.Error()
+}
+
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func NotFound(format string, args ...interface{}) error {
+	return &Error{Kind: ErrNotFound, Message: fmt.Sprintf(format, args...)}
+}
+
+func InvalidArgument(format string, args ...interface{}) error {
+	return &Error{Kind: ErrInvalidArgument, Message: fmt.Sprintf(format, args...)}
+}
+
+// AlreadyExists names the field that is already taken
+func AlreadyExists(field string, format string, args ...interface{}) error {
+	return &Error{Kind: ErrAlreadyExists, Field: field, Message: fmt.Sprintf(format, args...)}
+}
+
+func FailedPrecondition(format string, args ...interface{}) error {
+	return &Error{Kind: ErrFailedPrecondition, Message: fmt.Sprintf(format, args...)}
+}
+
+// uniqueFields maps a unique index to the api field it protects and the message of a conflict
+var uniqueFields = map[string]*Error{}
+
+// RegisterUniqueIndex makes FromDB report a violation of index as AlreadyExists naming field.
+// Call it from an init function, the registry is not guarded for concurrent writes.
+func RegisterUniqueIndex(index string, field string, format string, args ...interface{}) {
+	uniqueFields[index] = &Error{Kind: ErrAlreadyExists, Field: field, Message: fmt.Sprintf(format, args...)}
+}
+
+// FromDB turns the "no row" errors of gorm and database/sql into ErrNotFound and unique
+// index violations into ErrAlreadyExists, with the field of a registered index, anything
+// else is returned as is
+func FromDB(err error) error {
+	appErr := &Error{}
+	if err == nil || errors.As(err, &appErr) {
+		return err
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, sql.ErrNoRows) {
+		return &Error{Kind: ErrNotFound, Message: "record not found", Err: err}
+	}
+
+	pgErr := &pgconn.PgError{}
+	if errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation {
+		if registered, ok := uniqueFields[pgErr.ConstraintName]; ok {
+			return &Error{Kind: ErrAlreadyExists, Field: registered.Field, Message: registered.Message, Err: err}
+		}
+		return &Error{Kind: ErrAlreadyExists, Message: pgErr.Message, Err: err}
+	}
+
+	return err
+}
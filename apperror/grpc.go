@@ -0,0 +1,61 @@
+package apperror
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorDomain is reported in the ErrorInfo details of the statuses made here
+const ErrorDomain = "mock-golang"
+
+var kindCodes = []struct {
+	kind error
+	code codes.Code
+}{
+	{ErrNotFound, codes.NotFound},
+	{ErrInvalidArgument, codes.InvalidArgument},
+	{ErrAlreadyExists, codes.AlreadyExists},
+	{ErrFailedPrecondition, codes.FailedPrecondition},
+	{context.Canceled, codes.Canceled},
+	{context.DeadlineExceeded, codes.DeadlineExceeded},
+}
+
+// ToStatus is what the gRPC handlers return for an error of a repository or a model.
+// Status errors pass through, domain errors get their code, anything else is Internal.
+func ToStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	err = FromDB(err)
+	for _, kc := range kindCodes {
+		if !errors.Is(err, kc.kind) {
+			continue
+		}
+
+		st := status.New(kc.code, err.Error())
+
+		appErr := &Error{}
+		if errors.As(err, &appErr) && appErr.Field != "" {
+			if detailed, detailErr := st.WithDetails(&errdetails.ErrorInfo{
+				Reason:   Code(kc.code),
+				Domain:   ErrorDomain,
+				Metadata: map[string]string{"field": appErr.Field},
+			}); detailErr == nil {
+				st = detailed
+			}
+		}
+
+		return st.Err()
+	}
+
+	return status.Error(codes.Internal, err.Error())
+}
@@ -0,0 +1,108 @@
+package apperror
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var httpStatuses = map[codes.Code]int{
+	codes.OK:                 http.StatusOK,
+	codes.Canceled:           499,
+	codes.InvalidArgument:    http.StatusBadRequest,
+	codes.NotFound:           http.StatusNotFound,
+	codes.AlreadyExists:      http.StatusConflict,
+	codes.Aborted:            http.StatusConflict,
+	codes.FailedPrecondition: http.StatusUnprocessableEntity,
+	codes.OutOfRange:         http.StatusBadRequest,
+	codes.PermissionDenied:   http.StatusForbidden,
+	codes.Unauthenticated:    http.StatusUnauthorized,
+	codes.ResourceExhausted:  http.StatusTooManyRequests,
+	codes.Unimplemented:      http.StatusNotImplemented,
+	codes.Unavailable:        http.StatusServiceUnavailable,
+	codes.DeadlineExceeded:   http.StatusGatewayTimeout,
+}
+
+// stable codes of the error envelope, clients may switch on them
+var codeNames = map[codes.Code]string{
+	codes.Canceled:           "canceled",
+	codes.InvalidArgument:    "invalid_argument",
+	codes.NotFound:           "not_found",
+	codes.AlreadyExists:      "already_exists",
+	codes.Aborted:            "aborted",
+	codes.FailedPrecondition: "failed_precondition",
+	codes.OutOfRange:         "out_of_range",
+	codes.PermissionDenied:   "permission_denied",
+	codes.Unauthenticated:    "unauthenticated",
+	codes.ResourceExhausted:  "resource_exhausted",
+	codes.Unimplemented:      "unimplemented",
+	codes.Unavailable:        "unavailable",
+	codes.DeadlineExceeded:   "deadline_exceeded",
+}
+
+// HTTPStatus maps a gRPC code to the status the gateway answers, unknown codes are 500
+func HTTPStatus(code codes.Code) int {
+	if httpStatus, ok := httpStatuses[code]; ok {
+		return httpStatus
+	}
+
+	return http.StatusInternalServerError
+}
+
+// Code is the stable machine readable code of the error envelope
+func Code(code codes.Code) string {
+	if name, ok := codeNames[code]; ok {
+		return name
+	}
+
+	return "internal"
+}
+
+// AbortWithRPCError answers with the error envelope {status, code, error, field}.
+// Internal errors are attached to the gin context for the logs and hidden from the client.
+func AbortWithRPCError(c *gin.Context, err error) {
+	httpStatus, body := Envelope(err)
+	if httpStatus == http.StatusInternalServerError {
+		c.Error(err)
+	}
+
+	c.AbortWithStatusJSON(httpStatus, body)
+}
+
+// Envelope is the http status and body AbortWithRPCError answers, for handlers outside gin
+func Envelope(err error) (int, gin.H) {
+	st := status.Convert(err)
+	httpStatus := HTTPStatus(st.Code())
+
+	field := ""
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.Metadata["field"] != "" {
+			field = info.Metadata["field"]
+		}
+	}
+
+	message := st.Message()
+	if httpStatus == http.StatusInternalServerError {
+		message = "internal error"
+	}
+
+	return httpStatus, Body(httpStatus, Code(st.Code()), message, field)
+}
+
+// Body is the error envelope {status, code, error, field}, field is left out when empty
+func Body(httpStatus int, code string, message string, field string) gin.H {
+	body := gin.H{
+		"status": http.StatusText(httpStatus),
+		"code":   code,
+		"error":  message,
+	}
+
+	if field != "" {
+		body["field"] = field
+	}
+
+	return body
+}
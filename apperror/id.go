@@ -0,0 +1,22 @@
+package apperror
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// ParseID parses the id a request carries in field, a malformed id is an InvalidArgument status
+func ParseID(field string, value string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, ToStatus(&Error{
+			Kind:    ErrInvalidArgument,
+			Field:   field,
+			Message: fmt.Sprintf("%s %q is not a valid id", field, value),
+			Err:     err,
+		})
+	}
+
+	return id, nil
+}
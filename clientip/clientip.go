@@ -0,0 +1,107 @@
+package clientip
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Metadata is the gRPC metadata key the gateway reports the end user address in
+const Metadata = "x-client-ip"
+
+// Proxies are the networks allowed to report the client address of someone else
+// (X-Forwarded-For on the gateway, x-client-ip on the gRPC servers). Nobody else is believed.
+type Proxies []*net.IPNet
+
+// Parse accepts addresses and CIDR blocks
+func Parse(entries []string) (Proxies, error) {
+	proxies := Proxies{}
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("trusted proxy %q is not an ip address", entry)
+			}
+			if ip.To4() != nil {
+				entry += "/32"
+			} else {
+				entry += "/128"
+			}
+		}
+
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy %q: %w", entry, err)
+		}
+		proxies = append(proxies, network)
+	}
+	return proxies, nil
+}
+
+// FromConfig reads server.trusted_proxies, empty trusts nobody
+func FromConfig() (Proxies, error) {
+	return Parse(viper.GetStringSlice("server.trusted_proxies"))
+}
+
+func (p Proxies) Trusts(addr string) bool {
+	ip := net.ParseIP(Host(addr))
+	if ip == nil {
+		return false
+	}
+	for _, network := range p {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve returns the remote address unless it is a trusted proxy. Then the forwarded
+// chain ("client, proxy1, proxy2") is read from the right and the first address not
+// added by a trusted proxy is the client.
+func (p Proxies) Resolve(remote string, forwarded string) string {
+	remote = Host(remote)
+	if forwarded == "" || !p.Trusts(remote) {
+		return remote
+	}
+
+	hops := strings.Split(forwarded, ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if net.ParseIP(hop) == nil {
+			return remote
+		}
+		if i == 0 || !p.Trusts(hop) {
+			return hop
+		}
+	}
+	return remote
+}
+
+// Host strips the port of host:port addresses
+func Host(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+type clientIpKey struct{}
+
+func NewContext(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIpKey{}, ip)
+}
+
+// FromContext returns the address resolved for the call, empty when none was
+func FromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIpKey{}).(string)
+	return ip
+}
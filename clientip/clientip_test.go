@@ -0,0 +1,32 @@
+package clientip
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	proxies, err := Parse([]string{"127.0.0.1", "::1", "10.0.0.0/8"})
+	assert.Nil(t, err)
+	assert.Len(t, proxies, 3)
+
+	_, err = Parse([]string{"localhost"})
+	assert.NotNil(t, err)
+}
+
+func TestResolve(t *testing.T) {
+	proxies, _ := Parse([]string{"127.0.0.1", "10.0.0.0/8"})
+
+	// untrusted callers can not pick their address
+	assert.Equal(t, "203.0.113.9", proxies.Resolve("203.0.113.9:5123", "1.2.3.4"))
+	assert.Equal(t, "203.0.113.9", Proxies{}.Resolve("203.0.113.9:5123", "1.2.3.4"))
+
+	// a trusted proxy reports the client, spoofed entries left of it are ignored
+	assert.Equal(t, "198.51.100.7", proxies.Resolve("127.0.0.1:40000", "1.2.3.4, 198.51.100.7"))
+	assert.Equal(t, "198.51.100.7", proxies.Resolve("127.0.0.1:40000", "1.2.3.4, 198.51.100.7, 10.1.2.3"))
+	assert.Equal(t, "10.1.2.3", proxies.Resolve("127.0.0.1:40000", "10.1.2.3"))
+
+	assert.Equal(t, "127.0.0.1", proxies.Resolve("127.0.0.1:40000", ""))
+	assert.Equal(t, "127.0.0.1", proxies.Resolve("127.0.0.1:40000", "not-an-ip"))
+}
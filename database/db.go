@@ -1,7 +1,11 @@
 package database
 
 import (
+	"context"
 	"fmt"
+	"mock-golang/metrics"
+	"mock-golang/tracing"
+	"sync"
 
 	"github.com/spf13/viper"
 	"gorm.io/driver/postgres"
@@ -41,7 +45,59 @@ func (c DBConnection) ToConnectionString() string {
 		c.Timezone)
 }
 
+// pools opened by NewGormDB, every repository has its own
+var (
+	poolsMu sync.Mutex
+	pools   []*gorm.DB
+)
+
 func NewGormDB() (*gorm.DB, error) {
 	c := NewDBConnection().ToConnectionString()
-	return gorm.Open(postgres.Open(c))
+	db, err := gorm.Open(postgres.Open(c), &gorm.Config{Logger: NewGormLogger(viper.GetBool("postgres.log_sql"))})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := metrics.RegisterGormCallbacks(db); err != nil {
+		return nil, err
+	}
+	if err := tracing.RegisterGormCallbacks(db); err != nil {
+		return nil, err
+	}
+
+	poolsMu.Lock()
+	pools = append(pools, db)
+	poolsMu.Unlock()
+
+	return db, nil
+}
+
+// Ping checks that the pool of db reaches the database
+func Ping(ctx context.Context, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+
+	return sqlDB.PingContext(ctx)
+}
+
+// Close closes every pool opened by NewGormDB, on shutdown once the servers stopped. It returns the first error.
+func Close() error {
+	poolsMu.Lock()
+	defer poolsMu.Unlock()
+
+	var first error
+	for _, db := range pools {
+		sqlDB, err := db.DB()
+		if err == nil {
+			err = sqlDB.Close()
+		}
+		if err != nil && first == nil {
+			first = err
+		}
+	}
+	pools = nil
+
+	return first
 }
@@ -0,0 +1,88 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"mock-golang/logging"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// slowStatement is the duration above which a statement is logged as a warning
+const slowStatement = 200 * time.Millisecond
+
+// gormLogger writes the gorm logs through the logger of the statement context, so the SQL
+// of a request carries its request id. Outside a request it falls back to zap.L().
+type gormLogger struct {
+	level logger.LogLevel
+	// statements is the zap level every statement is logged at, errors and slow statements excepted
+	statements zapcore.Level
+}
+
+// NewGormLogger logs errors and slow statements, and every statement at Info when logSQL is set, Debug otherwise
+func NewGormLogger(logSQL bool) logger.Interface {
+	statements := zapcore.DebugLevel
+	if logSQL {
+		statements = zapcore.InfoLevel
+	}
+
+	return &gormLogger{level: logger.Info, statements: statements}
+}
+
+func (l *gormLogger) LogMode(level logger.LogLevel) logger.Interface {
+	copied := *l
+	copied.level = level
+	return &copied
+}
+
+func (l *gormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= logger.Info {
+		l.from(ctx).Info(fmt.Sprintf(msg, data...))
+	}
+}
+
+func (l *gormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= logger.Warn {
+		l.from(ctx).Warn(fmt.Sprintf(msg, data...))
+	}
+}
+
+func (l *gormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= logger.Error {
+		l.from(ctx).Error(fmt.Sprintf(msg, data...))
+	}
+}
+
+func (l *gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.level <= logger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	level := l.statements
+	msg := "sql"
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && l.level >= logger.Error:
+		level, msg = zapcore.ErrorLevel, "sql failed"
+	case elapsed > slowStatement && l.level >= logger.Warn:
+		level, msg = zapcore.WarnLevel, "slow sql"
+	case l.level < logger.Info:
+		return
+	}
+
+	log := l.from(ctx)
+	if ce := log.Check(level, msg); ce != nil {
+		sql, rows := fc()
+		ce.Write(zap.String("sql", sql), zap.Int64("rows", rows), zap.Duration("elapsed", elapsed), zap.Error(err))
+	}
+}
+
+func (l *gormLogger) from(ctx context.Context) *zap.Logger {
+	return logging.FromContext(ctx, zap.L())
+}
@@ -0,0 +1,35 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"mock-golang/logging"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"gorm.io/gorm"
+)
+
+func TestGormLoggerUsesRequestLogger(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	ctx := logging.NewContext(context.Background(), zap.New(core), "abc-123")
+	statement := func() (string, int64) { return `SELECT * FROM "flights"`, 1 }
+
+	NewGormLogger(true).Trace(ctx, time.Now(), statement, nil)
+	NewGormLogger(false).Trace(ctx, time.Now(), statement, nil)
+	NewGormLogger(false).Trace(ctx, time.Now(), statement, gorm.ErrRecordNotFound)
+	NewGormLogger(false).Trace(ctx, time.Now(), statement, errors.New("connection refused"))
+
+	entries := logs.AllUntimed()
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "sql", entries[0].Message)
+	assert.Equal(t, "sql failed", entries[1].Message)
+	for _, entry := range entries {
+		assert.Equal(t, "abc-123", entry.ContextMap()["request_id"])
+		assert.Equal(t, `SELECT * FROM "flights"`, entry.ContextMap()["sql"])
+	}
+}
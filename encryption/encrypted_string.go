@@ -0,0 +1,63 @@
+package encryption
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// EncryptedString is stored encrypted with the default keyring and read back as plaintext
+type EncryptedString string
+
+func (s EncryptedString) Value() (driver.Value, error) {
+	if s == "" {
+		return "", nil
+	}
+
+	keyring, err := Default()
+	if err != nil {
+		return nil, err
+	}
+
+	return keyring.Encrypt(string(s))
+}
+
+func (s *EncryptedString) Scan(value interface{}) error {
+	var raw string
+	switch v := value.(type) {
+	case nil:
+		*s = ""
+		return nil
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	default:
+		return fmt.Errorf("can not scan %T into EncryptedString", value)
+	}
+
+	keyring, err := Default()
+	if err != nil {
+		return err
+	}
+
+	plainText, err := keyring.Decrypt(raw)
+	if err != nil {
+		return err
+	}
+
+	*s = EncryptedString(plainText)
+	return nil
+}
+
+func (s EncryptedString) String() string {
+	return string(s)
+}
+
+// BlindIndex uses the default keyring
+func BlindIndex(value string) (string, error) {
+	keyring, err := Default()
+	if err != nil {
+		return "", err
+	}
+	return keyring.BlindIndex(value), nil
+}
@@ -0,0 +1,238 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	prefix       = "enc"
+	keyEnvPrefix = "ENCRYPTION_KEY_"
+)
+
+// Keyring holds the key-encryption keys by version. Every value gets its own
+// random data key, wrapped with the active version, so rotating only needs a
+// new active version: old values still name the version they were written with.
+type Keyring struct {
+	keys       map[string][]byte
+	active     string
+	blindIndex []byte
+}
+
+var (
+	defaultKeyring *Keyring
+	mu             sync.RWMutex
+)
+
+// Init loads the keyring from config (encryption.*), ENCRYPTION_KEY_<VERSION> and
+// ENCRYPTION_BLIND_INDEX_KEY environment variables override the config file.
+func Init() error {
+	keys := map[string]string{}
+	for version, key := range viper.GetStringMapString("encryption.keys") {
+		keys[strings.ToLower(version)] = key
+	}
+	for _, env := range os.Environ() {
+		name, value, _ := strings.Cut(env, "=")
+		if strings.HasPrefix(name, keyEnvPrefix) {
+			keys[strings.ToLower(strings.TrimPrefix(name, keyEnvPrefix))] = value
+		}
+	}
+
+	blindIndex := viper.GetString("encryption.blind_index_key")
+	if env := os.Getenv("ENCRYPTION_BLIND_INDEX_KEY"); env != "" {
+		blindIndex = env
+	}
+
+	keyring, err := NewKeyring(keys, strings.ToLower(viper.GetString("encryption.active_key")), blindIndex)
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defaultKeyring = keyring
+	mu.Unlock()
+
+	return nil
+}
+
+// NewKeyring takes base64 encoded 32 byte keys
+func NewKeyring(keys map[string]string, active string, blindIndexKey string) (*Keyring, error) {
+	keyring := &Keyring{
+		keys:   map[string][]byte{},
+		active: active,
+	}
+
+	for version, encoded := range keys {
+		if strings.Contains(version, ":") {
+			return nil, fmt.Errorf("encryption key version %q must not contain ':'", version)
+		}
+		key, err := decodeKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("encryption key %s: %w", version, err)
+		}
+		keyring.keys[version] = key
+	}
+
+	if _, ok := keyring.keys[active]; !ok {
+		return nil, fmt.Errorf("active encryption key %q is not configured", active)
+	}
+
+	blindIndex, err := decodeKey(blindIndexKey)
+	if err != nil {
+		return nil, fmt.Errorf("blind index key: %w", err)
+	}
+	keyring.blindIndex = blindIndex
+
+	return keyring, nil
+}
+
+func Default() (*Keyring, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if defaultKeyring == nil {
+		return nil, errors.New("encryption keys are not loaded, call encryption.Init")
+	}
+	return defaultKeyring, nil
+}
+
+// Encrypt returns enc:<version>:<wrapped data key>:<ciphertext>
+func (k *Keyring) Encrypt(plainText string) (string, error) {
+	dataKey := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return "", err
+	}
+
+	wrappedKey, err := seal(k.keys[k.active], dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	cipherText, err := seal(dataKey, []byte(plainText))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Join([]string{
+		prefix,
+		k.active,
+		base64.RawStdEncoding.EncodeToString(wrappedKey),
+		base64.RawStdEncoding.EncodeToString(cipherText),
+	}, ":"), nil
+}
+
+// Decrypt reads values written by any configured key version.
+// Values without the enc prefix are legacy plaintext and returned as is.
+func (k *Keyring) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	parts := strings.Split(value, ":")
+	if len(parts) != 4 {
+		return "", errors.New("encrypted value is malformed")
+	}
+
+	kek, ok := k.keys[parts[1]]
+	if !ok {
+		return "", fmt.Errorf("encryption key %q is not configured", parts[1])
+	}
+
+	wrappedKey, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", err
+	}
+
+	cipherText, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return "", err
+	}
+
+	dataKey, err := open(kek, wrappedKey)
+	if err != nil {
+		return "", err
+	}
+
+	plainText, err := open(dataKey, cipherText)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plainText), nil
+}
+
+// BlindIndex is a keyed hash of the normalized value, equal inputs give equal
+// indexes so encrypted columns can still be matched with =
+func (k *Keyring) BlindIndex(value string) string {
+	normalized := strings.ToUpper(strings.Join(strings.Fields(value), ""))
+	if normalized == "" {
+		return ""
+	}
+
+	mac := hmac.New(sha256.New, k.blindIndex)
+	mac.Write([]byte(normalized))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix+":")
+}
+
+func seal(key []byte, plainText []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, plainText, nil), nil
+}
+
+func open(key []byte, sealed []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("encrypted value is too short")
+	}
+
+	return gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func decodeKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("key must be 32 bytes, got %d", len(key))
+	}
+	return key, nil
+}
@@ -0,0 +1,74 @@
+package encryption
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	keyV1      = "vxIjOzQ0VImagF6Ofk5O0DiEhNdoW6Dj14xpegTK80k="
+	keyV2      = "IzuUDgJ0Jx7Uk3ePNHmK4ZlHbDsqtwx2hPeKqpc2nVg="
+	blindIndex = "wbTFZwCERlCfgrgV+VvL8o1fvzKVSzpaFvw9tBVDvuE="
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	keyring, err := NewKeyring(map[string]string{"v1": keyV1}, "v1", blindIndex)
+	assert.Nil(t, err)
+
+	first, err := keyring.Encrypt("079201001234")
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(first, "enc:v1:"))
+
+	second, err := keyring.Encrypt("079201001234")
+	assert.Nil(t, err)
+	assert.NotEqual(t, first, second)
+
+	plainText, err := keyring.Decrypt(first)
+	assert.Nil(t, err)
+	assert.Equal(t, "079201001234", plainText)
+
+	legacy, err := keyring.Decrypt("plain address")
+	assert.Nil(t, err)
+	assert.Equal(t, "plain address", legacy)
+}
+
+func TestKeyRotation(t *testing.T) {
+	old, err := NewKeyring(map[string]string{"v1": keyV1}, "v1", blindIndex)
+	assert.Nil(t, err)
+
+	cipherText, err := old.Encrypt("1990-01-02")
+	assert.Nil(t, err)
+
+	rotated, err := NewKeyring(map[string]string{"v1": keyV1, "v2": keyV2}, "v2", blindIndex)
+	assert.Nil(t, err)
+
+	plainText, err := rotated.Decrypt(cipherText)
+	assert.Nil(t, err)
+	assert.Equal(t, "1990-01-02", plainText)
+
+	newText, err := rotated.Encrypt("1990-01-02")
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(newText, "enc:v2:"))
+
+	_, err = old.Decrypt(newText)
+	assert.NotNil(t, err)
+}
+
+func TestBlindIndex(t *testing.T) {
+	keyring, err := NewKeyring(map[string]string{"v1": keyV1}, "v1", blindIndex)
+	assert.Nil(t, err)
+
+	assert.Equal(t, keyring.BlindIndex("b1234567"), keyring.BlindIndex(" B 1234567 "))
+	assert.NotEqual(t, keyring.BlindIndex("B1234567"), keyring.BlindIndex("B1234568"))
+	assert.Equal(t, "", keyring.BlindIndex(" "))
+}
+
+func TestNewKeyringRequiresActiveKey(t *testing.T) {
+	_, err := NewKeyring(map[string]string{"v1": keyV1}, "v2", blindIndex)
+	assert.NotNil(t, err)
+
+	_, err = NewKeyring(map[string]string{"v1": "c2hvcnQ="}, "v1", blindIndex)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,37 @@
+package audit_model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ActionAccountLocked   = "account_locked"
+	ActionAccountUnlocked = "account_unlocked"
+	ActionIpLocked        = "ip_locked"
+	ActionCustomerMerged  = "customer_merged"
+	ActionAccountClaimed  = "account_claimed"
+	ActionCustomerExport  = "customer_data_exported"
+	ActionCustomerErased  = "customer_erased"
+	ActionCustomerDeleted = "customer_deactivated"
+	ActionFlightDeleted   = "flight_deleted"
+)
+
+const (
+	TargetCustomer = "customer"
+	TargetIp       = "ip"
+	TargetFlight   = "flight"
+)
+
+// AuditLog is append only, ActorId is empty when the system acted on its own
+type AuditLog struct {
+	Id         uuid.UUID `gorm:"type:uuid;primaryKey"`
+	ActorId    string    `gorm:"column:actor_id"`
+	Action     string    `gorm:"column:action;index"`
+	TargetType string    `gorm:"column:target_type"`
+	TargetId   string    `gorm:"column:target_id;index"`
+	Detail     string    `gorm:"column:detail"`
+	Ip         string    `gorm:"column:ip"`
+	CreatedAt  time.Time `gorm:"column:created_at"`
+}
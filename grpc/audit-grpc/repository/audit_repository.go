@@ -0,0 +1,70 @@
+package audit_repo
+
+import (
+	"context"
+	"mock-golang/apperror"
+	"mock-golang/database"
+	audit_model "mock-golang/grpc/audit-grpc/model"
+
+	"gorm.io/gorm"
+)
+
+//Embeded struct
+
+type AuditRepository interface {
+	CreateAuditLog(ctx context.Context, model *audit_model.AuditLog) (*audit_model.AuditLog, error)
+	SearchAuditLog(ctx context.Context, targetType string, targetId string) ([]*audit_model.AuditLog, error)
+	SearchCustomerAuditLog(ctx context.Context, customerId string) ([]*audit_model.AuditLog, error)
+}
+
+type dbmanager struct {
+	*gorm.DB
+}
+
+func NewDBManager() (AuditRepository, error) {
+	db, err := database.NewGormDB()
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.AutoMigrate(
+		&audit_model.AuditLog{},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &dbmanager{db}, nil
+}
+
+func (m *dbmanager) CreateAuditLog(ctx context.Context, model *audit_model.AuditLog) (*audit_model.AuditLog, error) {
+	if err := m.WithContext(ctx).Create(model).Error; err != nil {
+		return nil, apperror.FromDB(err)
+	}
+
+	return model, nil
+}
+
+func (m *dbmanager) SearchAuditLog(ctx context.Context, targetType string, targetId string) ([]*audit_model.AuditLog, error) {
+	logs := []*audit_model.AuditLog{}
+
+	if err := m.WithContext(ctx).Where(&audit_model.AuditLog{TargetType: targetType, TargetId: targetId}).Order("created_at").Find(&logs).Error; err != nil {
+		return nil, apperror.FromDB(err)
+	}
+
+	return logs, nil
+}
+
+// SearchCustomerAuditLog returns what was done to the customer and what the customer did
+func (m *dbmanager) SearchCustomerAuditLog(ctx context.Context, customerId string) ([]*audit_model.AuditLog, error) {
+	logs := []*audit_model.AuditLog{}
+
+	err := m.WithContext(ctx).Where("(target_type = ? AND target_id = ?) OR actor_id = ?", audit_model.TargetCustomer, customerId, customerId).
+		Order("created_at").Find(&logs).Error
+	if err != nil {
+		return nil, apperror.FromDB(err)
+	}
+
+	return logs, nil
+}
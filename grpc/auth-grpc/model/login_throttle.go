@@ -0,0 +1,23 @@
+package auth_model
+
+import "time"
+
+// LoginThrottle counts failed password checks for one account ("account:<id>") or one client ip ("ip:<addr>")
+type LoginThrottle struct {
+	Key           string     `gorm:"column:key;primaryKey"`
+	Failures      int32      `gorm:"column:failures"`
+	LastFailureAt time.Time  `gorm:"column:last_failure_at"`
+	BlockedUntil  time.Time  `gorm:"column:blocked_until"`
+	LockedAt      *time.Time `gorm:"column:locked_at"`
+	CreatedAt     time.Time  `gorm:"column:created_at"`
+	UpdatedAt     time.Time  `gorm:"column:updated_at"`
+}
+
+// AccountThrottleKey is the key of the failures of one customer
+func AccountThrottleKey(customerId string) string {
+	return "account:" + customerId
+}
+
+func (in *LoginThrottle) IsBlocked(now time.Time) bool {
+	return now.Before(in.BlockedUntil)
+}
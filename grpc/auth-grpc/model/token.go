@@ -0,0 +1,41 @@
+package auth_model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	TokenKindAccess        = "access"
+	TokenKindRefresh       = "refresh"
+	TokenKindPasswordReset = "password_reset"
+	TokenKindEmailVerify   = "email_verification"
+	TokenKindPhoneOtp      = "phone_otp"
+	TokenKindAccountClaim  = "account_claim"
+)
+
+// Token only keeps the sha256 hash of the value handed to the customer.
+// Target is the email or phone number a verification token was sent to.
+// Attempts counts the wrong codes entered against a phone OTP, it is revoked at auth.phone_otp_max_attempts.
+type Token struct {
+	Id         uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	CustomerId string     `gorm:"column:customer_id;index"`
+	Kind       string     `gorm:"column:kind"`
+	TokenHash  string     `gorm:"column:token_hash;index"`
+	Target     string     `gorm:"column:target"`
+	Attempts   int        `gorm:"column:attempts;not null;default:0"`
+	ExpiresAt  time.Time  `gorm:"column:expires_at"`
+	UsedAt     *time.Time `gorm:"column:used_at"`
+	RevokedAt  *time.Time `gorm:"column:revoked_at"`
+	CreatedAt  time.Time  `gorm:"column:created_at"`
+	UpdatedAt  time.Time  `gorm:"column:updated_at"`
+}
+
+func (Token) TableName() string {
+	return "auth_tokens"
+}
+
+func (in *Token) IsActive(now time.Time) bool {
+	return in.UsedAt == nil && in.RevokedAt == nil && now.Before(in.ExpiresAt)
+}
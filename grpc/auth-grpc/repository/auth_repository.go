@@ -0,0 +1,170 @@
+package auth_repo
+
+import (
+	"context"
+	"mock-golang/apperror"
+	"mock-golang/database"
+	auth_model "mock-golang/grpc/auth-grpc/model"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//Embeded struct
+
+type AuthRepository interface {
+	CreateToken(ctx context.Context, model *auth_model.Token) (*auth_model.Token, error)
+	FindTokenByHash(ctx context.Context, kind string, tokenHash string) (*auth_model.Token, error)
+	FindActiveToken(ctx context.Context, customerId string, kind string) (*auth_model.Token, error)
+	FailTokenAttempt(ctx context.Context, id uuid.UUID, maxAttempts int) error
+	MarkTokenUsed(ctx context.Context, id uuid.UUID) (bool, error)
+	RevokeTokens(ctx context.Context, customerId string, kinds ...string) error
+	FindThrottle(ctx context.Context, key string) (*auth_model.LoginThrottle, error)
+	IncrementThrottle(ctx context.Context, key string) (*auth_model.LoginThrottle, error)
+	BlockThrottle(ctx context.Context, key string, blockedUntil time.Time, lockedAt *time.Time) error
+	DeleteThrottle(ctx context.Context, key string) error
+	ResetExpiredLock(ctx context.Context, key string, now time.Time) error
+}
+
+type dbmanager struct {
+	*gorm.DB
+}
+
+func NewDBManager() (AuthRepository, error) {
+	db, err := database.NewGormDB()
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.AutoMigrate(
+		&auth_model.Token{},
+		&auth_model.LoginThrottle{},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &dbmanager{db}, nil
+}
+
+func (m *dbmanager) CreateToken(ctx context.Context, model *auth_model.Token) (*auth_model.Token, error) {
+	if err := m.WithContext(ctx).Create(model).Error; err != nil {
+		return nil, apperror.FromDB(err)
+	}
+
+	return model, nil
+}
+
+func (m *dbmanager) FindTokenByHash(ctx context.Context, kind string, tokenHash string) (*auth_model.Token, error) {
+	res := auth_model.Token{}
+	if err := m.WithContext(ctx).Where(&auth_model.Token{Kind: kind, TokenHash: tokenHash}).First(&res).Error; err != nil {
+		return nil, apperror.FromDB(err)
+	}
+
+	return &res, nil
+}
+
+// FindActiveToken is the newest unused, unrevoked and unexpired token of a customer
+func (m *dbmanager) FindActiveToken(ctx context.Context, customerId string, kind string) (*auth_model.Token, error) {
+	res := auth_model.Token{}
+	err := m.WithContext(ctx).
+		Where("customer_id = ? AND kind = ? AND used_at IS NULL AND revoked_at IS NULL AND expires_at > ?", customerId, kind, time.Now()).
+		Order("created_at DESC").
+		First(&res).Error
+	if err != nil {
+		return nil, apperror.FromDB(err)
+	}
+
+	return &res, nil
+}
+
+// FailTokenAttempt counts a wrong code in one statement, the token is revoked once maxAttempts is reached
+func (m *dbmanager) FailTokenAttempt(ctx context.Context, id uuid.UUID, maxAttempts int) error {
+	now := time.Now()
+	err := m.WithContext(ctx).Model(&auth_model.Token{}).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"attempts":   gorm.Expr("attempts + 1"),
+			"revoked_at": gorm.Expr("CASE WHEN attempts + 1 >= ? THEN ?::timestamptz ELSE revoked_at END", maxAttempts, now),
+			"updated_at": now,
+		}).Error
+	if err != nil {
+		return apperror.FromDB(err)
+	}
+
+	return nil
+}
+
+// MarkTokenUsed consumes a single-use token, false means it was already used or revoked
+func (m *dbmanager) MarkTokenUsed(ctx context.Context, id uuid.UUID) (bool, error) {
+	now := time.Now()
+	res := m.WithContext(ctx).Model(&auth_model.Token{}).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{"used_at": now, "updated_at": now})
+	if res.Error != nil {
+		return false, apperror.FromDB(res.Error)
+	}
+
+	return res.RowsAffected == 1, nil
+}
+
+func (m *dbmanager) RevokeTokens(ctx context.Context, customerId string, kinds ...string) error {
+	now := time.Now()
+	return m.WithContext(ctx).Model(&auth_model.Token{}).
+		Where("customer_id = ? AND kind IN ? AND used_at IS NULL AND revoked_at IS NULL", customerId, kinds).
+		Updates(map[string]interface{}{"revoked_at": now, "updated_at": now}).Error
+}
+
+func (m *dbmanager) FindThrottle(ctx context.Context, key string) (*auth_model.LoginThrottle, error) {
+	res := auth_model.LoginThrottle{}
+	if err := m.WithContext(ctx).Where(&auth_model.LoginThrottle{Key: key}).First(&res).Error; err != nil {
+		return nil, apperror.FromDB(err)
+	}
+
+	return &res, nil
+}
+
+// IncrementThrottle adds one failure in a single upsert so concurrent attempts are all counted
+func (m *dbmanager) IncrementThrottle(ctx context.Context, key string) (*auth_model.LoginThrottle, error) {
+	now := time.Now()
+	row := &auth_model.LoginThrottle{
+		Key:           key,
+		Failures:      1,
+		LastFailureAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	err := m.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "key"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"failures":        gorm.Expr("login_throttles.failures + 1"),
+			"last_failure_at": now,
+			"updated_at":      now,
+		}),
+	}).Create(row).Error
+	if err != nil {
+		return nil, apperror.FromDB(err)
+	}
+
+	return m.FindThrottle(ctx, key)
+}
+
+func (m *dbmanager) BlockThrottle(ctx context.Context, key string, blockedUntil time.Time, lockedAt *time.Time) error {
+	return m.WithContext(ctx).Model(&auth_model.LoginThrottle{}).Where("key = ?", key).
+		Updates(map[string]interface{}{"blocked_until": blockedUntil, "locked_at": lockedAt, "updated_at": time.Now()}).Error
+}
+
+func (m *dbmanager) DeleteThrottle(ctx context.Context, key string) error {
+	return apperror.FromDB(m.WithContext(ctx).Where("key = ?", key).Delete(&auth_model.LoginThrottle{}).Error)
+}
+
+// ResetExpiredLock starts the counter over once a lock has run out, so the next failures back off and lock again
+func (m *dbmanager) ResetExpiredLock(ctx context.Context, key string, now time.Time) error {
+	return apperror.FromDB(m.WithContext(ctx).Model(&auth_model.LoginThrottle{}).
+		Where("key = ? AND locked_at IS NOT NULL AND blocked_until <= ?", key, now).
+		Updates(map[string]interface{}{"failures": 0, "locked_at": nil, "updated_at": now}).Error)
+}
@@ -0,0 +1,817 @@
+package auth_handler
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"mock-golang/apperror"
+	audit_model "mock-golang/grpc/audit-grpc/model"
+	audit_repo "mock-golang/grpc/audit-grpc/repository"
+	auth_model "mock-golang/grpc/auth-grpc/model"
+	auth_repo "mock-golang/grpc/auth-grpc/repository"
+	customer_model "mock-golang/grpc/customer-grpc/model"
+	customer_repo "mock-golang/grpc/customer-grpc/repository"
+	customer_request "mock-golang/grpc/customer-grpc/request"
+	"mock-golang/notification"
+	"mock-golang/protobuf"
+	"mock-golang/rbac"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultAccessTokenTTL  = 15 * time.Minute
+	defaultRefreshTokenTTL = 30 * 24 * time.Hour
+	defaultResetTokenTTL   = 30 * time.Minute
+	defaultEmailVerifyTTL  = 24 * time.Hour
+	defaultPhoneOtpTTL     = 5 * time.Minute
+	defaultAccountClaimTTL = 24 * time.Hour
+
+	defaultPhoneOtpMaxAttempts = 5
+)
+
+type AuthHandler struct {
+	protobuf.UnimplementedRPCAuthServer
+	authRepository     auth_repo.AuthRepository
+	customerRepository customer_repo.CustomerRepository
+	auditRepository    audit_repo.AuditRepository
+	sender             notification.Sender
+	guard              *PasswordGuard
+	mu                 *sync.Mutex
+}
+
+func NewAuthHandler(
+	authRepository auth_repo.AuthRepository,
+	customerRepository customer_repo.CustomerRepository,
+	auditRepository audit_repo.AuditRepository,
+	sender notification.Sender,
+	guard *PasswordGuard) (*AuthHandler, error) {
+	return &AuthHandler{
+		authRepository:     authRepository,
+		customerRepository: customerRepository,
+		auditRepository:    auditRepository,
+		sender:             sender,
+		guard:              guard,
+		mu:                 &sync.Mutex{},
+	}, nil
+}
+
+func (h *AuthHandler) Login(ctx context.Context, in *protobuf.LoginRequest) (*protobuf.TokenResponse, error) {
+	customer, err := h.customerRepository.FindByRegisteredEmail(ctx, in.Email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Unknown emails still count against the caller's ip
+			if err := h.guard.Check(ctx, ""); err != nil {
+				return nil, err
+			}
+			if err := h.guard.Fail(ctx, ""); err != nil {
+				return nil, err
+			}
+			return nil, status.Error(codes.Unauthenticated, "email or password is incorrect")
+		}
+		return nil, apperror.ToStatus(err)
+	}
+
+	if err := h.guard.Check(ctx, customer.Id.String()); err != nil {
+		return nil, err
+	}
+
+	if customer.Password == "" || subtle.ConstantTimeCompare([]byte(customer.Password), []byte(in.Password)) != 1 {
+		if err := h.guard.Fail(ctx, customer.Id.String()); err != nil {
+			return nil, err
+		}
+		return nil, status.Error(codes.Unauthenticated, "email or password is incorrect")
+	}
+
+	if err := h.guard.Succeed(ctx, customer.Id.String()); err != nil {
+		return nil, err
+	}
+
+	return h.issueTokens(ctx, customer.Id.String())
+}
+
+func (h *AuthHandler) RefreshToken(ctx context.Context, in *protobuf.RefreshTokenRequest) (*protobuf.TokenResponse, error) {
+	token, err := h.authRepository.FindTokenByHash(ctx, auth_model.TokenKindRefresh, hashToken(in.RefreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Error(codes.Unauthenticated, "refresh token is invalid")
+		}
+		return nil, apperror.ToStatus(err)
+	}
+
+	if !token.IsActive(time.Now()) {
+		return nil, status.Error(codes.Unauthenticated, "refresh token is expired or revoked")
+	}
+
+	// Refresh tokens rotate: the presented one can not be used twice
+	used, err := h.authRepository.MarkTokenUsed(ctx, token.Id)
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+	if !used {
+		return nil, status.Error(codes.Unauthenticated, "refresh token is expired or revoked")
+	}
+
+	return h.issueTokens(ctx, token.CustomerId)
+}
+
+func (h *AuthHandler) ForgotPassword(ctx context.Context, in *protobuf.ForgotPasswordRequest) (*protobuf.AuthResponse, error) {
+	// Same answer whether the email exists or not, so it can not be used to probe accounts
+	out := &protobuf.AuthResponse{
+		Code:    0,
+		Message: "If the email is registered, a reset link has been sent",
+	}
+
+	customer, err := h.customerRepository.FindByRegisteredEmail(ctx, in.Email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return out, nil
+		}
+		return nil, apperror.ToStatus(err)
+	}
+
+	// An unverified email may not belong to the customer
+	if customer.EmailVerifiedAt == nil {
+		return out, nil
+	}
+
+	// Only the latest reset link stays valid
+	if err := h.authRepository.RevokeTokens(ctx, customer.Id.String(), auth_model.TokenKindPasswordReset); err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	rawToken, token, err := h.newToken(customer.Id.String(), auth_model.TokenKindPasswordReset, ttl("auth.reset_token_ttl", defaultResetTokenTTL))
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	if _, err := h.authRepository.CreateToken(ctx, token); err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	err = h.sender.Send(ctx, &notification.Event{
+		Type:       notification.EventPasswordResetRequested,
+		CustomerId: customer.Id.String(),
+		Email:      customer.Email,
+		Data: map[string]string{
+			"token":      rawToken,
+			"reset_url":  linkURL("auth.reset_url", rawToken),
+			"expires_at": token.ExpiresAt.Format(time.RFC3339),
+		},
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	return out, nil
+}
+
+func (h *AuthHandler) ResetPassword(ctx context.Context, in *protobuf.ResetPasswordRequest) (*protobuf.AuthResponse, error) {
+	if strings.TrimSpace(in.NewPassword) == "" {
+		return nil, status.Error(codes.InvalidArgument, "new password is required")
+	}
+
+	token, err := h.consumeToken(ctx, auth_model.TokenKindPasswordReset, hashToken(in.Token))
+	if err != nil {
+		return nil, err
+	}
+
+	customer, err := h.findCustomer(ctx, token.CustomerId)
+	if err != nil {
+		return nil, err
+	}
+
+	customer.Password = in.NewPassword
+	customer.UpdatedAt = time.Now()
+
+	if _, err := h.customerRepository.UpdateCustomer(ctx, customer); err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	// Sign out every session that may have been opened with the old password
+	err = h.authRepository.RevokeTokens(ctx, token.CustomerId,
+		auth_model.TokenKindAccess,
+		auth_model.TokenKindRefresh,
+		auth_model.TokenKindPasswordReset)
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	// The password is already changed, a lost confirmation must not fail the request
+	_ = h.sender.Send(ctx, &notification.Event{
+		Type:       notification.EventPasswordResetCompleted,
+		CustomerId: token.CustomerId,
+		Email:      customer.Email,
+		CreatedAt:  time.Now(),
+	})
+
+	out := &protobuf.AuthResponse{
+		Code:    0,
+		Message: "Success",
+	}
+
+	return out, nil
+}
+
+func (h *AuthHandler) SendEmailVerification(ctx context.Context, in *protobuf.VerificationRequest) (*protobuf.AuthResponse, error) {
+	customer, err := h.findCustomer(ctx, in.CustomerId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := rbac.RequireOwnOrAny(ctx, customer.Id.String(), rbac.PermCustomerWriteOwn, rbac.PermCustomerWriteAny); err != nil {
+		return nil, err
+	}
+
+	if customer.EmailVerifiedAt != nil {
+		return &protobuf.AuthResponse{Code: 0, Message: "Email already verified"}, nil
+	}
+
+	if err := h.authRepository.RevokeTokens(ctx, customer.Id.String(), auth_model.TokenKindEmailVerify); err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	rawToken, token, err := h.newToken(customer.Id.String(), auth_model.TokenKindEmailVerify, ttl("auth.email_verification_ttl", defaultEmailVerifyTTL))
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+	token.Target = customer.Email
+
+	if _, err := h.authRepository.CreateToken(ctx, token); err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	err = h.sender.Send(ctx, &notification.Event{
+		Type:       notification.EventEmailVerification,
+		CustomerId: customer.Id.String(),
+		Email:      customer.Email,
+		Data: map[string]string{
+			"token":      rawToken,
+			"verify_url": linkURL("auth.verify_email_url", rawToken),
+			"expires_at": token.ExpiresAt.Format(time.RFC3339),
+		},
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	out := &protobuf.AuthResponse{
+		Code:    0,
+		Message: "Verification link has been sent",
+	}
+
+	return out, nil
+}
+
+func (h *AuthHandler) VerifyEmail(ctx context.Context, in *protobuf.VerifyEmailRequest) (*protobuf.AuthResponse, error) {
+	token, err := h.consumeToken(ctx, auth_model.TokenKindEmailVerify, hashToken(in.Token))
+	if err != nil {
+		return nil, err
+	}
+
+	customer, err := h.findCustomer(ctx, token.CustomerId)
+	if err != nil {
+		return nil, err
+	}
+
+	// The link only proves the address it was sent to
+	if !strings.EqualFold(customer.Email, token.Target) {
+		return nil, status.Error(codes.FailedPrecondition, "email has changed since the link was sent")
+	}
+
+	now := time.Now()
+	if err := h.customerRepository.SetEmailVerifiedAt(ctx, customer.Id, &now); err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	out := &protobuf.AuthResponse{
+		Code:    0,
+		Message: "Success",
+	}
+
+	return out, nil
+}
+
+func (h *AuthHandler) SendPhoneOtp(ctx context.Context, in *protobuf.VerificationRequest) (*protobuf.AuthResponse, error) {
+	customer, err := h.findCustomer(ctx, in.CustomerId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := rbac.RequireOwnOrAny(ctx, customer.Id.String(), rbac.PermCustomerWriteOwn, rbac.PermCustomerWriteAny); err != nil {
+		return nil, err
+	}
+
+	if customer.PhoneVerifiedAt != nil {
+		return &protobuf.AuthResponse{Code: 0, Message: "Phone number already verified"}, nil
+	}
+
+	if strings.TrimSpace(customer.PhoneNumber) == "" {
+		return nil, status.Error(codes.FailedPrecondition, "customer has no phone number")
+	}
+
+	if err := h.authRepository.RevokeTokens(ctx, customer.Id.String(), auth_model.TokenKindPhoneOtp); err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	code, err := generateOtp()
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	token := h.buildToken(customer.Id.String(), auth_model.TokenKindPhoneOtp, hashOtp(customer.Id.String(), code), ttl("auth.phone_otp_ttl", defaultPhoneOtpTTL))
+	token.Target = customer.PhoneNumber
+
+	if _, err := h.authRepository.CreateToken(ctx, token); err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	err = h.sender.Send(ctx, &notification.Event{
+		Type:       notification.EventPhoneOtp,
+		CustomerId: customer.Id.String(),
+		Phone:      customer.PhoneNumber,
+		Data: map[string]string{
+			"code":       code,
+			"expires_at": token.ExpiresAt.Format(time.RFC3339),
+		},
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	out := &protobuf.AuthResponse{
+		Code:    0,
+		Message: "OTP has been sent",
+	}
+
+	return out, nil
+}
+
+func (h *AuthHandler) VerifyPhone(ctx context.Context, in *protobuf.VerifyPhoneRequest) (*protobuf.AuthResponse, error) {
+	customer, err := h.findCustomer(ctx, in.CustomerId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := rbac.RequireOwnOrAny(ctx, customer.Id.String(), rbac.PermCustomerWriteOwn, rbac.PermCustomerWriteAny); err != nil {
+		return nil, err
+	}
+
+	token, err := h.consumeOtp(ctx, customer.Id.String(), in.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	if customer.PhoneNumber != token.Target {
+		return nil, status.Error(codes.FailedPrecondition, "phone number has changed since the OTP was sent")
+	}
+
+	now := time.Now()
+	if err := h.customerRepository.SetPhoneVerifiedAt(ctx, customer.Id, &now); err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	out := &protobuf.AuthResponse{
+		Code:    0,
+		Message: "Success",
+	}
+
+	return out, nil
+}
+
+func (h *AuthHandler) Authenticate(ctx context.Context, in *protobuf.AuthenticateRequest) (*protobuf.PrincipalResponse, error) {
+	principal, err := h.ResolvePrincipal(ctx, in.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &protobuf.PrincipalResponse{
+		CustomerId:  principal.CustomerId,
+		Role:        int32(principal.Role),
+		RoleName:    principal.Role.String(),
+		Permissions: []string{},
+	}
+
+	for _, perm := range principal.Role.Permissions() {
+		out.Permissions = append(out.Permissions, string(perm))
+	}
+
+	return out, nil
+}
+
+func (h *AuthHandler) UnlockAccount(ctx context.Context, in *protobuf.VerificationRequest) (*protobuf.AuthResponse, error) {
+	if err := rbac.Require(ctx, rbac.PermAccountUnlock); err != nil {
+		return nil, err
+	}
+
+	customer, err := h.findCustomer(ctx, in.CustomerId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.guard.Unlock(ctx, customer.Id.String(), rbac.FromContext(ctx).CustomerId); err != nil {
+		return nil, err
+	}
+
+	out := &protobuf.AuthResponse{
+		Code:    0,
+		Message: "Success",
+	}
+
+	return out, nil
+}
+
+// RequestAccountClaim mails a claim link to the guest records booked with the email
+func (h *AuthHandler) RequestAccountClaim(ctx context.Context, in *protobuf.ForgotPasswordRequest) (*protobuf.AuthResponse, error) {
+	out := &protobuf.AuthResponse{
+		Code:    0,
+		Message: "If guest bookings exist for the email, a claim link has been sent",
+	}
+
+	if strings.TrimSpace(in.Email) == "" {
+		return nil, status.Error(codes.InvalidArgument, "email is required")
+	}
+
+	guest, err := h.findClaimableGuest(ctx, in.Email)
+	if err != nil {
+		return nil, err
+	}
+	if guest == nil {
+		return out, nil
+	}
+
+	if err := h.authRepository.RevokeTokens(ctx, guest.Id.String(), auth_model.TokenKindAccountClaim); err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	rawToken, token, err := h.newToken(guest.Id.String(), auth_model.TokenKindAccountClaim, ttl("auth.account_claim_ttl", defaultAccountClaimTTL))
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+	token.Target = guest.Email
+
+	if _, err := h.authRepository.CreateToken(ctx, token); err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	err = h.sender.Send(ctx, &notification.Event{
+		Type:       notification.EventAccountClaim,
+		CustomerId: guest.Id.String(),
+		Email:      guest.Email,
+		Data: map[string]string{
+			"token":      rawToken,
+			"claim_url":  linkURL("auth.claim_account_url", rawToken),
+			"expires_at": token.ExpiresAt.Format(time.RFC3339),
+		},
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	return out, nil
+}
+
+// ClaimAccount turns a guest into a registered customer. Following the link proves
+// the email, so other guest records booked with it are merged into the claimed one.
+func (h *AuthHandler) ClaimAccount(ctx context.Context, in *protobuf.ClaimAccountRequest) (*protobuf.TokenResponse, error) {
+	if strings.TrimSpace(in.Password) == "" {
+		return nil, status.Error(codes.InvalidArgument, "password is required")
+	}
+
+	token, err := h.consumeToken(ctx, auth_model.TokenKindAccountClaim, hashToken(in.Token))
+	if err != nil {
+		return nil, err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	customer, err := h.findCustomer(ctx, token.CustomerId)
+	if err != nil {
+		return nil, err
+	}
+
+	if customer.Role != int32(rbac.RoleGuest) || customer.Password != "" || customer.MergedIntoId != "" {
+		return nil, status.Error(codes.FailedPrecondition, "account is already claimed")
+	}
+	if !strings.EqualFold(customer.Email, token.Target) {
+		return nil, status.Error(codes.FailedPrecondition, "email changed since the claim link was sent")
+	}
+
+	// Registered accounts log in by email, it must stay unique among them
+	if _, err := h.customerRepository.FindByRegisteredEmail(ctx, customer.Email); err == nil {
+		return nil, status.Error(codes.FailedPrecondition, "email already belongs to a registered account")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.ToStatus(err)
+	}
+
+	now := time.Now()
+	customer.Password = in.Password
+	customer.Status = 1
+	customer.UpdatedAt = now
+
+	if _, err := h.customerRepository.UpdateCustomer(ctx, customer); err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+	if err := h.customerRepository.SetRole(ctx, customer.Id, int32(rbac.RoleCustomer)); err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+	if err := h.customerRepository.SetEmailVerifiedAt(ctx, customer.Id, &now); err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	guests, _, err := h.customerRepository.SearchCustomer(ctx, &customer_request.SearchCustomerRequest{
+		Email:  customer.Email,
+		Role:   int32(rbac.RoleGuest),
+		Status: -1,
+	})
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	sourceIds := []uuid.UUID{}
+	for _, guest := range guests {
+		if guest.Id != customer.Id && guest.Password == "" {
+			sourceIds = append(sourceIds, guest.Id)
+		}
+	}
+
+	detail := "guest account claimed"
+	if len(sourceIds) > 0 {
+		moved, err := h.customerRepository.MergeCustomers(ctx, customer.Id, sourceIds)
+		if err != nil {
+			return nil, apperror.ToStatus(err)
+		}
+		detail = fmt.Sprintf("guest account claimed, %d guest records and %d bookings merged", len(sourceIds), moved)
+	}
+
+	_, err = h.auditRepository.CreateAuditLog(ctx, &audit_model.AuditLog{
+		Id:         uuid.New(),
+		ActorId:    customer.Id.String(),
+		Action:     audit_model.ActionAccountClaimed,
+		TargetType: audit_model.TargetCustomer,
+		TargetId:   customer.Id.String(),
+		Detail:     detail,
+		Ip:         ClientIp(ctx),
+		CreatedAt:  now,
+	})
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	return h.issueTokens(ctx, customer.Id.String())
+}
+
+// findClaimableGuest returns nil when the email has no guest record or is already registered
+func (h *AuthHandler) findClaimableGuest(ctx context.Context, email string) (*customer_model.Customer, error) {
+	if _, err := h.customerRepository.FindByRegisteredEmail(ctx, email); err == nil {
+		return nil, nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.ToStatus(err)
+	}
+
+	guests, _, err := h.customerRepository.SearchCustomer(ctx, &customer_request.SearchCustomerRequest{
+		Email:  email,
+		Role:   int32(rbac.RoleGuest),
+		Status: -1,
+	})
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	// The oldest guest record survives, later ones are merged into it on claim
+	var oldest *customer_model.Customer
+	for _, guest := range guests {
+		if guest.Password != "" {
+			continue
+		}
+		if oldest == nil || guest.CreatedAt.Before(oldest.CreatedAt) {
+			oldest = guest
+		}
+	}
+
+	return oldest, nil
+}
+
+// ResolvePrincipal turns an access token into the calling customer and its role
+func (h *AuthHandler) ResolvePrincipal(ctx context.Context, accessToken string) (rbac.Principal, error) {
+	token, err := h.authRepository.FindTokenByHash(ctx, auth_model.TokenKindAccess, hashToken(accessToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return rbac.Principal{}, status.Error(codes.Unauthenticated, "access token is invalid")
+		}
+		return rbac.Principal{}, apperror.ToStatus(err)
+	}
+
+	if !token.IsActive(time.Now()) {
+		return rbac.Principal{}, status.Error(codes.Unauthenticated, "access token is expired or revoked")
+	}
+
+	customer, err := h.findCustomer(ctx, token.CustomerId)
+	if err != nil {
+		// Deactivated customers are not found anymore, their sessions end with it
+		if status.Code(err) == codes.NotFound {
+			return rbac.Principal{}, status.Error(codes.Unauthenticated, "account is deactivated")
+		}
+		return rbac.Principal{}, err
+	}
+
+	// Sessions of a merged record end with the merge
+	if customer.MergedIntoId != "" {
+		return rbac.Principal{}, status.Error(codes.Unauthenticated, "account was merged into another customer")
+	}
+
+	if customer.ErasedAt != nil {
+		return rbac.Principal{}, status.Error(codes.Unauthenticated, "account was erased")
+	}
+
+	principal := rbac.Principal{
+		CustomerId: customer.Id.String(),
+		Role:       rbac.Role(customer.Role),
+	}
+
+	return principal, nil
+}
+
+// consumeToken looks up a single-use token and marks it used
+func (h *AuthHandler) consumeToken(ctx context.Context, kind string, tokenHash string) (*auth_model.Token, error) {
+	token, err := h.authRepository.FindTokenByHash(ctx, kind, tokenHash)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Error(codes.InvalidArgument, "token is invalid or expired")
+		}
+		return nil, apperror.ToStatus(err)
+	}
+
+	if !token.IsActive(time.Now()) {
+		return nil, status.Error(codes.InvalidArgument, "token is invalid or expired")
+	}
+
+	used, err := h.authRepository.MarkTokenUsed(ctx, token.Id)
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+	if !used {
+		return nil, status.Error(codes.InvalidArgument, "token is invalid or expired")
+	}
+
+	return token, nil
+}
+
+// consumeOtp checks the code against the pending OTP of the customer. A 6 digit code is easy to guess,
+// so every wrong code is counted and the OTP is revoked after auth.phone_otp_max_attempts of them.
+func (h *AuthHandler) consumeOtp(ctx context.Context, customerId string, code string) (*auth_model.Token, error) {
+	token, err := h.authRepository.FindActiveToken(ctx, customerId, auth_model.TokenKindPhoneOtp)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Error(codes.InvalidArgument, "code is invalid or expired")
+		}
+		return nil, apperror.ToStatus(err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(token.TokenHash), []byte(hashOtp(customerId, code))) != 1 {
+		maxAttempts := viper.GetInt("auth.phone_otp_max_attempts")
+		if maxAttempts <= 0 {
+			maxAttempts = defaultPhoneOtpMaxAttempts
+		}
+		if err := h.authRepository.FailTokenAttempt(ctx, token.Id, maxAttempts); err != nil {
+			return nil, apperror.ToStatus(err)
+		}
+		if token.Attempts+1 >= maxAttempts {
+			return nil, status.Error(codes.ResourceExhausted, "too many wrong codes, request a new one")
+		}
+		return nil, status.Error(codes.InvalidArgument, "code is invalid or expired")
+	}
+
+	used, err := h.authRepository.MarkTokenUsed(ctx, token.Id)
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+	if !used {
+		return nil, status.Error(codes.InvalidArgument, "code is invalid or expired")
+	}
+
+	return token, nil
+}
+
+func (h *AuthHandler) findCustomer(ctx context.Context, id string) (*customer_model.Customer, error) {
+	customerId, err := apperror.ParseID("customerId", id)
+	if err != nil {
+		return nil, err
+	}
+
+	customer, err := h.customerRepository.FindById(ctx, customerId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		return nil, apperror.ToStatus(err)
+	}
+
+	return customer, nil
+}
+
+func (h *AuthHandler) issueTokens(ctx context.Context, customerId string) (*protobuf.TokenResponse, error) {
+	accessTTL := ttl("auth.access_token_ttl", defaultAccessTokenTTL)
+
+	accessToken, access, err := h.newToken(customerId, auth_model.TokenKindAccess, accessTTL)
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	refreshToken, refresh, err := h.newToken(customerId, auth_model.TokenKindRefresh, ttl("auth.refresh_token_ttl", defaultRefreshTokenTTL))
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	for _, token := range []*auth_model.Token{access, refresh} {
+		if _, err := h.authRepository.CreateToken(ctx, token); err != nil {
+			return nil, apperror.ToStatus(err)
+		}
+	}
+
+	out := &protobuf.TokenResponse{
+		CustomerId:   customerId,
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(accessTTL.Seconds()),
+	}
+
+	return out, nil
+}
+
+// newToken returns the raw value for the customer and the hashed model to store
+func (h *AuthHandler) newToken(customerId string, kind string, ttl time.Duration) (string, *auth_model.Token, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", nil, err
+	}
+
+	rawToken := base64.RawURLEncoding.EncodeToString(b)
+
+	return rawToken, h.buildToken(customerId, kind, hashToken(rawToken), ttl), nil
+}
+
+func (h *AuthHandler) buildToken(customerId string, kind string, tokenHash string, ttl time.Duration) *auth_model.Token {
+	now := time.Now()
+
+	return &auth_model.Token{
+		Id:         uuid.New(),
+		CustomerId: customerId,
+		Kind:       kind,
+		TokenHash:  tokenHash,
+		ExpiresAt:  now.Add(ttl),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+func hashToken(rawToken string) string {
+	sum := sha256.Sum256([]byte(rawToken))
+	return hex.EncodeToString(sum[:])
+}
+
+// hashOtp binds the short code to the customer, codes alone collide between customers
+func hashOtp(customerId string, code string) string {
+	return hashToken(customerId + ":" + code)
+}
+
+func generateOtp() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+func ttl(key string, def time.Duration) time.Duration {
+	if d := viper.GetDuration(key); d > 0 {
+		return d
+	}
+	return def
+}
+
+func linkURL(key string, rawToken string) string {
+	base := viper.GetString(key)
+	if base == "" {
+		return ""
+	}
+	return base + "?token=" + rawToken
+}
@@ -0,0 +1,208 @@
+package auth_handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mock-golang/apperror"
+	"mock-golang/clientip"
+	audit_model "mock-golang/grpc/audit-grpc/model"
+	audit_repo "mock-golang/grpc/audit-grpc/repository"
+	auth_model "mock-golang/grpc/auth-grpc/model"
+	auth_repo "mock-golang/grpc/auth-grpc/repository"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultAccountThreshold = 5
+	defaultIpThreshold      = 20
+	defaultLockoutDuration  = 15 * time.Minute
+	defaultBackoffBase      = time.Second
+	defaultBackoffMax       = 15 * time.Minute
+)
+
+// PasswordGuard protects every password check against guessing: each failure
+// delays the next attempt exponentially per account and per client ip, and
+// too many failures lock the account (or ip) for a while.
+type PasswordGuard struct {
+	authRepository  auth_repo.AuthRepository
+	auditRepository audit_repo.AuditRepository
+}
+
+func NewPasswordGuard(authRepository auth_repo.AuthRepository, auditRepository audit_repo.AuditRepository) *PasswordGuard {
+	return &PasswordGuard{
+		authRepository:  authRepository,
+		auditRepository: auditRepository,
+	}
+}
+
+// Check fails with ResourceExhausted while the account or the caller's ip is blocked
+func (g *PasswordGuard) Check(ctx context.Context, customerId string) error {
+	now := time.Now()
+	for _, key := range throttleKeys(customerId, ClientIp(ctx)) {
+		throttle, err := g.authRepository.FindThrottle(ctx, key)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			}
+			return apperror.ToStatus(err)
+		}
+
+		if !throttle.IsBlocked(now) {
+			continue
+		}
+
+		if throttle.LockedAt != nil {
+			return status.Errorf(codes.ResourceExhausted, "too many failed attempts, locked until %s", throttle.BlockedUntil.Format(time.RFC3339))
+		}
+		return status.Errorf(codes.ResourceExhausted, "too many failed attempts, retry in %s", throttle.BlockedUntil.Sub(now).Round(time.Second))
+	}
+
+	return nil
+}
+
+// Fail records a wrong password for the account (may be empty for unknown emails) and the caller's ip
+func (g *PasswordGuard) Fail(ctx context.Context, customerId string) error {
+	ip := ClientIp(ctx)
+	now := time.Now()
+
+	for _, key := range throttleKeys(customerId, ip) {
+		if err := g.authRepository.ResetExpiredLock(ctx, key, now); err != nil {
+			return apperror.ToStatus(err)
+		}
+
+		throttle, err := g.authRepository.IncrementThrottle(ctx, key)
+		if err != nil {
+			return apperror.ToStatus(err)
+		}
+
+		threshold := intConfig("auth.lockout_threshold", defaultAccountThreshold)
+		targetType, targetId := audit_model.TargetCustomer, customerId
+		action := audit_model.ActionAccountLocked
+		if key == ipKey(ip) {
+			threshold = intConfig("auth.ip_lockout_threshold", defaultIpThreshold)
+			targetType, targetId = audit_model.TargetIp, ip
+			action = audit_model.ActionIpLocked
+		}
+
+		if throttle.LockedAt == nil && throttle.Failures >= int32(threshold) {
+			blockedUntil := now.Add(ttl("auth.lockout_duration", defaultLockoutDuration))
+			if err := g.authRepository.BlockThrottle(ctx, key, blockedUntil, &now); err != nil {
+				return apperror.ToStatus(err)
+			}
+
+			err := g.audit(ctx, "", action, targetType, targetId, fmt.Sprintf("%d failed password attempts, locked until %s", throttle.Failures, blockedUntil.Format(time.RFC3339)))
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Still locked, Check turns these away before the password is compared
+		if throttle.LockedAt != nil {
+			continue
+		}
+
+		if err := g.authRepository.BlockThrottle(ctx, key, now.Add(backoff(throttle.Failures)), nil); err != nil {
+			return apperror.ToStatus(err)
+		}
+	}
+
+	return nil
+}
+
+// Succeed clears the account counter, the ip counter only decays with its backoff
+func (g *PasswordGuard) Succeed(ctx context.Context, customerId string) error {
+	if err := g.authRepository.DeleteThrottle(ctx, accountKey(customerId)); err != nil {
+		return apperror.ToStatus(err)
+	}
+	return nil
+}
+
+// Unlock removes the lock of an account before it expires
+func (g *PasswordGuard) Unlock(ctx context.Context, customerId string, actorId string) error {
+	if err := g.authRepository.DeleteThrottle(ctx, accountKey(customerId)); err != nil {
+		return apperror.ToStatus(err)
+	}
+
+	return g.audit(ctx, actorId, audit_model.ActionAccountUnlocked, audit_model.TargetCustomer, customerId, "unlocked by admin")
+}
+
+func (g *PasswordGuard) audit(ctx context.Context, actorId string, action string, targetType string, targetId string, detail string) error {
+	_, err := g.auditRepository.CreateAuditLog(ctx, &audit_model.AuditLog{
+		Id:         uuid.New(),
+		ActorId:    actorId,
+		Action:     action,
+		TargetType: targetType,
+		TargetId:   targetId,
+		Detail:     detail,
+		Ip:         ClientIp(ctx),
+		CreatedAt:  time.Now(),
+	})
+	if err != nil {
+		return apperror.ToStatus(err)
+	}
+	return nil
+}
+
+// ClientIp returns the address of the end user behind the call, resolved by the client ip intercepter.
+// Without it only the peer address is used, never what the caller claims.
+func ClientIp(ctx context.Context) string {
+	if ip := clientip.FromContext(ctx); ip != "" {
+		return ip
+	}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return clientip.Host(p.Addr.String())
+	}
+
+	return ""
+}
+
+func throttleKeys(customerId string, ip string) []string {
+	keys := []string{}
+	if customerId != "" {
+		keys = append(keys, accountKey(customerId))
+	}
+	if ip != "" {
+		keys = append(keys, ipKey(ip))
+	}
+	return keys
+}
+
+func accountKey(customerId string) string {
+	return auth_model.AccountThrottleKey(customerId)
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
+
+// backoff doubles the wait after every failure: 1s, 2s, 4s ... up to auth.backoff_max
+func backoff(failures int32) time.Duration {
+	base := ttl("auth.backoff_base", defaultBackoffBase)
+	max := ttl("auth.backoff_max", defaultBackoffMax)
+
+	d := base
+	for i := int32(1); i < failures; i++ {
+		d *= 2
+		if d >= max {
+			return max
+		}
+	}
+	return d
+}
+
+func intConfig(key string, def int) int {
+	if v := viper.GetInt(key); v > 0 {
+		return v
+	}
+	return def
+}
@@ -0,0 +1,124 @@
+package auth_handler
+
+import (
+	"context"
+	"mock-golang/apperror"
+	"mock-golang/clientip"
+	audit_model "mock-golang/grpc/audit-grpc/model"
+	audit_repo "mock-golang/grpc/audit-grpc/repository"
+	auth_model "mock-golang/grpc/auth-grpc/model"
+	auth_repo "mock-golang/grpc/auth-grpc/repository"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// throttleStore keeps the login_throttles rows in memory, the other methods are not used by the guard
+type throttleStore struct {
+	auth_repo.AuthRepository
+	rows map[string]*auth_model.LoginThrottle
+}
+
+func (s *throttleStore) FindThrottle(ctx context.Context, key string) (*auth_model.LoginThrottle, error) {
+	row, ok := s.rows[key]
+	if !ok {
+		return nil, apperror.FromDB(gorm.ErrRecordNotFound)
+	}
+	res := *row
+	return &res, nil
+}
+
+func (s *throttleStore) IncrementThrottle(ctx context.Context, key string) (*auth_model.LoginThrottle, error) {
+	if _, ok := s.rows[key]; !ok {
+		s.rows[key] = &auth_model.LoginThrottle{Key: key}
+	}
+	s.rows[key].Failures++
+	return s.FindThrottle(ctx, key)
+}
+
+func (s *throttleStore) BlockThrottle(ctx context.Context, key string, blockedUntil time.Time, lockedAt *time.Time) error {
+	s.rows[key].BlockedUntil = blockedUntil
+	s.rows[key].LockedAt = lockedAt
+	return nil
+}
+
+func (s *throttleStore) DeleteThrottle(ctx context.Context, key string) error {
+	delete(s.rows, key)
+	return nil
+}
+
+func (s *throttleStore) ResetExpiredLock(ctx context.Context, key string, now time.Time) error {
+	if row, ok := s.rows[key]; ok && row.LockedAt != nil && !row.BlockedUntil.After(now) {
+		row.Failures = 0
+		row.LockedAt = nil
+	}
+	return nil
+}
+
+type auditStore struct {
+	audit_repo.AuditRepository
+	logs []*audit_model.AuditLog
+}
+
+func (s *auditStore) CreateAuditLog(ctx context.Context, model *audit_model.AuditLog) (*audit_model.AuditLog, error) {
+	s.logs = append(s.logs, model)
+	return model, nil
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, time.Second, backoff(1))
+	assert.Equal(t, 2*time.Second, backoff(2))
+	assert.Equal(t, 8*time.Second, backoff(4))
+	assert.Equal(t, defaultBackoffMax, backoff(40))
+}
+
+func TestClientIp(t *testing.T) {
+	assert.Equal(t, "", ClientIp(context.Background()))
+
+	// what the caller claims is ignored, the peer address is used
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(clientip.Metadata, "10.0.0.7"))
+	ctx = peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("203.0.113.9"), Port: 5123}})
+	assert.Equal(t, "203.0.113.9", ClientIp(ctx))
+
+	ctx = clientip.NewContext(ctx, "10.0.0.7")
+	assert.Equal(t, "10.0.0.7", ClientIp(ctx))
+	assert.Equal(t, []string{"account:c1", "ip:10.0.0.7"}, throttleKeys("c1", ClientIp(ctx)))
+	assert.Equal(t, []string{"ip:10.0.0.7"}, throttleKeys("", "10.0.0.7"))
+}
+
+func TestPasswordGuardLocksAgainAfterExpiry(t *testing.T) {
+	ctx := context.Background()
+	throttles := &throttleStore{rows: map[string]*auth_model.LoginThrottle{}}
+	audits := &auditStore{}
+	guard := NewPasswordGuard(throttles, audits)
+
+	for i := 0; i < defaultAccountThreshold; i++ {
+		assert.Nil(t, guard.Fail(ctx, "c1"))
+	}
+	assert.NotNil(t, throttles.rows["account:c1"].LockedAt)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(guard.Check(ctx, "c1")))
+	assert.Len(t, audits.logs, 1)
+
+	// the lock runs out: the next failure starts over with the shortest backoff
+	throttles.rows["account:c1"].BlockedUntil = time.Now().Add(-time.Minute)
+	assert.Nil(t, guard.Check(ctx, "c1"))
+	assert.Nil(t, guard.Fail(ctx, "c1"))
+	assert.Nil(t, throttles.rows["account:c1"].LockedAt)
+	assert.Equal(t, int32(1), throttles.rows["account:c1"].Failures)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(guard.Check(ctx, "c1")))
+
+	// and crossing the threshold again locks again
+	for i := 1; i < defaultAccountThreshold; i++ {
+		assert.Nil(t, guard.Fail(ctx, "c1"))
+	}
+	assert.NotNil(t, throttles.rows["account:c1"].LockedAt)
+	assert.Len(t, audits.logs, 2)
+	assert.Equal(t, audit_model.ActionAccountLocked, audits.logs[1].Action)
+}
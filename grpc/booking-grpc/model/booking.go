@@ -1,6 +1,7 @@
 package booking_model
 
 import (
+	"fmt"
 	customer_model "mock-golang/grpc/customer-grpc/model"
 	flight_model "mock-golang/grpc/flight-grpc/model"
 	"time"
@@ -9,6 +10,15 @@ import (
 
 	"github.com/google/uuid"
 	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+const (
+	StatusActive = "Active"
+	StatusCancel = "Cancel"
+	// Bookings breaking the travel policy of the organization wait for an approver
+	StatusPendingApproval = "PendingApproval"
+	StatusRejected        = "Rejected"
 )
 
 type Booking struct {
@@ -23,6 +33,66 @@ type Booking struct {
 	UpdatedAt  time.Time                `gorm:"column:updated_at"`
 	Customer   *customer_model.Customer `gorm:"foreignKey:customer_id;references:Id;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
 	Flight     *flight_model.Flight     `gorm:"foreignKey:flight_id;references:Id;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Passengers []*BookingPassenger      `gorm:"foreignKey:BookingId;references:Id"`
+	FareClass  string                   `gorm:"column:fare_class"`
+	// Approval workflow of organization bookings
+	OrganizationId  string     `gorm:"column:organization_id;index"`
+	PolicyViolation string     `gorm:"column:policy_violation"`
+	ApproverId      string     `gorm:"column:approver_id"`
+	DecidedAt       *time.Time `gorm:"column:decided_at"`
+	DecisionComment string     `gorm:"column:decision_comment"`
+}
+
+// Upcoming scopes bookings to the active and pending ones whose flight did not depart yet
+func Upcoming(now time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Joins("JOIN flights ON flights.id::text = bookings.flight_id").
+			Where("bookings.status IN ? AND flights.depart_date > ?", []string{StatusActive, StatusPendingApproval}, now)
+	}
+}
+
+// CheckStatusChange keeps bookings waiting for approval or rejected out of the normal flow,
+// they can only be cancelled, approval decisions go through the approval workflow.
+// Cancelled and rejected bookings are final, a cancelled pending booking would otherwise
+// come back as Active without an approver.
+func (in *Booking) CheckStatusChange(next string) error {
+	if next == "" || next == in.Status {
+		return nil
+	}
+
+	if next == StatusPendingApproval || next == StatusRejected {
+		return fmt.Errorf("status %s is set by the approval workflow", next)
+	}
+
+	if in.Status == StatusCancel || in.Status == StatusRejected {
+		return fmt.Errorf("booking is %s, its status can not change anymore", in.Status)
+	}
+
+	if in.Status == StatusPendingApproval && next != StatusCancel {
+		return fmt.Errorf("booking is %s, it can only be cancelled", in.Status)
+	}
+
+	return nil
+}
+
+func (in *Booking) approvalResponse(res *protobuf.Booking) {
+	res.FareClass = in.FareClass
+	res.OrganizationId = in.OrganizationId
+	res.PolicyViolation = in.PolicyViolation
+	res.ApproverId = in.ApproverId
+	res.DecisionComment = in.DecisionComment
+	if in.DecidedAt != nil {
+		res.DecidedAt = timestamppb.New(*in.DecidedAt)
+	}
+}
+
+func (in *Booking) passengersResponse() []*protobuf.Passenger {
+	res := []*protobuf.Passenger{}
+	for _, passenger := range in.Passengers {
+		res = append(res, passenger.ToResponse())
+	}
+
+	return res
 }
 
 func (in *Booking) ToResponse() *protobuf.Booking {
@@ -42,11 +112,10 @@ func (in *Booking) ToResponse() *protobuf.Booking {
 			Name:           in.Customer.Name,
 			Email:          in.Customer.Email,
 			PhoneNumber:    in.Customer.PhoneNumber,
-			DateOfBith:     in.Customer.DateOfBith,
-			IdentityCard:   in.Customer.IdentityCard,
-			Address:        in.Customer.Address,
+			DateOfBith:     string(in.Customer.DateOfBith),
+			IdentityCard:   string(in.Customer.IdentityCard),
+			Address:        string(in.Customer.Address),
 			MembershipCard: in.Customer.MembershipCard,
-			Password:       in.Customer.Password,
 			Status:         in.Customer.Status,
 			CreatedAt:      timestamppb.New(in.Customer.CreatedAt),
 			UpdatedAt:      timestamppb.New(in.Customer.UpdatedAt),
@@ -62,7 +131,9 @@ func (in *Booking) ToResponse() *protobuf.Booking {
 			CreatedAt:     timestamppb.New(in.Flight.CreatedAt),
 			UpdatedAt:     timestamppb.New(in.Flight.UpdatedAt),
 		},
+		Passenger: in.passengersResponse(),
 	}
+	in.approvalResponse(res)
 
 	return res
 }
@@ -78,7 +149,9 @@ func (in *Booking) ToResponseForCreate() *protobuf.Booking {
 		Status:     in.Status,
 		CreatedAt:  timestamppb.New(in.CreatedAt),
 		UpdatedAt:  timestamppb.New(in.UpdatedAt),
+		Passenger:  in.passengersResponse(),
 	}
+	in.approvalResponse(res)
 
 	return res
 }
@@ -0,0 +1,31 @@
+package booking_model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckStatusChange(t *testing.T) {
+	booking := &Booking{Status: StatusActive}
+	assert.Nil(t, booking.CheckStatusChange(""))
+	assert.Nil(t, booking.CheckStatusChange(StatusCancel))
+	assert.NotNil(t, booking.CheckStatusChange(StatusPendingApproval))
+	assert.NotNil(t, booking.CheckStatusChange(StatusRejected))
+
+	rejected := &Booking{Status: StatusRejected}
+	assert.NotNil(t, rejected.CheckStatusChange(StatusActive))
+	assert.NotNil(t, rejected.CheckStatusChange(StatusCancel))
+}
+
+func TestCancelledPendingBookingStaysCancelled(t *testing.T) {
+	booking := &Booking{Status: StatusPendingApproval}
+	assert.NotNil(t, booking.CheckStatusChange(StatusActive))
+
+	assert.Nil(t, booking.CheckStatusChange(StatusCancel))
+	booking.Status = StatusCancel
+
+	// Active again would skip the approver
+	assert.NotNil(t, booking.CheckStatusChange(StatusActive))
+	assert.Nil(t, booking.CheckStatusChange(StatusCancel))
+}
@@ -0,0 +1,24 @@
+package booking_model
+
+import "fmt"
+
+const (
+	FareEconomy        = "economy"
+	FarePremiumEconomy = "premium_economy"
+	FareBusiness       = "business"
+	FareFirst          = "first"
+)
+
+// FareClasses are ordered from the cheapest to the most expensive
+var FareClasses = []string{FareEconomy, FarePremiumEconomy, FareBusiness, FareFirst}
+
+// FareClassRank is the position of the class in FareClasses
+func FareClassRank(fareClass string) (int, error) {
+	for i, class := range FareClasses {
+		if class == fareClass {
+			return i, nil
+		}
+	}
+
+	return 0, fmt.Errorf("fare class %q is invalid, use one of %v", fareClass, FareClasses)
+}
@@ -0,0 +1,76 @@
+package booking_model
+
+import (
+	"mock-golang/encryption"
+	customer_model "mock-golang/grpc/customer-grpc/model"
+	"strings"
+	"time"
+
+	"mock-golang/protobuf"
+
+	"github.com/google/uuid"
+)
+
+// BookingPassenger is a copy of the traveller data at booking time, editing or
+// deleting the saved traveller afterwards does not change the booking
+type BookingPassenger struct {
+	Id             uuid.UUID                  `gorm:"type:uuid;primaryKey"`
+	BookingId      uuid.UUID                  `gorm:"type:uuid;column:booking_id;index"`
+	TravellerId    string                     `gorm:"column:traveller_id"`
+	Name           string                     `gorm:"column:name"`
+	DateOfBirth    encryption.EncryptedString `gorm:"column:date_of_birth;type:text"`
+	DocumentType   string                     `gorm:"column:document_type"`
+	DocumentNumber encryption.EncryptedString `gorm:"column:document_number;type:text"`
+	DocumentExpiry time.Time                  `gorm:"column:document_expiry;type:date"`
+	Nationality    string                     `gorm:"column:nationality"`
+	CreatedAt      time.Time                  `gorm:"column:created_at"`
+}
+
+// CopyTraveller takes the identity and document of a saved or a just typed traveller
+func (in *BookingPassenger) CopyTraveller(traveller *customer_model.SavedTraveller) {
+	in.Name = traveller.Name
+	in.DateOfBirth = traveller.DateOfBirth
+	in.DocumentType = traveller.DocumentType
+	in.DocumentNumber = traveller.DocumentNumber
+	in.DocumentExpiry = traveller.DocumentExpiry
+	in.Nationality = traveller.Nationality
+}
+
+func (in *BookingPassenger) ToResponse() *protobuf.Passenger {
+	res := &protobuf.Passenger{
+		Id:             in.Id.String(),
+		TravellerId:    in.TravellerId,
+		Name:           in.Name,
+		DateOfBirth:    string(in.DateOfBirth),
+		DocumentType:   in.DocumentType,
+		DocumentNumber: string(in.DocumentNumber),
+		DocumentExpiry: in.DocumentExpiry.Format(customer_model.DateLayout),
+		Nationality:    in.Nationality,
+	}
+
+	return res
+}
+
+// MaskPersonalData blanks the birth dates and keeps the last characters of the document numbers
+// of the booker and the passengers. Names, document types and expiry stay to check a booking.
+func MaskPersonalData(res *protobuf.Booking) {
+	if res.Customer != nil {
+		res.Customer.DateOfBith = ""
+		res.Customer.IdentityCard = maskDocument(res.Customer.IdentityCard)
+		res.Customer.Address = ""
+	}
+
+	for _, passenger := range res.Passenger {
+		passenger.DateOfBirth = ""
+		passenger.DocumentNumber = maskDocument(passenger.DocumentNumber)
+	}
+}
+
+func maskDocument(number string) string {
+	const visible = 3
+	if len(number) <= visible {
+		return strings.Repeat("*", len(number))
+	}
+
+	return strings.Repeat("*", len(number)-visible) + number[len(number)-visible:]
+}
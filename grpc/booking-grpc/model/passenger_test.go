@@ -0,0 +1,28 @@
+package booking_model
+
+import (
+	"testing"
+
+	"mock-golang/protobuf"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaskPersonalData(t *testing.T) {
+	res := &protobuf.Booking{
+		Customer: &protobuf.CustomerDTO{DateOfBith: "1990-02-03", IdentityCard: "001099012345", Address: "1 Le Loi"},
+		Passenger: []*protobuf.Passenger{
+			{Name: "Nguyen Van A", DateOfBirth: "1990-02-03", DocumentType: "passport", DocumentNumber: "B1234567", DocumentExpiry: "2030-01-01"},
+		},
+	}
+
+	MaskPersonalData(res)
+
+	assert.Equal(t, "", res.Customer.DateOfBith)
+	assert.Equal(t, "*********345", res.Customer.IdentityCard)
+	assert.Equal(t, "", res.Customer.Address)
+	assert.Equal(t, "", res.Passenger[0].DateOfBirth)
+	assert.Equal(t, "*****567", res.Passenger[0].DocumentNumber)
+	assert.Equal(t, "Nguyen Van A", res.Passenger[0].Name)
+	assert.Equal(t, "2030-01-01", res.Passenger[0].DocumentExpiry)
+}
@@ -2,13 +2,19 @@ package booking_repo
 
 import (
 	"context"
+	"mock-golang/apperror"
 	"mock-golang/database"
 	booking_model "mock-golang/grpc/booking-grpc/model"
 	booking_request "mock-golang/grpc/booking-grpc/request"
+	customer_model "mock-golang/grpc/customer-grpc/model"
+	flight_model "mock-golang/grpc/flight-grpc/model"
+	"mock-golang/pagination"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 //Embeded struct
@@ -17,23 +23,45 @@ type BookingRepository interface {
 	FindById(ctx context.Context, id uuid.UUID) (*booking_model.Booking, error)
 	CreateBooking(ctx context.Context, model *booking_model.Booking) (*booking_model.Booking, error)
 	UpdateBooking(ctx context.Context, model *booking_model.Booking) (*booking_model.Booking, error)
-	SearchBooking(ctx context.Context, model *booking_request.SearchBookingRequest) ([]*booking_model.Booking, error)
+	SearchBooking(ctx context.Context, model *booking_request.SearchBookingRequest) ([]*booking_model.Booking, string, error)
+	CountBooking(ctx context.Context, model *booking_request.SearchBookingRequest) (int64, error)
+	CountUpcomingBookings(ctx context.Context, customerId string, flightId string, now time.Time) (int64, error)
+	// Ping checks the database is reachable, used by the health checks
+	Ping(ctx context.Context) error
+}
+
+// unscoped preloads soft deleted customers and flights, a booking keeps showing who flew where
+func unscoped(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
+
+// Orders are the fields SearchBooking pages by
+var Orders = pagination.Orders{
+	Default: "bookedDate desc",
+	Columns: map[string]string{
+		"bookedDate": "booked_date",
+		"createdAt":  "created_at",
+		"code":       "flight_number",
+	},
 }
 
 type dbmanager struct {
 	*gorm.DB
 }
 
+func (m *dbmanager) Ping(ctx context.Context) error {
+	return database.Ping(ctx, m.DB)
+}
+
 func NewDBManager() (BookingRepository, error) {
 	db, err := database.NewGormDB()
 	if err != nil {
 		return nil, err
 	}
 
-	db = db.Debug()
-
 	err = db.AutoMigrate(
 		&booking_model.Booking{},
+		&booking_model.BookingPassenger{},
 	)
 
 	if err != nil {
@@ -45,32 +73,57 @@ func NewDBManager() (BookingRepository, error) {
 
 func (m *dbmanager) FindById(ctx context.Context, id uuid.UUID) (*booking_model.Booking, error) {
 	res := booking_model.Booking{}
-	if err := m.Where(&booking_model.Booking{Id: id}).Preload("Customer").Preload("Flight").First(&res).Error; err != nil {
-		return nil, err
+	if err := m.WithContext(ctx).Where(&booking_model.Booking{Id: id}).Preload("Customer", unscoped).Preload("Flight", unscoped).Preload("Passengers").First(&res).Error; err != nil {
+		return nil, apperror.FromDB(err)
 	}
 
 	return &res, nil
 }
 
+// CreateBooking holds a share lock on the flight and the customer until the booking is written,
+// DeleteFlight and DeactivateCustomer lock them for update before counting the upcoming bookings
 func (m *dbmanager) CreateBooking(ctx context.Context, model *booking_model.Booking) (*booking_model.Booking, error) {
-	if err := m.Create(model).Error; err != nil {
-		return nil, err
+	err := m.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockLive(tx, &flight_model.Flight{}, model.FlightId, "flight"); err != nil {
+			return err
+		}
+		if err := lockLive(tx, &customer_model.Customer{}, model.CustomerId, "customer"); err != nil {
+			return err
+		}
+
+		return tx.Create(model).Error
+	})
+	if err != nil {
+		return nil, apperror.FromDB(err)
 	}
 
 	return model, nil
 }
 
+// lockLive share locks a row that is not soft deleted
+func lockLive(tx *gorm.DB, model interface{}, id string, name string) error {
+	ids := []string{}
+	err := tx.Model(model).Clauses(clause.Locking{Strength: "SHARE"}).Where("id = ?", id).Pluck("id", &ids).Error
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return apperror.FailedPrecondition("%s %s is deleted or deactivated", name, id)
+	}
+
+	return nil
+}
+
 func (m *dbmanager) UpdateBooking(ctx context.Context, model *booking_model.Booking) (*booking_model.Booking, error) {
-	if err := m.Where(&booking_model.Booking{Id: model.Id}).Updates(model).Error; err != nil {
-		return nil, err
+	if err := m.WithContext(ctx).Where(&booking_model.Booking{Id: model.Id}).Updates(model).Error; err != nil {
+		return nil, apperror.FromDB(err)
 	}
 
 	return model, nil
 }
 
-func (m *dbmanager) SearchBooking(ctx context.Context, req *booking_request.SearchBookingRequest) ([]*booking_model.Booking, error) {
-	bookings := []*booking_model.Booking{}
-
+// searchScope builds the filters shared by SearchBooking and CountBooking
+func (m *dbmanager) searchScope(ctx context.Context, req *booking_request.SearchBookingRequest) *gorm.DB {
 	sbWhere := " 1=1 "
 	params := []interface{}{}
 	if len(strings.TrimSpace(req.Id)) > 0 {
@@ -97,14 +150,77 @@ func (m *dbmanager) SearchBooking(ctx context.Context, req *booking_request.Sear
 		sbWhere += " AND booked_date <= ? "
 		params = append(params, req.ToDate)
 	}
+	if len(strings.TrimSpace(req.OrganizationId)) > 0 {
+		sbWhere += " AND organization_id = ? "
+		params = append(params, req.OrganizationId)
+	}
+
 	if len(strings.TrimSpace(req.Status)) > 0 {
 		sbWhere += " AND Status = ? "
 		params = append(params, req.Status)
 	}
 
-	if err := m.Where(sbWhere, params...).Preload("Customer").Preload("Flight").Find(&bookings).Error; err != nil {
-		return nil, err
+	return m.WithContext(ctx).Model(&booking_model.Booking{}).Where(sbWhere, params...)
+}
+
+// SearchBooking returns a page of bookings and the token of the next one,
+// customers, flights and passengers are only preloaded for that page
+func (m *dbmanager) SearchBooking(ctx context.Context, req *booking_request.SearchBookingRequest) ([]*booking_model.Booking, string, error) {
+	bookings := []*booking_model.Booking{}
+
+	db := req.Page.Apply(m.searchScope(ctx, req), "id")
+	if err := db.Preload("Customer", unscoped).Preload("Flight", unscoped).Preload("Passengers").Find(&bookings).Error; err != nil {
+		return nil, "", apperror.FromDB(err)
+	}
+
+	nextPageToken := ""
+	if req.Page.HasNext(len(bookings)) {
+		bookings = bookings[:req.Page.Size]
+		last := bookings[len(bookings)-1]
+		nextPageToken = req.Page.NextToken(sortKey(req.Page.Order.Field, last), last.Id.String())
+	}
+
+	return bookings, nextPageToken, nil
+}
+
+// CountBooking counts the matches of SearchBooking across all pages
+func (m *dbmanager) CountBooking(ctx context.Context, req *booking_request.SearchBookingRequest) (int64, error) {
+	var count int64
+
+	if err := m.searchScope(ctx, req).Count(&count).Error; err != nil {
+		return 0, apperror.FromDB(err)
+	}
+
+	return count, nil
+}
+
+// sortKey is the value of the order by field the next page starts after
+func sortKey(field string, booking *booking_model.Booking) interface{} {
+	switch field {
+	case "createdAt":
+		return booking.CreatedAt
+	case "code":
+		return booking.Code
+	default:
+		return booking.BookedDate
+	}
+}
+
+// CountUpcomingBookings counts the active and pending bookings of a customer or a flight that did not depart yet
+func (m *dbmanager) CountUpcomingBookings(ctx context.Context, customerId string, flightId string, now time.Time) (int64, error) {
+	var count int64
+
+	db := m.WithContext(ctx).Model(&booking_model.Booking{}).Scopes(booking_model.Upcoming(now))
+	if customerId != "" {
+		db = db.Where("bookings.customer_id = ?", customerId)
+	}
+	if flightId != "" {
+		db = db.Where("bookings.flight_id = ?", flightId)
+	}
+
+	if err := db.Count(&count).Error; err != nil {
+		return 0, apperror.FromDB(err)
 	}
 
-	return bookings, nil
+	return count, nil
 }
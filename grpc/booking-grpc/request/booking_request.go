@@ -3,6 +3,8 @@ package booking_request
 import (
 	"time"
 
+	"mock-golang/pagination"
+
 	"github.com/google/uuid"
 )
 
@@ -18,4 +20,8 @@ type SearchBookingRequest struct {
 	Status     string
 	FromDate   time.Time
 	ToDate     time.Time
+	// Set by the approval workflow only
+	OrganizationId string
+	// Page is nil to return every match
+	Page *pagination.Page
 }
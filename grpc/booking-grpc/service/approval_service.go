@@ -0,0 +1,197 @@
+package booking_handler
+
+import (
+	"context"
+	"errors"
+	"mock-golang/apperror"
+	booking_model "mock-golang/grpc/booking-grpc/model"
+	booking_request "mock-golang/grpc/booking-grpc/request"
+	organization_model "mock-golang/grpc/organization-grpc/model"
+	"mock-golang/protobuf"
+	"mock-golang/rbac"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// ListPendingApprovals lists the bookings of an organization waiting for a decision
+func (h *BookingHandler) ListPendingApprovals(ctx context.Context, in *protobuf.PendingApprovalsRequest) (*protobuf.SearchBookingResponse, error) {
+	if _, err := apperror.ParseID("organizationId", in.OrganizationId); err != nil {
+		return nil, err
+	}
+
+	if err := h.requireApprover(ctx, in.OrganizationId); err != nil {
+		return nil, err
+	}
+
+	bookings, _, err := h.bookingRepository.SearchBooking(ctx, &booking_request.SearchBookingRequest{
+		OrganizationId: in.OrganizationId,
+		Status:         booking_model.StatusPendingApproval,
+	})
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	pRes := &protobuf.SearchBookingResponse{
+		Booking: []*protobuf.Booking{},
+	}
+
+	for _, bk := range bookings {
+		pRes.Booking = append(pRes.Booking, respond(ctx, bk.ToResponse()))
+	}
+
+	return pRes, nil
+}
+
+func (h *BookingHandler) ApproveBooking(ctx context.Context, in *protobuf.BookingDecisionRequest) (*protobuf.Booking, error) {
+	return h.decide(ctx, in, booking_model.StatusActive)
+}
+
+// RejectBooking needs a comment so the traveller knows why
+func (h *BookingHandler) RejectBooking(ctx context.Context, in *protobuf.BookingDecisionRequest) (*protobuf.Booking, error) {
+	if strings.TrimSpace(in.Comment) == "" {
+		return nil, status.Error(codes.InvalidArgument, "a comment is required to reject a booking")
+	}
+
+	return h.decide(ctx, in, booking_model.StatusRejected)
+}
+
+func (h *BookingHandler) decide(ctx context.Context, in *protobuf.BookingDecisionRequest, next string) (*protobuf.Booking, error) {
+	bookingId, err := apperror.ParseID("bookingId", in.BookingId)
+	if err != nil {
+		return nil, err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	booking, err := h.bookingRepository.FindById(ctx, bookingId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "booking %s not found", bookingId)
+		}
+		return nil, apperror.ToStatus(err)
+	}
+
+	if err := h.requireApprover(ctx, booking.OrganizationId); err != nil {
+		return nil, err
+	}
+
+	approverId := rbac.FromContext(ctx).CustomerId
+	if approverId == booking.CustomerId {
+		return nil, status.Error(codes.PermissionDenied, "approvers cannot decide on their own bookings")
+	}
+
+	if booking.Status != booking_model.StatusPendingApproval {
+		return nil, status.Errorf(codes.FailedPrecondition, "booking is %s, only pending bookings can be decided", booking.Status)
+	}
+
+	now := time.Now()
+	booking.Status = next
+	booking.ApproverId = approverId
+	booking.DecidedAt = &now
+	booking.DecisionComment = strings.TrimSpace(in.Comment)
+	booking.UpdatedAt = now
+
+	out, err := h.bookingRepository.UpdateBooking(ctx, booking)
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	if out.Status == booking_model.StatusActive {
+		recordSale(out)
+	}
+
+	return respond(ctx, out.ToResponse()), nil
+}
+
+// requireApprover accepts the approvers of the organization and the organization managers
+func (h *BookingHandler) requireApprover(ctx context.Context, organizationId string) error {
+	principal := rbac.FromContext(ctx)
+	if principal.Can(rbac.PermOrganizationManage) {
+		return nil
+	}
+
+	if err := rbac.Require(ctx, rbac.PermBookingWriteOwn); err != nil {
+		return err
+	}
+
+	member, err := h.findMember(ctx, principal.CustomerId)
+	if err != nil {
+		return err
+	}
+	if member == nil || member.OrganizationId.String() != organizationId || !member.CanApprove() {
+		return status.Errorf(codes.PermissionDenied, "not an approver of organization %s", organizationId)
+	}
+
+	return nil
+}
+
+// requireBookFor lets bookers and approvers book for the other members of their organization
+func (h *BookingHandler) requireBookFor(ctx context.Context, customerId string, member *organization_model.Member) error {
+	err := rbac.RequireOwnOrAny(ctx, customerId, rbac.PermBookingWriteOwn, rbac.PermBookingWriteAny)
+	if err == nil || member == nil || status.Code(err) != codes.PermissionDenied {
+		return err
+	}
+
+	principal := rbac.FromContext(ctx)
+	if !principal.Can(rbac.PermBookingWriteOwn) {
+		return err
+	}
+
+	booker, findErr := h.findMember(ctx, principal.CustomerId)
+	if findErr != nil {
+		return findErr
+	}
+	if booker == nil || booker.OrganizationId != member.OrganizationId || !booker.CanBookForOthers() {
+		return err
+	}
+
+	return nil
+}
+
+// applyTravelPolicy holds the booking for approval when it breaks the organization policy
+func (h *BookingHandler) applyTravelPolicy(ctx context.Context, booking *booking_model.Booking, member *organization_model.Member) error {
+	org, err := h.organizationRepository.FindById(ctx, member.OrganizationId)
+	if err != nil {
+		return apperror.ToStatus(err)
+	}
+
+	flight, err := h.findFlight(ctx, booking.FlightId)
+	if err != nil {
+		return err
+	}
+
+	violations, err := org.PolicyViolations(booking.FareClass, flight.DepartDate, time.Now())
+	if err != nil {
+		return apperror.ToStatus(err)
+	}
+
+	booking.OrganizationId = org.Id.String()
+	if len(violations) > 0 {
+		booking.Status = booking_model.StatusPendingApproval
+		booking.PolicyViolation = strings.Join(violations, "; ")
+	}
+
+	return nil
+}
+
+// findMember returns nil when the customer does not belong to an organization
+func (h *BookingHandler) findMember(ctx context.Context, customerId string) (*organization_model.Member, error) {
+	if customerId == "" {
+		return nil, nil
+	}
+
+	member, err := h.organizationRepository.FindMember(ctx, customerId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, apperror.ToStatus(err)
+	}
+
+	return member, nil
+}
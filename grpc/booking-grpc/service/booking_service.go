@@ -2,43 +2,93 @@ package booking_handler
 
 import (
 	"context"
-	"database/sql"
+	"errors"
+	"mock-golang/apperror"
 	booking_model "mock-golang/grpc/booking-grpc/model"
 	booking_repo "mock-golang/grpc/booking-grpc/repository"
 	booking_request "mock-golang/grpc/booking-grpc/request"
+	customer_model "mock-golang/grpc/customer-grpc/model"
+	customer_repo "mock-golang/grpc/customer-grpc/repository"
+	flight_model "mock-golang/grpc/flight-grpc/model"
+	flight_repo "mock-golang/grpc/flight-grpc/repository"
+	loyalty_handler "mock-golang/grpc/loyalty-grpc/service"
+	organization_repo "mock-golang/grpc/organization-grpc/repository"
+	"mock-golang/metrics"
+	"mock-golang/pagination"
 	"mock-golang/protobuf"
+	"mock-golang/rbac"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
 )
 
 type BookingHandler struct {
 	protobuf.UnimplementedRPCBookingServer
-	bookingRepository booking_repo.BookingRepository
-	mu                *sync.Mutex
+	bookingRepository      booking_repo.BookingRepository
+	customerRepository     customer_repo.CustomerRepository
+	flightRepository       flight_repo.FlightRepository
+	organizationRepository organization_repo.OrganizationRepository
+	loyalty                *loyalty_handler.LoyaltyHandler
+	mu                     *sync.Mutex
 }
 
 func (h *BookingHandler) FindById(ctx context.Context, in *protobuf.BookingParamId) (*protobuf.Booking, error) {
-	out, err := h.bookingRepository.FindById(ctx, uuid.MustParse(in.Id))
+	bookingId, err := apperror.ParseID("id", in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := h.bookingRepository.FindById(ctx, bookingId)
 
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, apperror.ToStatus(err)
+	}
+
+	if err := rbac.RequireOwnOrAny(ctx, out.CustomerId, rbac.PermBookingReadOwn, rbac.PermBookingReadAny); err != nil {
+		return nil, err
 	}
 
-	return out.ToResponse(), nil
+	return respond(ctx, out.ToResponse()), nil
 }
 
-func NewBookingHandler(bookingRepository booking_repo.BookingRepository) (*BookingHandler, error) {
+func NewBookingHandler(
+	bookingRepository booking_repo.BookingRepository,
+	customerRepository customer_repo.CustomerRepository,
+	flightRepository flight_repo.FlightRepository,
+	organizationRepository organization_repo.OrganizationRepository,
+	loyalty *loyalty_handler.LoyaltyHandler) (*BookingHandler, error) {
 	return &BookingHandler{
-		bookingRepository: bookingRepository,
-		mu:                &sync.Mutex{},
+		bookingRepository:      bookingRepository,
+		customerRepository:     customerRepository,
+		flightRepository:       flightRepository,
+		organizationRepository: organizationRepository,
+		loyalty:                loyalty,
+		mu:                     &sync.Mutex{},
 	}, nil
 }
 
 func (h *BookingHandler) CreateBooking(ctx context.Context, in *protobuf.Booking) (*protobuf.Booking, error) {
+	member, err := h.findMember(ctx, in.CustomerId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.requireBookFor(ctx, in.CustomerId, member); err != nil {
+		return nil, err
+	}
+
+	fareClass := in.FareClass
+	if fareClass == "" {
+		fareClass = booking_model.FareEconomy
+	}
+	if _, err := booking_model.FareClassRank(fareClass); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
 	req := &booking_model.Booking{
 		Id:         uuid.New(),
 		CustomerId: in.CustomerId,
@@ -47,24 +97,55 @@ func (h *BookingHandler) CreateBooking(ctx context.Context, in *protobuf.Booking
 		BookedSlot: in.BookedSlot,
 		BookedDate: time.Now(),
 		Status:     in.Status,
+		FareClass:  fareClass,
 		CreatedAt:  time.Now(),
 		UpdatedAt:  time.Now(),
 	}
+
+	if member != nil {
+		if err := h.applyTravelPolicy(ctx, req, member); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(in.Passenger) > 0 {
+		if in.BookedSlot > 0 && int(in.BookedSlot) != len(in.Passenger) {
+			return nil, status.Errorf(codes.InvalidArgument, "booked slot %d does not match the %d passengers", in.BookedSlot, len(in.Passenger))
+		}
+
+		passengers, err := h.resolvePassengers(ctx, req, in.Passenger)
+		if err != nil {
+			return nil, err
+		}
+		req.Passengers = passengers
+		req.BookedSlot = int32(len(passengers))
+	}
 	out, err := h.bookingRepository.CreateBooking(ctx, req)
 
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, apperror.ToStatus(err)
 	}
 
-	return out.ToResponseForCreate(), nil
+	// Bookings waiting for an approver are counted once approved
+	if out.Status == booking_model.StatusActive {
+		recordSale(out)
+	}
+
+	return respond(ctx, out.ToResponseForCreate()), nil
 }
 
 func (h *BookingHandler) UpdateBooking(ctx context.Context, in *protobuf.Booking) (*protobuf.Booking, error) {
-	req, err := h.bookingRepository.FindById(ctx, uuid.MustParse(in.Id))
+	bookingId, err := apperror.ParseID("id", in.Id)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, status.Error(codes.NotFound, err.Error())
-		}
+		return nil, err
+	}
+
+	req, err := h.bookingRepository.FindById(ctx, bookingId)
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	if err := rbac.RequireOwnOrAny(ctx, req.CustomerId, rbac.PermBookingWriteOwn, rbac.PermBookingWriteAny); err != nil {
 		return nil, err
 	}
 
@@ -76,6 +157,11 @@ func (h *BookingHandler) UpdateBooking(ctx context.Context, in *protobuf.Booking
 		req.BookedSlot = in.BookedSlot
 	}
 
+	if err := req.CheckStatusChange(in.Status); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	cancelled := req.Status != booking_model.StatusCancel && in.Status == booking_model.StatusCancel
 	if in.Status != "" {
 		req.Status = in.Status
 	}
@@ -85,42 +171,166 @@ func (h *BookingHandler) UpdateBooking(ctx context.Context, in *protobuf.Booking
 	out, err := h.bookingRepository.UpdateBooking(ctx, req)
 
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, apperror.ToStatus(err)
+	}
+
+	if cancelled {
+		metrics.BookingsCancelled.Inc()
+	}
+
+	// Reversal is posted once per booking, repeating the cancel is harmless
+	if out.Status == booking_model.StatusCancel {
+		if err := h.loyalty.ReverseBooking(ctx, out); err != nil {
+			return nil, apperror.ToStatus(err)
+		}
+	}
+
+	return respond(ctx, out.ToResponseForCreate()), nil
+}
+
+// resolvePassengers copies saved travellers of the booking customer or validates inline
+// passengers, and checks that every document is still valid on the departure date
+func (h *BookingHandler) resolvePassengers(ctx context.Context, booking *booking_model.Booking, in []*protobuf.Passenger) ([]*booking_model.BookingPassenger, error) {
+	flight, err := h.findFlight(ctx, booking.FlightId)
+	if err != nil {
+		return nil, err
+	}
+
+	passengers := []*booking_model.BookingPassenger{}
+	for i, p := range in {
+		passenger := &booking_model.BookingPassenger{
+			Id:        uuid.New(),
+			BookingId: booking.Id,
+			CreatedAt: booking.CreatedAt,
+		}
+
+		if p.TravellerId != "" {
+			traveller, err := h.findSavedTraveller(ctx, booking.CustomerId, p.TravellerId)
+			if err != nil {
+				return nil, err
+			}
+
+			passenger.TravellerId = traveller.Id.String()
+			passenger.CopyTraveller(traveller)
+		} else {
+			typed := &customer_model.SavedTraveller{}
+			if err := typed.Apply(p); err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "passenger %d: %s", i+1, err)
+			}
+
+			passenger.CopyTraveller(typed)
+		}
+
+		if err := customer_model.CheckDocumentExpiry(passenger.DocumentExpiry, flight.DepartDate); err != nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "passenger %s: %s", passenger.Name, err)
+		}
+
+		passengers = append(passengers, passenger)
+	}
+
+	return passengers, nil
+}
+
+// respond masks the personal data of the booking unless the caller owns it or may read anybody's
+func respond(ctx context.Context, res *protobuf.Booking) *protobuf.Booking {
+	if !rbac.CanReadPersonalData(ctx, res.CustomerId) {
+		booking_model.MaskPersonalData(res)
+	}
+
+	return res
+}
+
+func (h *BookingHandler) findFlight(ctx context.Context, id string) (*flight_model.Flight, error) {
+	flightId, err := apperror.ParseID("flightId", id)
+	if err != nil {
+		return nil, err
+	}
+
+	flight, err := h.flightRepository.FindById(ctx, flightId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "flight %s not found", flightId)
+		}
+		return nil, apperror.ToStatus(err)
+	}
+
+	return flight, nil
+}
+
+// findSavedTraveller only accepts travellers saved by the booking customer
+func (h *BookingHandler) findSavedTraveller(ctx context.Context, customerId string, id string) (*customer_model.SavedTraveller, error) {
+	travellerId, err := apperror.ParseID("travellerId", id)
+	if err != nil {
+		return nil, err
 	}
 
-	return out.ToResponseForCreate(), nil
+	traveller, err := h.customerRepository.FindTravellerById(ctx, travellerId)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.ToStatus(err)
+	}
+	if err != nil || traveller.CustomerId != customerId {
+		return nil, status.Errorf(codes.NotFound, "traveller %s not found", travellerId)
+	}
+
+	return traveller, nil
 }
 
 func (h *BookingHandler) SearchBooking(ctx context.Context, in *protobuf.SearchBookingRequest) (*protobuf.SearchBookingResponse, error) {
+	page, err := pagination.NewPage(in.PageSize, in.PageToken, in.OrderBy, booking_repo.Orders)
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
 	params := &booking_request.SearchBookingRequest{
 		Id:         in.Id,
 		CustomerId: in.CustomerId,
 		FlightId:   in.FlightId,
 		Code:       in.Code,
 		Status:     in.Status,
-		// FromDate:   in.FromDate.AsTime(),
-		// ToDate:     in.ToDate.AsTime(),
+		Page:       page,
 	}
 
-	bookings, err := h.bookingRepository.SearchBooking(ctx, params)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, status.Error(codes.NotFound, err.Error())
-		}
-		return nil, err
+	// A nil timestamp would otherwise become the Unix epoch
+	if in.FromDate != nil {
+		params.FromDate = in.FromDate.AsTime()
+	}
+	if in.ToDate != nil {
+		params.ToDate = in.ToDate.AsTime()
 	}
 
-	pRes := &protobuf.SearchBookingResponse{
-		Booking: []*protobuf.Booking{},
+	// Without booking:read:any the search is limited to the caller's own bookings
+	if principal := rbac.FromContext(ctx); !principal.Can(rbac.PermBookingReadAny) {
+		if err := rbac.Require(ctx, rbac.PermBookingReadOwn); err != nil {
+			return nil, err
+		}
+		params.CustomerId = principal.CustomerId
 	}
 
-	for _, bk := range bookings {
-		pRes.Booking = append(pRes.Booking, bk.ToResponse())
+	bookings, nextPageToken, err := h.bookingRepository.SearchBooking(ctx, params)
+	if err != nil {
+		return nil, apperror.ToStatus(err)
 	}
 
+	total, err := h.bookingRepository.CountBooking(ctx, params)
 	if err != nil {
-		return nil, err
+		return nil, apperror.ToStatus(err)
+	}
+
+	pRes := &protobuf.SearchBookingResponse{
+		Booking:       []*protobuf.Booking{},
+		NextPageToken: nextPageToken,
+		TotalSize:     total,
+	}
+
+	for _, bk := range bookings {
+		pRes.Booking = append(pRes.Booking, respond(ctx, bk.ToResponse()))
 	}
 
 	return pRes, nil
 }
+
+// recordSale counts a confirmed booking and its seats
+func recordSale(booking *booking_model.Booking) {
+	metrics.BookingsCreated.WithLabelValues(booking.FareClass, booking.Status).Inc()
+	metrics.SeatsSold.WithLabelValues(booking.FlightId).Add(float64(booking.BookedSlot))
+}
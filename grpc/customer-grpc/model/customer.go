@@ -3,26 +3,56 @@ package customer_model
 import (
 	"time"
 
+	"mock-golang/encryption"
 	"mock-golang/protobuf"
 
 	"github.com/google/uuid"
 	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
 )
 
 type Customer struct {
-	Id             uuid.UUID `gorm:"type:uuid;primaryKey"`
-	Role           int32     `gorm:"column:role"`
-	Name           string    `gorm:"column:customer_name"`
-	Email          string    `gorm:"column:email"`
-	PhoneNumber    string    `gorm:"column:phone_number"`
-	DateOfBith     string    `gorm:"column:date_of_bith"`
-	IdentityCard   string    `gorm:"column:identity_card"`
-	Address        string    `gorm:"column:address"`
-	MembershipCard string    `gorm:"column:membership_card"`
-	Password       string    `gorm:"column:password"`
-	Status         int32     `gorm:"column:status"`
-	CreatedAt      time.Time `gorm:"column:created_at"`
-	UpdatedAt      time.Time `gorm:"column:updated_at"`
+	Id          uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Role        int32     `gorm:"column:role"`
+	Name        string    `gorm:"column:customer_name"`
+	Email       string    `gorm:"column:email"`
+	PhoneNumber string    `gorm:"column:phone_number"`
+	// Personal data is encrypted at rest, identity card is matched through its blind index
+	DateOfBith        encryption.EncryptedString `gorm:"column:date_of_bith;type:text"`
+	IdentityCard      encryption.EncryptedString `gorm:"column:identity_card;type:text"`
+	IdentityCardIndex string                     `gorm:"column:identity_card_index;index"`
+	Address           encryption.EncryptedString `gorm:"column:address;type:text"`
+	MembershipCard    string                     `gorm:"column:membership_card"`
+	Password          string                     `gorm:"column:password"`
+	Status            int32                      `gorm:"column:status"`
+	CreatedAt         time.Time                  `gorm:"column:created_at"`
+	UpdatedAt         time.Time                  `gorm:"column:updated_at"`
+	// Set once the customer proved ownership, cleared when the value changes
+	EmailVerifiedAt *time.Time `gorm:"column:email_verified_at"`
+	PhoneVerifiedAt *time.Time `gorm:"column:phone_verified_at"`
+	// Duplicates merged by an admin point to the customer that now owns their bookings
+	MergedIntoId string `gorm:"column:merged_into_id;index"`
+	// Copy of the loyalty account tier, only written by the loyalty repository
+	LoyaltyTier string `gorm:"column:loyalty_tier;<-:false"`
+	// Set when the personal data was erased on request, the row stays for the bookings
+	ErasedAt *time.Time `gorm:"column:erased_at"`
+	// Deactivated customers are soft deleted, gorm leaves them out of every query unless Unscoped
+	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;index"`
+}
+
+// BeforeSave keeps the blind index in step with the identity card
+func (in *Customer) BeforeSave(tx *gorm.DB) error {
+	if in.IdentityCard == "" {
+		return nil
+	}
+
+	index, err := encryption.BlindIndex(string(in.IdentityCard))
+	if err != nil {
+		return err
+	}
+	in.IdentityCardIndex = index
+
+	return nil
 }
 
 func (in *Customer) ToResponse() *protobuf.Customer {
@@ -32,14 +62,26 @@ func (in *Customer) ToResponse() *protobuf.Customer {
 		Name:           in.Name,
 		Email:          in.Email,
 		PhoneNumber:    in.PhoneNumber,
-		DateOfBith:     in.DateOfBith,
-		IdentityCard:   in.IdentityCard,
-		Address:        in.Address,
+		DateOfBith:     string(in.DateOfBith),
+		IdentityCard:   string(in.IdentityCard),
+		Address:        string(in.Address),
 		MembershipCard: in.MembershipCard,
-		Password:       in.Password,
 		Status:         in.Status,
 		CreatedAt:      timestamppb.New(in.CreatedAt),
 		UpdatedAt:      timestamppb.New(in.UpdatedAt),
+		LoyaltyTier:    in.LoyaltyTier,
+	}
+
+	if in.EmailVerifiedAt != nil {
+		customerRes.EmailVerifiedAt = timestamppb.New(*in.EmailVerifiedAt)
+	}
+
+	if in.PhoneVerifiedAt != nil {
+		customerRes.PhoneVerifiedAt = timestamppb.New(*in.PhoneVerifiedAt)
+	}
+
+	if in.DeletedAt.Valid {
+		customerRes.DeletedAt = timestamppb.New(in.DeletedAt.Time)
 	}
 
 	return customerRes
@@ -0,0 +1,156 @@
+package customer_model
+
+import (
+	"fmt"
+	"time"
+
+	"mock-golang/notification"
+	"mock-golang/protobuf"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// SupportedLanguages are the languages notifications are written in, the first one is the default
+var SupportedLanguages = []string{"vi", "en"}
+
+// NotificationPreference holds the channels and categories a customer wants, one row per customer
+type NotificationPreference struct {
+	CustomerId   string `gorm:"column:customer_id;primaryKey"`
+	EmailEnabled bool   `gorm:"column:email_enabled"`
+	SmsEnabled   bool   `gorm:"column:sms_enabled"`
+	// PushEnabled is always false, there is no push delivery yet
+	PushEnabled    bool      `gorm:"column:push_enabled"`
+	BookingUpdates bool      `gorm:"column:booking_updates"`
+	FlightChanges  bool      `gorm:"column:flight_changes"`
+	Promotions     bool      `gorm:"column:promotions"`
+	Language       string    `gorm:"column:language"`
+	CreatedAt      time.Time `gorm:"column:created_at"`
+	UpdatedAt      time.Time `gorm:"column:updated_at"`
+}
+
+// ConsentRecord is an append only proof of every opt in and opt out
+type ConsentRecord struct {
+	Id         uuid.UUID `gorm:"type:uuid;primaryKey"`
+	CustomerId string    `gorm:"column:customer_id;index"`
+	Purpose    string    `gorm:"column:purpose"`
+	Granted    bool      `gorm:"column:granted"`
+	ActorId    string    `gorm:"column:actor_id"`
+	Ip         string    `gorm:"column:ip"`
+	CreatedAt  time.Time `gorm:"column:created_at"`
+}
+
+// ConsentChange is a purpose whose consent differs between two preferences
+type ConsentChange struct {
+	Purpose string
+	Granted bool
+}
+
+// DefaultNotificationPreference applies until the customer saves its own: operational
+// messages by email and SMS, no promotions without consent
+func DefaultNotificationPreference(customerId string) *NotificationPreference {
+	return &NotificationPreference{
+		CustomerId:     customerId,
+		EmailEnabled:   true,
+		SmsEnabled:     true,
+		BookingUpdates: true,
+		FlightChanges:  true,
+		Language:       SupportedLanguages[0],
+	}
+}
+
+// ValidateLanguage accepts the SupportedLanguages only
+func ValidateLanguage(language string) error {
+	for _, supported := range SupportedLanguages {
+		if language == supported {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("language %q is not supported, use one of %v", language, SupportedLanguages)
+}
+
+// Validate accepts the SupportedLanguages only and refuses push, events carry no device to push to
+func (in *NotificationPreference) Validate() error {
+	if in.PushEnabled {
+		return fmt.Errorf("push notifications are not available, pushEnabled must be false")
+	}
+
+	return ValidateLanguage(in.Language)
+}
+
+// consents lists every purpose in a fixed order
+func (in *NotificationPreference) consents() []ConsentChange {
+	return []ConsentChange{
+		{Purpose: notification.ChannelEmail, Granted: in.EmailEnabled},
+		{Purpose: notification.ChannelSms, Granted: in.SmsEnabled},
+		{Purpose: notification.CategoryBookingUpdates, Granted: in.BookingUpdates},
+		{Purpose: notification.CategoryFlightChanges, Granted: in.FlightChanges},
+		{Purpose: notification.CategoryPromotions, Granted: in.Promotions},
+	}
+}
+
+// ConsentChanges returns the purposes next grants or withdraws compared to in
+func (in *NotificationPreference) ConsentChanges(next *NotificationPreference) []ConsentChange {
+	changes := []ConsentChange{}
+
+	current := in.consents()
+	for i, consent := range next.consents() {
+		if consent.Granted != current[i].Granted {
+			changes = append(changes, consent)
+		}
+	}
+
+	return changes
+}
+
+func (in *NotificationPreference) ToNotification() *notification.Preferences {
+	res := &notification.Preferences{
+		Channels:   map[string]bool{},
+		Categories: map[string]bool{},
+		Language:   in.Language,
+	}
+
+	for _, consent := range in.consents() {
+		switch consent.Purpose {
+		case notification.ChannelEmail, notification.ChannelSms:
+			res.Channels[consent.Purpose] = consent.Granted
+		default:
+			res.Categories[consent.Purpose] = consent.Granted
+		}
+	}
+
+	return res
+}
+
+func (in *NotificationPreference) ToResponse() *protobuf.NotificationPreferences {
+	res := &protobuf.NotificationPreferences{
+		CustomerId:     in.CustomerId,
+		EmailEnabled:   in.EmailEnabled,
+		SmsEnabled:     in.SmsEnabled,
+		PushEnabled:    in.PushEnabled,
+		BookingUpdates: in.BookingUpdates,
+		FlightChanges:  in.FlightChanges,
+		Promotions:     in.Promotions,
+		Language:       in.Language,
+	}
+
+	if !in.UpdatedAt.IsZero() {
+		res.UpdatedAt = timestamppb.New(in.UpdatedAt)
+	}
+
+	return res
+}
+
+func (in *ConsentRecord) ToResponse() *protobuf.ConsentRecord {
+	res := &protobuf.ConsentRecord{
+		Id:        in.Id.String(),
+		Purpose:   in.Purpose,
+		Granted:   in.Granted,
+		ActorId:   in.ActorId,
+		Ip:        in.Ip,
+		CreatedAt: timestamppb.New(in.CreatedAt),
+	}
+
+	return res
+}
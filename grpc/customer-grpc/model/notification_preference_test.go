@@ -0,0 +1,44 @@
+package customer_model
+
+import (
+	"testing"
+
+	"mock-golang/notification"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsentChanges(t *testing.T) {
+	current := DefaultNotificationPreference("c1")
+
+	next := *current
+	next.SmsEnabled = false
+	next.Promotions = true
+	next.Language = "en"
+
+	changes := current.ConsentChanges(&next)
+	assert.Equal(t, []ConsentChange{
+		{Purpose: notification.ChannelSms, Granted: false},
+		{Purpose: notification.CategoryPromotions, Granted: true},
+	}, changes)
+
+	assert.Empty(t, current.ConsentChanges(current))
+}
+
+func TestDefaultPreferenceHasNoPromotions(t *testing.T) {
+	preferences := DefaultNotificationPreference("c1").ToNotification()
+
+	assert.True(t, preferences.Allows(notification.ChannelEmail, notification.CategoryBookingUpdates))
+	assert.False(t, preferences.Allows(notification.ChannelEmail, notification.CategoryPromotions))
+	assert.False(t, preferences.Allows("push", notification.CategoryFlightChanges))
+	assert.Nil(t, ValidateLanguage("vi"))
+	assert.NotNil(t, ValidateLanguage("fr"))
+}
+
+func TestValidateRefusesPush(t *testing.T) {
+	preference := DefaultNotificationPreference("c1")
+	assert.Nil(t, preference.Validate())
+
+	preference.PushEnabled = true
+	assert.NotNil(t, preference.Validate())
+}
@@ -0,0 +1,132 @@
+package customer_model
+
+import (
+	"fmt"
+	"mock-golang/apperror"
+	"mock-golang/encryption"
+	"strings"
+	"time"
+
+	"mock-golang/protobuf"
+	"mock-golang/rules"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+const (
+	DocumentPassport = "passport"
+	DocumentIdCard   = "id_card"
+
+	// DateLayout is the ISO date used by birth dates and document expiry
+	DateLayout = "2006-01-02"
+)
+
+// SavedTraveller is a passenger a customer books for again and again (family, colleagues)
+type SavedTraveller struct {
+	Id             uuid.UUID                  `gorm:"type:uuid;primaryKey"`
+	CustomerId     string                     `gorm:"column:customer_id;index"`
+	Name           string                     `gorm:"column:name"`
+	DateOfBirth    encryption.EncryptedString `gorm:"column:date_of_birth;type:text"`
+	DocumentType   string                     `gorm:"column:document_type"`
+	DocumentNumber encryption.EncryptedString `gorm:"column:document_number;type:text"`
+	DocumentExpiry time.Time                  `gorm:"column:document_expiry;type:date"`
+	Nationality    string                     `gorm:"column:nationality"`
+	CreatedAt      time.Time                  `gorm:"column:created_at"`
+	UpdatedAt      time.Time                  `gorm:"column:updated_at"`
+}
+
+// TravellerInput is what a traveller is typed with, a saved traveller (protobuf.Traveller)
+// as well as a booking passenger without one (protobuf.Passenger)
+type TravellerInput interface {
+	GetName() string
+	GetDateOfBirth() string
+	GetDocumentType() string
+	GetDocumentNumber() string
+	GetDocumentExpiry() string
+	GetNationality() string
+}
+
+// Apply validates the input and copies it, normalized, onto the traveller
+func (in *SavedTraveller) Apply(input TravellerInput) error {
+	if strings.TrimSpace(input.GetName()) == "" {
+		return apperror.InvalidArgument("name is required")
+	}
+
+	if _, err := time.Parse(DateLayout, input.GetDateOfBirth()); err != nil {
+		return apperror.InvalidArgument("date of birth %q must be YYYY-MM-DD", input.GetDateOfBirth())
+	}
+
+	expiry, err := time.Parse(DateLayout, input.GetDocumentExpiry())
+	if err != nil {
+		return apperror.InvalidArgument("document expiry %q must be YYYY-MM-DD", input.GetDocumentExpiry())
+	}
+
+	nationality := strings.ToUpper(strings.TrimSpace(input.GetNationality()))
+	if err := ValidateDocument(input.GetDocumentType(), input.GetDocumentNumber(), nationality); err != nil {
+		return apperror.InvalidArgument("%s", err)
+	}
+
+	in.Name = strings.TrimSpace(input.GetName())
+	in.DateOfBirth = encryption.EncryptedString(input.GetDateOfBirth())
+	in.DocumentType = input.GetDocumentType()
+	in.DocumentNumber = encryption.EncryptedString(strings.TrimSpace(input.GetDocumentNumber()))
+	in.DocumentExpiry = expiry
+	in.Nationality = nationality
+
+	return nil
+}
+
+// BeforeSave rejects incomplete documents whatever the caller checked
+func (in *SavedTraveller) BeforeSave(tx *gorm.DB) error {
+	return ValidateDocument(in.DocumentType, string(in.DocumentNumber), in.Nationality)
+}
+
+func (in *SavedTraveller) ToResponse() *protobuf.Traveller {
+	res := &protobuf.Traveller{
+		Id:             in.Id.String(),
+		CustomerId:     in.CustomerId,
+		Name:           in.Name,
+		DateOfBirth:    string(in.DateOfBirth),
+		DocumentType:   in.DocumentType,
+		DocumentNumber: string(in.DocumentNumber),
+		DocumentExpiry: in.DocumentExpiry.Format(DateLayout),
+		Nationality:    in.Nationality,
+		CreatedAt:      timestamppb.New(in.CreatedAt),
+		UpdatedAt:      timestamppb.New(in.UpdatedAt),
+	}
+
+	return res
+}
+
+// ValidateDocument checks the document fields that do not depend on a flight
+func ValidateDocument(documentType string, number string, nationality string) error {
+	if documentType != DocumentPassport && documentType != DocumentIdCard {
+		return fmt.Errorf("document type %q is invalid, use %s or %s", documentType, DocumentPassport, DocumentIdCard)
+	}
+	if strings.TrimSpace(number) == "" {
+		return fmt.Errorf("document number is required")
+	}
+	if len(nationality) != 2 || strings.ToUpper(nationality) != nationality {
+		return fmt.Errorf("nationality %q must be an ISO 3166 alpha-2 code", nationality)
+	}
+	if documentType == DocumentIdCard {
+		return rules.IdCard(nationality, strings.TrimSpace(number))
+	}
+
+	return nil
+}
+
+// CheckDocumentExpiry refuses a document that expires before the day of departure
+func CheckDocumentExpiry(expiry time.Time, departure time.Time) error {
+	departureDay := time.Date(departure.Year(), departure.Month(), departure.Day(), 0, 0, 0, 0, time.UTC)
+	expiryDay := time.Date(expiry.Year(), expiry.Month(), expiry.Day(), 0, 0, 0, 0, time.UTC)
+
+	if expiryDay.Before(departureDay) {
+		return fmt.Errorf("document expires on %s, before the departure on %s",
+			expiryDay.Format(DateLayout), departureDay.Format(DateLayout))
+	}
+
+	return nil
+}
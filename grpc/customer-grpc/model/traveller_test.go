@@ -0,0 +1,48 @@
+package customer_model
+
+import (
+	"errors"
+	"mock-golang/apperror"
+	"mock-golang/protobuf"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateDocument(t *testing.T) {
+	assert.Nil(t, ValidateDocument(DocumentPassport, "B1234567", "VN"))
+	assert.Nil(t, ValidateDocument(DocumentIdCard, "001099012345", "VN"))
+	assert.NotNil(t, ValidateDocument("visa", "B1234567", "VN"))
+	assert.NotNil(t, ValidateDocument(DocumentPassport, " ", "VN"))
+	assert.NotNil(t, ValidateDocument(DocumentPassport, "B1234567", "vn"))
+	assert.NotNil(t, ValidateDocument(DocumentPassport, "B1234567", "VNM"))
+}
+
+func TestCheckDocumentExpiry(t *testing.T) {
+	departure := time.Date(2026, 5, 10, 23, 30, 0, 0, time.UTC)
+
+	assert.Nil(t, CheckDocumentExpiry(time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC), departure))
+	// Valid on the day of departure is enough, whatever the hour
+	assert.Nil(t, CheckDocumentExpiry(time.Date(2026, 5, 10, 0, 0, 0, 0, time.UTC), departure))
+	assert.NotNil(t, CheckDocumentExpiry(time.Date(2026, 5, 9, 0, 0, 0, 0, time.UTC), departure))
+}
+
+func TestApplyTraveller(t *testing.T) {
+	traveller := &SavedTraveller{}
+	err := traveller.Apply(&protobuf.Passenger{
+		Name:           " Nguyen Van A ",
+		DateOfBirth:    "1990-02-03",
+		DocumentType:   DocumentPassport,
+		DocumentNumber: " B1234567 ",
+		DocumentExpiry: "2030-01-01",
+		Nationality:    "vn",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "Nguyen Van A", traveller.Name)
+	assert.Equal(t, "B1234567", string(traveller.DocumentNumber))
+	assert.Equal(t, "VN", traveller.Nationality)
+
+	err = traveller.Apply(&protobuf.Traveller{Name: "A", DateOfBirth: "03/02/1990"})
+	assert.True(t, errors.Is(err, apperror.ErrInvalidArgument))
+}
@@ -2,75 +2,381 @@ package customer_repo
 
 import (
 	"context"
+	"fmt"
+	"mock-golang/apperror"
 	"mock-golang/database"
+	"mock-golang/encryption"
+	audit_model "mock-golang/grpc/audit-grpc/model"
+	auth_model "mock-golang/grpc/auth-grpc/model"
+	booking_model "mock-golang/grpc/booking-grpc/model"
 	customer_model "mock-golang/grpc/customer-grpc/model"
 	customer_request "mock-golang/grpc/customer-grpc/request"
+	customer_response "mock-golang/grpc/customer-grpc/response"
+	loyalty_model "mock-golang/grpc/loyalty-grpc/model"
+	"mock-golang/helper"
+	"mock-golang/pagination"
 	"strings"
+	"time"
+	"unicode"
 
 	"github.com/google/uuid"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 //Embeded struct
 
 type CustomerRepository interface {
 	FindById(ctx context.Context, id uuid.UUID) (*customer_model.Customer, error)
+	FindByIdWithDeleted(ctx context.Context, id uuid.UUID) (*customer_model.Customer, error)
+	FindByEmail(ctx context.Context, email string) (*customer_model.Customer, error)
+	FindByRegisteredEmail(ctx context.Context, email string) (*customer_model.Customer, error)
 	CreateCustomer(ctx context.Context, model *customer_model.Customer) (*customer_model.Customer, error)
 	UpdateCustomer(ctx context.Context, model *customer_model.Customer) (*customer_model.Customer, error)
-	SearchCustomer(ctx context.Context, req *customer_request.SearchCustomerRequest) ([]*customer_model.Customer, error)
+	SearchCustomer(ctx context.Context, req *customer_request.SearchCustomerRequest) ([]*customer_model.Customer, string, error)
+	CountCustomer(ctx context.Context, req *customer_request.SearchCustomerRequest) (int64, error)
+	SetEmailVerifiedAt(ctx context.Context, id uuid.UUID, at *time.Time) error
+	SetPhoneVerifiedAt(ctx context.Context, id uuid.UUID, at *time.Time) error
+	SetLoyaltyTier(ctx context.Context, id uuid.UUID, tier string) error
+	SetRole(ctx context.Context, id uuid.UUID, role int32) error
+	MergeCustomers(ctx context.Context, targetId uuid.UUID, sourceIds []uuid.UUID) (int64, error)
+	FindDuplicates(ctx context.Context, limit int) ([]*customer_response.DuplicateGroup, error)
+	FindTravellerById(ctx context.Context, id uuid.UUID) (*customer_model.SavedTraveller, error)
+	ListTravellers(ctx context.Context, customerId string) ([]*customer_model.SavedTraveller, error)
+	CreateTraveller(ctx context.Context, model *customer_model.SavedTraveller) (*customer_model.SavedTraveller, error)
+	UpdateTraveller(ctx context.Context, model *customer_model.SavedTraveller) (*customer_model.SavedTraveller, error)
+	DeleteTraveller(ctx context.Context, id uuid.UUID) error
+	EraseCustomer(ctx context.Context, id uuid.UUID, at time.Time, audit EraseAudit) (int64, int64, error)
+	DeactivateCustomer(ctx context.Context, id uuid.UUID, now time.Time) error
+	FindNotificationPreference(ctx context.Context, customerId string) (*customer_model.NotificationPreference, error)
+	SaveNotificationPreference(ctx context.Context, model *customer_model.NotificationPreference, consents []*customer_model.ConsentRecord) (*customer_model.NotificationPreference, error)
+	ListConsentRecords(ctx context.Context, customerId string, limit int) ([]*customer_model.ConsentRecord, error)
+	// Ping checks the database is reachable, used by the health checks
+	Ping(ctx context.Context) error
+}
+
+// Merged duplicates are kept for the audit trail but never returned by lookups
+const notMerged = "COALESCE(merged_into_id, '') = ''"
+
+// searchableName is the name folded for search, Vietnamese diacritics included
+const searchableName = "immutable_unaccent(LOWER(customer_name))"
+
+// searchIndexes back the partial matches of SearchCustomer, the trigram indexes serve LIKE '%...%'
+var searchIndexes = []string{
+	"CREATE EXTENSION IF NOT EXISTS unaccent",
+	"CREATE EXTENSION IF NOT EXISTS pg_trgm",
+	// unaccent is only STABLE, an index needs an IMMUTABLE function
+	`CREATE OR REPLACE FUNCTION immutable_unaccent(text) RETURNS text
+		AS $$ SELECT public.unaccent('public.unaccent', $1) $$
+		LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT`,
+	"CREATE INDEX IF NOT EXISTS customers_name_trgm_idx ON customers USING gin (" + searchableName + " gin_trgm_ops)",
+	"CREATE INDEX IF NOT EXISTS customers_email_prefix_idx ON customers (LOWER(email) text_pattern_ops)",
+	// exact email matches ignore case and surrounding spaces, guests included
+	"CREATE INDEX IF NOT EXISTS customers_email_normalized_idx ON customers (LOWER(TRIM(email)))",
+	"CREATE INDEX IF NOT EXISTS customers_phone_trgm_idx ON customers USING gin (phone_number gin_trgm_ops)",
+}
+
+// Orders are the fields SearchCustomer pages by
+var Orders = pagination.Orders{
+	Default: "name",
+	Columns: map[string]string{
+		"name":      "customer_name",
+		"email":     "email",
+		"createdAt": "created_at",
+	},
+}
+
+// Registered, not merged and not deactivated customers must not share these fields. Guests may,
+// they are matched and merged later. Field is the api name reported back on a conflict.
+var uniqueIndexes = []struct {
+	Name       string
+	Field      string
+	Expression string
+}{
+	{Name: "customers_email_key", Field: "email", Expression: "LOWER(TRIM(email))"},
+	{Name: "customers_phone_number_key", Field: "phoneNumber", Expression: "phone_number"},
+	{Name: "customers_identity_card_key", Field: "identityCard", Expression: "identity_card_index"},
+}
+
+func init() {
+	for _, index := range uniqueIndexes {
+		apperror.RegisterUniqueIndex(index.Name, index.Field, "a customer with this %s already exists", index.Field)
+	}
 }
 
 type dbmanager struct {
 	*gorm.DB
 }
 
+func (m *dbmanager) Ping(ctx context.Context) error {
+	return database.Ping(ctx, m.DB)
+}
+
 func NewDBManager() (CustomerRepository, error) {
 	db, err := database.NewGormDB()
 	if err != nil {
 		return nil, err
 	}
 
-	db = db.Debug()
-
 	err = db.AutoMigrate(
 		&customer_model.Customer{},
+		&customer_model.SavedTraveller{},
+		&customer_model.NotificationPreference{},
+		&customer_model.ConsentRecord{},
 	)
 
 	if err != nil {
 		return nil, err
 	}
 
+	if err := backfillPhones(db); err != nil {
+		return nil, fmt.Errorf("normalize phone numbers: %w", err)
+	}
+
+	// Before the unique indexes, the identity card one reads the blind index
+	if err := backfillEncryption(db); err != nil {
+		return nil, fmt.Errorf("encrypt customer personal data: %w", err)
+	}
+
+	for _, index := range uniqueIndexes {
+		// Indexes made before deactivated customers were left out are made again
+		var definition string
+		err := db.Raw("SELECT indexdef FROM pg_indexes WHERE indexname = ?", index.Name).Scan(&definition).Error
+		if err != nil {
+			return nil, err
+		}
+		if definition != "" && !strings.Contains(definition, "deleted_at IS NULL") {
+			if err := db.Exec(fmt.Sprintf("DROP INDEX %s", index.Name)).Error; err != nil {
+				return nil, err
+			}
+		}
+
+		if err := checkCollisions(db, index.Field, index.Expression); err != nil {
+			return nil, err
+		}
+
+		err = db.Exec(fmt.Sprintf(
+			"CREATE UNIQUE INDEX IF NOT EXISTS %s ON customers (%s) WHERE role <> 0 AND %s AND deleted_at IS NULL AND %s <> ''",
+			index.Name, index.Expression, notMerged, index.Expression)).Error
+		if err != nil {
+			return nil, fmt.Errorf("create unique index on %s (merge the duplicates first): %w", index.Field, err)
+		}
+	}
+
+	for _, statement := range searchIndexes {
+		if err := db.Exec(statement).Error; err != nil {
+			return nil, fmt.Errorf("prepare customer search: %w", err)
+		}
+	}
+
 	return &dbmanager{db}, nil
 }
 
+// checkCollisions names the registered customers sharing a value of expression, the unique index
+// on it can not be created until they are merged
+func checkCollisions(db *gorm.DB, field string, expression string) error {
+	groups := []string{}
+	err := db.Model(&customer_model.Customer{}).
+		Select("STRING_AGG(id::text, ', ' ORDER BY created_at)").
+		Where("role <> 0 AND deleted_at IS NULL AND " + notMerged + " AND " + expression + " <> ''").
+		Group(expression).
+		Having("COUNT(*) > 1").
+		Scan(&groups).Error
+	if err != nil {
+		return err
+	}
+	if len(groups) == 0 {
+		return nil
+	}
+
+	for _, ids := range groups {
+		zap.L().Error("customers share a value that must be unique", zap.String("field", field), zap.String("customerIds", ids))
+	}
+
+	return fmt.Errorf("%d groups of customers share their %s (first: %s), merge them before the unique index can be created",
+		len(groups), field, groups[0])
+}
+
+// encryptionBatch is the number of customers encrypted per statement by backfillEncryption
+const encryptionBatch = 500
+
+// backfillEncryption encrypts the date of birth, identity card and address of the customers saved
+// before they were encrypted at rest, and fills their identity card blind index. Each row goes
+// through BeforeSave, deactivated and erased customers included.
+func backfillEncryption(db *gorm.DB) error {
+	var encrypted int
+	for {
+		rows := []struct {
+			Id                uuid.UUID
+			DateOfBith        string
+			IdentityCard      string
+			IdentityCardIndex string
+			Address           string
+		}{}
+		err := db.Table("customers").
+			Select("id, COALESCE(date_of_bith, '') AS date_of_bith, COALESCE(identity_card, '') AS identity_card, " +
+				"COALESCE(identity_card_index, '') AS identity_card_index, COALESCE(address, '') AS address").
+			Where("(date_of_bith <> '' AND date_of_bith NOT LIKE 'enc:%') " +
+				"OR (identity_card <> '' AND identity_card NOT LIKE 'enc:%') " +
+				"OR (address <> '' AND address NOT LIKE 'enc:%') " +
+				"OR (identity_card <> '' AND COALESCE(identity_card_index, '') = '')").
+			Order("id").Limit(encryptionBatch).
+			Find(&rows).Error
+		if err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			break
+		}
+
+		keyring, err := encryption.Default()
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			customer := customer_model.Customer{Id: row.Id}
+			for _, field := range []struct {
+				raw    string
+				target *encryption.EncryptedString
+			}{
+				{row.DateOfBith, &customer.DateOfBith},
+				{row.IdentityCard, &customer.IdentityCard},
+				{row.Address, &customer.Address},
+			} {
+				plainText, err := keyring.Decrypt(field.raw)
+				if err != nil {
+					return fmt.Errorf("customer %s: %w", row.Id, err)
+				}
+				*field.target = encryption.EncryptedString(plainText)
+			}
+
+			if err := customer.BeforeSave(db); err != nil {
+				return err
+			}
+
+			// UpdateColumns keeps updated_at, the customer did not change anything
+			err = db.Unscoped().Model(&customer_model.Customer{}).Where("id = ?", row.Id).UpdateColumns(map[string]interface{}{
+				"date_of_bith":        customer.DateOfBith,
+				"identity_card":       customer.IdentityCard,
+				"identity_card_index": customer.IdentityCardIndex,
+				"address":             customer.Address,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		encrypted += len(rows)
+	}
+
+	if encrypted > 0 {
+		zap.L().Info("personal data of older customers encrypted at rest", zap.Int("customers", encrypted))
+	}
+
+	return nil
+}
+
+// backfillPhones rewrites the numbers saved before phones were stored as E.164, then gives every
+// number shared by registered customers to one of them: the first to verify it, else the oldest
+// account. The others lose the number and its verification, so the unique index can be created.
+func backfillPhones(db *gorm.DB) error {
+	rows := []struct {
+		Id          uuid.UUID
+		PhoneNumber string
+	}{}
+	err := db.Table("customers").Select("id, phone_number").
+		Where(`phone_number <> '' AND phone_number !~ '^\+[1-9][0-9]{7,14}$'`).
+		Find(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		normalized, err := helper.NormalizePhone(row.PhoneNumber, viper.GetString("customer.default_country_code"))
+		if err != nil {
+			zap.L().Warn("phone number left as is", zap.String("customerId", row.Id.String()), zap.Error(err))
+			continue
+		}
+
+		err = db.Table("customers").Where("id = ?", row.Id).UpdateColumn("phone_number", normalized).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	res := db.Exec(`UPDATE customers SET phone_number = '', phone_verified_at = NULL WHERE id IN (
+		SELECT id FROM (
+			SELECT id, ROW_NUMBER() OVER (PARTITION BY phone_number ORDER BY phone_verified_at NULLS LAST, created_at, id) AS rank
+			FROM customers WHERE role <> 0 AND ` + notMerged + ` AND deleted_at IS NULL AND phone_number <> ''
+		) numbered WHERE rank > 1)`)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected > 0 {
+		zap.L().Warn("phone numbers shared by several customers were removed from all but one", zap.Int64("customers", res.RowsAffected))
+	}
+
+	return nil
+}
+
 func (m *dbmanager) FindById(ctx context.Context, id uuid.UUID) (*customer_model.Customer, error) {
 	res := customer_model.Customer{}
-	if err := m.Where(&customer_model.Customer{Id: id}).First(&res).Error; err != nil {
-		return nil, err
+	if err := m.WithContext(ctx).Where(&customer_model.Customer{Id: id}).First(&res).Error; err != nil {
+		return nil, apperror.FromDB(err)
+	}
+
+	return &res, nil
+}
+
+// FindByIdWithDeleted also finds deactivated customers
+func (m *dbmanager) FindByIdWithDeleted(ctx context.Context, id uuid.UUID) (*customer_model.Customer, error) {
+	res := customer_model.Customer{}
+	if err := m.WithContext(ctx).Unscoped().Where(&customer_model.Customer{Id: id}).First(&res).Error; err != nil {
+		return nil, apperror.FromDB(err)
+	}
+
+	return &res, nil
+}
+
+func (m *dbmanager) FindByEmail(ctx context.Context, email string) (*customer_model.Customer, error) {
+	res := customer_model.Customer{}
+	if err := m.WithContext(ctx).Where("LOWER(TRIM(email)) = LOWER(TRIM(?))", email).Where(notMerged).First(&res).Error; err != nil {
+		return nil, apperror.FromDB(err)
+	}
+
+	return &res, nil
+}
+
+// FindByRegisteredEmail skips guest records, which may share the email of an account
+func (m *dbmanager) FindByRegisteredEmail(ctx context.Context, email string) (*customer_model.Customer, error) {
+	res := customer_model.Customer{}
+	if err := m.WithContext(ctx).Where("LOWER(TRIM(email)) = LOWER(TRIM(?)) AND role <> 0", email).Where(notMerged).First(&res).Error; err != nil {
+		return nil, apperror.FromDB(err)
 	}
 
 	return &res, nil
 }
 
 func (m *dbmanager) CreateCustomer(ctx context.Context, model *customer_model.Customer) (*customer_model.Customer, error) {
-	if err := m.Create(model).Error; err != nil {
-		return nil, err
+	if err := m.WithContext(ctx).Create(model).Error; err != nil {
+		return nil, apperror.FromDB(err)
 	}
 
 	return model, nil
 }
 
 func (m *dbmanager) UpdateCustomer(ctx context.Context, model *customer_model.Customer) (*customer_model.Customer, error) {
-	if err := m.Where(&customer_model.Customer{Id: model.Id}).Updates(model).Error; err != nil {
-		return nil, err
+	if err := m.WithContext(ctx).Where(&customer_model.Customer{Id: model.Id}).Updates(model).Error; err != nil {
+		return nil, apperror.FromDB(err)
 	}
 
 	return model, nil
 }
 
-func (m *dbmanager) SearchCustomer(ctx context.Context, req *customer_request.SearchCustomerRequest) ([]*customer_model.Customer, error) {
-	customers := []*customer_model.Customer{}
-
+// searchScope builds the filters shared by SearchCustomer and CountCustomer
+func (m *dbmanager) searchScope(ctx context.Context, req *customer_request.SearchCustomerRequest) (*gorm.DB, error) {
 	sbWhere := " 1=1 "
 	params := []interface{}{}
 	if len(strings.TrimSpace(req.Id)) > 0 {
@@ -78,24 +384,38 @@ func (m *dbmanager) SearchCustomer(ctx context.Context, req *customer_request.Se
 		params = append(params, req.Id)
 	}
 	if len(strings.TrimSpace(req.Name)) > 0 {
-		sbWhere += " AND Name = ? "
-		params = append(params, req.Name)
+		// Backed by the trigram index on the same expression
+		sbWhere += " AND " + searchableName + " LIKE '%' || immutable_unaccent(LOWER(?)) || '%' "
+		params = append(params, helper.EscapeLike(strings.TrimSpace(req.Name)))
 	}
 	if req.Role >= 0 {
 		sbWhere += " AND Role = ? "
 		params = append(params, req.Role)
 	}
 	if len(strings.TrimSpace(req.Email)) > 0 {
-		sbWhere += " AND Email = ? "
+		sbWhere += " AND LOWER(TRIM(email)) = LOWER(TRIM(?)) "
 		params = append(params, req.Email)
 	}
+	if len(strings.TrimSpace(req.EmailPrefix)) > 0 {
+		sbWhere += " AND LOWER(email) LIKE ? "
+		params = append(params, helper.EscapeLike(strings.ToLower(strings.TrimSpace(req.EmailPrefix)))+"%")
+	}
 	if len(strings.TrimSpace(req.PhoneNumber)) > 0 {
 		sbWhere += " AND phone_number = ? "
 		params = append(params, req.PhoneNumber)
 	}
+	if suffix := digitsOnly(req.PhoneSuffix); len(suffix) > 0 {
+		sbWhere += " AND phone_number LIKE ? "
+		params = append(params, "%"+suffix)
+	}
 	if len(strings.TrimSpace(req.IdentityCard)) > 0 {
-		sbWhere += " AND identity_card = ? "
-		params = append(params, req.IdentityCard)
+		index, err := encryption.BlindIndex(req.IdentityCard)
+		if err != nil {
+			return nil, err
+		}
+		// identity_card is encrypted, plaintext equality only matches rows not re-saved since encryption
+		sbWhere += " AND (identity_card_index = ? OR identity_card = ?) "
+		params = append(params, index, req.IdentityCard)
 	}
 	if len(strings.TrimSpace(req.MembershipCard)) > 0 {
 		sbWhere += " AND membership_card = ? "
@@ -105,10 +425,400 @@ func (m *dbmanager) SearchCustomer(ctx context.Context, req *customer_request.Se
 		sbWhere += " AND Status = ? "
 		params = append(params, req.Status)
 	}
+	if req.VerifiedOnly {
+		// Only contacts the customer proved to own can be matched
+		if len(strings.TrimSpace(req.Email)) > 0 {
+			sbWhere += " AND email_verified_at IS NOT NULL "
+		}
+		if len(strings.TrimSpace(req.PhoneNumber)) > 0 {
+			sbWhere += " AND phone_verified_at IS NOT NULL "
+		}
+	}
 
-	if err := m.Where(sbWhere, params...).Find(&customers).Error; err != nil {
-		return nil, err
+	db := m.WithContext(ctx).Model(&customer_model.Customer{})
+	if req.IncludeDeleted {
+		db = db.Unscoped()
+	}
+
+	return db.Where(sbWhere, params...).Where(notMerged), nil
+}
+
+// SearchCustomer returns a page of customers and the token of the next one, every match without a page
+func (m *dbmanager) SearchCustomer(ctx context.Context, req *customer_request.SearchCustomerRequest) ([]*customer_model.Customer, string, error) {
+	customers := []*customer_model.Customer{}
+
+	db, err := m.searchScope(ctx, req)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if req.Page == nil {
+		db = db.Order("customer_name, id")
+	}
+
+	if err := req.Page.Apply(db, "id").Find(&customers).Error; err != nil {
+		return nil, "", apperror.FromDB(err)
+	}
+
+	nextPageToken := ""
+	if req.Page.HasNext(len(customers)) {
+		customers = customers[:req.Page.Size]
+		last := customers[len(customers)-1]
+		nextPageToken = req.Page.NextToken(sortKey(req.Page.Order.Field, last), last.Id.String())
+	}
+
+	return customers, nextPageToken, nil
+}
+
+// CountCustomer counts the matches of SearchCustomer across all pages
+func (m *dbmanager) CountCustomer(ctx context.Context, req *customer_request.SearchCustomerRequest) (int64, error) {
+	var count int64
+
+	db, err := m.searchScope(ctx, req)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := db.Count(&count).Error; err != nil {
+		return 0, apperror.FromDB(err)
+	}
+
+	return count, nil
+}
+
+// sortKey is the value of the order by field the next page starts after
+func sortKey(field string, customer *customer_model.Customer) interface{} {
+	switch field {
+	case "email":
+		return customer.Email
+	case "createdAt":
+		return customer.CreatedAt
+	default:
+		return customer.Name
+	}
+}
+
+// digitsOnly keeps the digits of a phone fragment, stored numbers are E.164
+func digitsOnly(s string) string {
+	digits := strings.Builder{}
+	for _, r := range s {
+		if unicode.IsDigit(r) {
+			digits.WriteRune(r)
+		}
+	}
+
+	return digits.String()
+}
+
+// SetEmailVerifiedAt writes the column even when at is nil, which Updates(model) would skip
+func (m *dbmanager) SetEmailVerifiedAt(ctx context.Context, id uuid.UUID, at *time.Time) error {
+	return apperror.FromDB(m.WithContext(ctx).Model(&customer_model.Customer{}).Where("id = ?", id).Update("email_verified_at", at).Error)
+}
+
+func (m *dbmanager) SetPhoneVerifiedAt(ctx context.Context, id uuid.UUID, at *time.Time) error {
+	return apperror.FromDB(m.WithContext(ctx).Model(&customer_model.Customer{}).Where("id = ?", id).Update("phone_verified_at", at).Error)
+}
+
+// SetLoyaltyTier keeps the copy of the loyalty account tier shown in the customer responses
+func (m *dbmanager) SetLoyaltyTier(ctx context.Context, id uuid.UUID, tier string) error {
+	return apperror.FromDB(m.WithContext(ctx).Model(&customer_model.Customer{}).Where("id = ?", id).UpdateColumn("loyalty_tier", tier).Error)
+}
+
+func (m *dbmanager) SetRole(ctx context.Context, id uuid.UUID, role int32) error {
+	err := m.WithContext(ctx).Model(&customer_model.Customer{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"role": role, "updated_at": time.Now()}).Error
+
+	// Leaving the guest role brings the row under the unique indexes
+	return apperror.FromDB(err)
+}
+
+// MergeCustomers moves the bookings, saved travellers and loyalty points of every source to the target and retires the sources, all or nothing
+func (m *dbmanager) MergeCustomers(ctx context.Context, targetId uuid.UUID, sourceIds []uuid.UUID) (int64, error) {
+	var moved int64
+
+	err := m.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Table("bookings").Where("customer_id IN ?", sourceIds).
+			Updates(map[string]interface{}{"customer_id": targetId, "updated_at": time.Now()})
+		if res.Error != nil {
+			return res.Error
+		}
+		moved = res.RowsAffected
+
+		err := tx.Model(&customer_model.SavedTraveller{}).Where("customer_id IN ?", sourceIds).
+			Updates(map[string]interface{}{"customer_id": targetId.String(), "updated_at": time.Now()}).Error
+		if err != nil {
+			return err
+		}
+
+		if err := mergeLoyalty(tx, targetId, sourceIds); err != nil {
+			return err
+		}
+
+		return tx.Model(&customer_model.Customer{}).Where("id IN ?", sourceIds).
+			Updates(map[string]interface{}{"merged_into_id": targetId.String(), "status": 0, "updated_at": time.Now()}).Error
+	})
+	if err != nil {
+		return 0, apperror.FromDB(err)
+	}
+
+	return moved, nil
+}
+
+// mergeLoyalty moves the ledger and adds the balances to the target account. The tier is
+// left for the loyalty service to recalculate on the next read, from the moved accruals.
+func mergeLoyalty(tx *gorm.DB, targetId uuid.UUID, sourceIds []uuid.UUID) error {
+	err := tx.Model(&loyalty_model.LoyaltyTransaction{}).Where("customer_id IN ?", sourceIds).
+		Update("customer_id", targetId.String()).Error
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	err = tx.Exec(`INSERT INTO loyalty_accounts (customer_id, balance, qualifying_points, tier, tier_updated_at, created_at, updated_at)
+		SELECT ?, SUM(balance), 0, ?, ?, ?, ? FROM loyalty_accounts WHERE customer_id IN ? HAVING COUNT(*) > 0
+		ON CONFLICT (customer_id) DO UPDATE SET balance = loyalty_accounts.balance + EXCLUDED.balance,
+			tier_updated_at = EXCLUDED.tier_updated_at, updated_at = EXCLUDED.updated_at`,
+		targetId.String(), loyalty_model.Tiers[0].Name, time.Time{}, now, now, sourceIds).Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Where("customer_id IN ?", sourceIds).Delete(&loyalty_model.LoyaltyAccount{}).Error
+}
+
+var duplicateKeys = []struct {
+	field string
+	expr  string
+}{
+	{field: "email", expr: "LOWER(TRIM(email))"},
+	{field: "phone_number", expr: "REGEXP_REPLACE(phone_number, '[^0-9]', '', 'g')"},
+	{field: "identity_card", expr: "identity_card_index"},
+}
+
+// FindDuplicates groups customers that share a normalized email, phone number or identity card
+func (m *dbmanager) FindDuplicates(ctx context.Context, limit int) ([]*customer_response.DuplicateGroup, error) {
+	groups := []*customer_response.DuplicateGroup{}
+
+	for _, key := range duplicateKeys {
+		rows := []struct {
+			Ids string
+		}{}
+
+		err := m.WithContext(ctx).Model(&customer_model.Customer{}).
+			Select("STRING_AGG(id::text, ',' ORDER BY created_at) AS ids").
+			Where(notMerged).
+			Where(key.expr + " <> ''").
+			Group(key.expr).
+			Having("COUNT(*) > 1").
+			Limit(limit).
+			Scan(&rows).Error
+		if err != nil {
+			return nil, apperror.FromDB(err)
+		}
+
+		for _, row := range rows {
+			groups = append(groups, &customer_response.DuplicateGroup{
+				MatchField:  key.field,
+				CustomerIds: strings.Split(row.Ids, ","),
+			})
+		}
+	}
+
+	return groups, nil
+}
+
+func (m *dbmanager) FindTravellerById(ctx context.Context, id uuid.UUID) (*customer_model.SavedTraveller, error) {
+	res := customer_model.SavedTraveller{}
+	if err := m.WithContext(ctx).Where(&customer_model.SavedTraveller{Id: id}).First(&res).Error; err != nil {
+		return nil, apperror.FromDB(err)
+	}
+
+	return &res, nil
+}
+
+func (m *dbmanager) ListTravellers(ctx context.Context, customerId string) ([]*customer_model.SavedTraveller, error) {
+	travellers := []*customer_model.SavedTraveller{}
+	if err := m.WithContext(ctx).Where(&customer_model.SavedTraveller{CustomerId: customerId}).Order("name").Find(&travellers).Error; err != nil {
+		return nil, apperror.FromDB(err)
+	}
+
+	return travellers, nil
+}
+
+func (m *dbmanager) CreateTraveller(ctx context.Context, model *customer_model.SavedTraveller) (*customer_model.SavedTraveller, error) {
+	if err := m.WithContext(ctx).Create(model).Error; err != nil {
+		return nil, apperror.FromDB(err)
+	}
+
+	return model, nil
+}
+
+func (m *dbmanager) UpdateTraveller(ctx context.Context, model *customer_model.SavedTraveller) (*customer_model.SavedTraveller, error) {
+	if err := m.WithContext(ctx).Where(&customer_model.SavedTraveller{Id: model.Id}).Updates(model).Error; err != nil {
+		return nil, apperror.FromDB(err)
+	}
+
+	return model, nil
+}
+
+// DeleteTraveller only removes the profile entry, bookings keep their own passenger copy
+func (m *dbmanager) DeleteTraveller(ctx context.Context, id uuid.UUID) error {
+	return apperror.FromDB(m.WithContext(ctx).Where(&customer_model.SavedTraveller{Id: id}).Delete(&customer_model.SavedTraveller{}).Error)
+}
+
+// EraseAudit makes the audit entry of an erasure once its counts are known
+type EraseAudit func(passengers int64, travellers int64) *audit_model.AuditLog
+
+// EraseCustomer overwrites the personal data of the customer and of the passengers on its bookings
+// and deletes its saved travellers. The rows stay so bookings and accounting remain consistent.
+// It returns the number of anonymized passengers and deleted travellers.
+func (m *dbmanager) EraseCustomer(ctx context.Context, id uuid.UUID, at time.Time, audit EraseAudit) (int64, int64, error) {
+	var passengers, travellers int64
+
+	err := m.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// A deactivated customer may still ask to be erased
+		err := tx.Unscoped().Model(&customer_model.Customer{}).Where("id = ?", id).
+			Updates(map[string]interface{}{
+				"customer_name":       "Erased customer",
+				"email":               fmt.Sprintf("erased+%s@invalid", id),
+				"phone_number":        "",
+				"date_of_bith":        "",
+				"identity_card":       "",
+				"identity_card_index": "",
+				"address":             "",
+				"membership_card":     "",
+				"password":            "",
+				"email_verified_at":   nil,
+				"phone_verified_at":   nil,
+				"role":                0,
+				"status":              0,
+				"erased_at":           at,
+				"updated_at":          at,
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		res := tx.Table("booking_passengers").
+			Where("booking_id IN (?)", tx.Table("bookings").Select("id").Where("customer_id = ?", id.String())).
+			Updates(map[string]interface{}{
+				"name":            "Erased passenger",
+				"traveller_id":    "",
+				"date_of_birth":   "",
+				"document_number": "",
+				"nationality":     "",
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		passengers = res.RowsAffected
+
+		res = tx.Where(&customer_model.SavedTraveller{CustomerId: id.String()}).Delete(&customer_model.SavedTraveller{})
+		if res.Error != nil {
+			return res.Error
+		}
+		travellers = res.RowsAffected
+
+		// The failed logins and the tokens name the customer, the tokens also the email or phone they were sent to
+		err = tx.Where("key = ?", auth_model.AccountThrottleKey(id.String())).Delete(&auth_model.LoginThrottle{}).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("customer_id = ?", id.String()).Delete(&auth_model.Token{}).Error; err != nil {
+			return err
+		}
+
+		// The consent history stays as proof of what was agreed, without the address it was given from
+		err = tx.Where(&customer_model.NotificationPreference{CustomerId: id.String()}).Delete(&customer_model.NotificationPreference{}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&customer_model.ConsentRecord{}).Where("customer_id = ?", id.String()).Update("ip", "").Error
+		if err != nil {
+			return err
+		}
+
+		// Written with the erasure, there is no erasure without its audit entry
+		return tx.Create(audit(passengers, travellers)).Error
+	})
+	if err != nil {
+		return 0, 0, apperror.FromDB(err)
+	}
+
+	return passengers, travellers, nil
+}
+
+// DeactivateCustomer soft deletes the customer when it has no trip to come, its bookings keep pointing to the row.
+// The customer row is locked first, a booking being made holds a share lock on it and is waited for.
+func (m *dbmanager) DeactivateCustomer(ctx context.Context, id uuid.UUID, now time.Time) error {
+	err := m.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ids := []string{}
+		err := tx.Model(&customer_model.Customer{}).Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).Pluck("id", &ids).Error
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return apperror.NotFound("customer %s not found", id)
+		}
+
+		var upcoming int64
+		err = tx.Model(&booking_model.Booking{}).Scopes(booking_model.Upcoming(now)).
+			Where("bookings.customer_id = ?", id.String()).Count(&upcoming).Error
+		if err != nil {
+			return err
+		}
+		if upcoming > 0 {
+			return apperror.FailedPrecondition("customer %s has %d active bookings, cancel them first", id, upcoming)
+		}
+
+		return tx.Delete(&customer_model.Customer{Id: id}).Error
+	})
+
+	return apperror.FromDB(err)
+}
+
+func (m *dbmanager) FindNotificationPreference(ctx context.Context, customerId string) (*customer_model.NotificationPreference, error) {
+	res := customer_model.NotificationPreference{}
+	if err := m.WithContext(ctx).Where(&customer_model.NotificationPreference{CustomerId: customerId}).First(&res).Error; err != nil {
+		return nil, apperror.FromDB(err)
+	}
+
+	return &res, nil
+}
+
+// SaveNotificationPreference upserts the preference and appends the consent records in one transaction
+func (m *dbmanager) SaveNotificationPreference(ctx context.Context, model *customer_model.NotificationPreference, consents []*customer_model.ConsentRecord) (*customer_model.NotificationPreference, error) {
+	err := m.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "customer_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"email_enabled", "sms_enabled", "push_enabled",
+				"booking_updates", "flight_changes", "promotions",
+				"language", "updated_at",
+			}),
+		}).Create(model).Error
+		if err != nil {
+			return err
+		}
+
+		if len(consents) == 0 {
+			return nil
+		}
+
+		return tx.Create(&consents).Error
+	})
+	if err != nil {
+		return nil, apperror.FromDB(err)
+	}
+
+	return model, nil
+}
+
+func (m *dbmanager) ListConsentRecords(ctx context.Context, customerId string, limit int) ([]*customer_model.ConsentRecord, error) {
+	records := []*customer_model.ConsentRecord{}
+	if err := m.WithContext(ctx).Where(&customer_model.ConsentRecord{CustomerId: customerId}).Order("created_at DESC").Limit(limit).Find(&records).Error; err != nil {
+		return nil, apperror.FromDB(err)
 	}
 
-	return customers, nil
+	return records, nil
 }
@@ -0,0 +1,61 @@
+package customer_repo
+
+import (
+	"context"
+	customer_model "mock-golang/grpc/customer-grpc/model"
+	customer_request "mock-golang/grpc/customer-grpc/request"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// dryRun builds the statements against postgres without a server
+func dryRun(t *testing.T) *dbmanager {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+	})
+	assert.Nil(t, err)
+
+	return &dbmanager{db}
+}
+
+// searchSQL is the query SearchCustomer runs for req and its arguments
+func searchSQL(t *testing.T, req *customer_request.SearchCustomerRequest) (string, []interface{}) {
+	db, err := dryRun(t).searchScope(context.Background(), req)
+	assert.Nil(t, err)
+
+	stmt := db.Find(&[]*customer_model.Customer{}).Statement
+	return stmt.SQL.String(), stmt.Vars
+}
+
+func TestSearchCustomerEmailIgnoresCase(t *testing.T) {
+	sql, vars := searchSQL(t, &customer_request.SearchCustomerRequest{Email: " Alice@Example.com", Role: 0, Status: -1})
+
+	assert.Contains(t, sql, "LOWER(TRIM(email)) = LOWER(TRIM($2))")
+	assert.Equal(t, []interface{}{int32(0), " Alice@Example.com"}, vars)
+}
+
+func TestSearchCustomerNameIgnoresAccentsAndCase(t *testing.T) {
+	sql, vars := searchSQL(t, &customer_request.SearchCustomerRequest{Name: " Nguyễn Văn ", Role: -1, Status: -1})
+
+	assert.Contains(t, sql, "immutable_unaccent(LOWER(customer_name)) LIKE '%' || immutable_unaccent(LOWER($1)) || '%'")
+	assert.Equal(t, []interface{}{"Nguyễn Văn"}, vars)
+}
+
+func TestSearchCustomerNameFiltersCustomerName(t *testing.T) {
+	sql, _ := searchSQL(t, &customer_request.SearchCustomerRequest{Name: "an", Role: -1, Status: -1})
+
+	// customers has no Name column, the filter must use customer_name
+	assert.NotContains(t, sql, "Name = ?")
+	assert.NotContains(t, sql, " Name ")
+	assert.Contains(t, sql, "LOWER(customer_name)")
+}
+
+func TestSearchCustomerNameEscapesWildcards(t *testing.T) {
+	_, vars := searchSQL(t, &customer_request.SearchCustomerRequest{Name: "50%_off", Role: -1, Status: -1})
+
+	assert.Equal(t, []interface{}{`50\%\_off`}, vars)
+}
@@ -1,6 +1,10 @@
 package customer_request
 
-import "github.com/google/uuid"
+import (
+	"mock-golang/pagination"
+
+	"github.com/google/uuid"
+)
 
 type CustomerRequest struct {
 	Id             uuid.UUID
@@ -25,4 +29,10 @@ type SearchCustomerRequest struct {
 	IdentityCard   string
 	MembershipCard string
 	Status         int32
+	VerifiedOnly   bool
+	IncludeDeleted bool
+	EmailPrefix    string
+	PhoneSuffix    string
+	// Page is nil to return every match
+	Page *pagination.Page
 }
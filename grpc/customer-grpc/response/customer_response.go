@@ -15,3 +15,9 @@ type CustomerResponse struct {
 	Password       string
 	Status         int32
 }
+
+// DuplicateGroup is a set of customers sharing the same normalized email, phone or identity card
+type DuplicateGroup struct {
+	MatchField  string
+	CustomerIds []string
+}
@@ -0,0 +1,146 @@
+package customer_response
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	ExportFormatJSON = "json"
+	ExportFormatZip  = "zip"
+)
+
+// CustomerExport is everything stored about a customer, answered to a data subject request
+type CustomerExport struct {
+	ExportedAt   time.Time             `json:"exportedAt"`
+	Customer     *ExportCustomer       `json:"customer"`
+	Travellers   []*ExportTraveller    `json:"travellers"`
+	Bookings     []*ExportBooking      `json:"bookings"`
+	Loyalty      []*ExportLoyaltyEntry `json:"loyalty"`
+	AuditEntries []*ExportAuditEntry   `json:"auditEntries"`
+	Consents     []*ExportConsent      `json:"consents"`
+}
+
+type ExportCustomer struct {
+	Id              string     `json:"id"`
+	Role            string     `json:"role"`
+	Name            string     `json:"name"`
+	Email           string     `json:"email"`
+	PhoneNumber     string     `json:"phoneNumber"`
+	DateOfBirth     string     `json:"dateOfBirth"`
+	IdentityCard    string     `json:"identityCard"`
+	Address         string     `json:"address"`
+	MembershipCard  string     `json:"membershipCard"`
+	LoyaltyTier     string     `json:"loyaltyTier"`
+	Status          int32      `json:"status"`
+	EmailVerifiedAt *time.Time `json:"emailVerifiedAt"`
+	PhoneVerifiedAt *time.Time `json:"phoneVerifiedAt"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
+}
+
+type ExportTraveller struct {
+	Id             string `json:"id"`
+	Name           string `json:"name"`
+	DateOfBirth    string `json:"dateOfBirth"`
+	DocumentType   string `json:"documentType"`
+	DocumentNumber string `json:"documentNumber"`
+	DocumentExpiry string `json:"documentExpiry"`
+	Nationality    string `json:"nationality"`
+}
+
+type ExportBooking struct {
+	Id         string             `json:"id"`
+	Code       string             `json:"code"`
+	FlightId   string             `json:"flightId"`
+	FlightName string             `json:"flightName"`
+	From       string             `json:"from"`
+	To         string             `json:"to"`
+	DepartDate time.Time          `json:"departDate"`
+	BookedSlot int32              `json:"bookedSlot"`
+	BookedDate time.Time          `json:"bookedDate"`
+	Status     string             `json:"status"`
+	Passengers []*ExportTraveller `json:"passengers"`
+}
+
+type ExportLoyaltyEntry struct {
+	Kind        string    `json:"kind"`
+	Points      int64     `json:"points"`
+	BookingId   string    `json:"bookingId,omitempty"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type ExportAuditEntry struct {
+	Action    string    `json:"action"`
+	ActorId   string    `json:"actorId"`
+	Detail    string    `json:"detail"`
+	Ip        string    `json:"ip"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type ExportConsent struct {
+	Purpose   string    `json:"purpose"`
+	Granted   bool      `json:"granted"`
+	ActorId   string    `json:"actorId"`
+	Ip        string    `json:"ip"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Bundle serializes the export as one JSON document, or as a ZIP with one JSON file per section.
+// It returns the content, its content type and a file name.
+func (in *CustomerExport) Bundle(format string) ([]byte, string, string, error) {
+	baseName := fmt.Sprintf("customer-%s-%s", in.Customer.Id, in.ExportedAt.UTC().Format("20060102T150405Z"))
+
+	switch format {
+	case "", ExportFormatJSON:
+		content, err := json.MarshalIndent(in, "", "  ")
+		if err != nil {
+			return nil, "", "", err
+		}
+		return content, "application/json", baseName + ".json", nil
+
+	case ExportFormatZip:
+		buf := &bytes.Buffer{}
+		w := zip.NewWriter(buf)
+
+		sections := []struct {
+			name  string
+			value interface{}
+		}{
+			{name: "customer.json", value: in.Customer},
+			{name: "travellers.json", value: in.Travellers},
+			{name: "bookings.json", value: in.Bookings},
+			{name: "loyalty.json", value: in.Loyalty},
+			{name: "audit_entries.json", value: in.AuditEntries},
+			{name: "consents.json", value: in.Consents},
+		}
+
+		for _, section := range sections {
+			f, err := w.CreateHeader(&zip.FileHeader{
+				Name:     section.name,
+				Method:   zip.Deflate,
+				Modified: in.ExportedAt,
+			})
+			if err != nil {
+				return nil, "", "", err
+			}
+
+			enc := json.NewEncoder(f)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(section.value); err != nil {
+				return nil, "", "", err
+			}
+		}
+
+		if err := w.Close(); err != nil {
+			return nil, "", "", err
+		}
+		return buf.Bytes(), "application/zip", baseName + ".zip", nil
+	}
+
+	return nil, "", "", fmt.Errorf("export format %q is invalid, use %s or %s", format, ExportFormatJSON, ExportFormatZip)
+}
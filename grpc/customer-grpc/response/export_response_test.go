@@ -0,0 +1,53 @@
+package customer_response
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newExport() *CustomerExport {
+	return &CustomerExport{
+		ExportedAt: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+		Customer:   &ExportCustomer{Id: "c1", Name: "Nguyen Van A", Email: "a@example.com"},
+		Travellers: []*ExportTraveller{{Id: "t1", Name: "Nguyen Van B"}},
+		Bookings:   []*ExportBooking{{Id: "b1", Code: "VN_ABCDEF"}},
+	}
+}
+
+func TestBundleJSON(t *testing.T) {
+	content, contentType, fileName, err := newExport().Bundle(ExportFormatJSON)
+	assert.Nil(t, err)
+	assert.Equal(t, "application/json", contentType)
+	assert.Equal(t, "customer-c1-20260301T100000Z.json", fileName)
+
+	decoded := CustomerExport{}
+	assert.Nil(t, json.Unmarshal(content, &decoded))
+	assert.Equal(t, "a@example.com", decoded.Customer.Email)
+	assert.Equal(t, "VN_ABCDEF", decoded.Bookings[0].Code)
+}
+
+func TestBundleZip(t *testing.T) {
+	content, contentType, fileName, err := newExport().Bundle(ExportFormatZip)
+	assert.Nil(t, err)
+	assert.Equal(t, "application/zip", contentType)
+	assert.Equal(t, "customer-c1-20260301T100000Z.zip", fileName)
+
+	r, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	assert.Nil(t, err)
+
+	names := []string{}
+	for _, f := range r.File {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, []string{"customer.json", "travellers.json", "bookings.json", "loyalty.json", "audit_entries.json", "consents.json"}, names)
+}
+
+func TestBundleInvalidFormat(t *testing.T) {
+	_, _, _, err := newExport().Bundle("xml")
+	assert.NotNil(t, err)
+}
@@ -2,52 +2,110 @@ package customer_handler
 
 import (
 	"context"
-	"database/sql"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"mock-golang/apperror"
+	"mock-golang/encryption"
+	audit_model "mock-golang/grpc/audit-grpc/model"
+	audit_repo "mock-golang/grpc/audit-grpc/repository"
+	auth_handler "mock-golang/grpc/auth-grpc/service"
+	booking_repo "mock-golang/grpc/booking-grpc/repository"
 	customer_model "mock-golang/grpc/customer-grpc/model"
 	customer_repo "mock-golang/grpc/customer-grpc/repository"
 	customer_request "mock-golang/grpc/customer-grpc/request"
+	loyalty_repo "mock-golang/grpc/loyalty-grpc/repository"
+	"mock-golang/helper"
+	"mock-golang/pagination"
 	"mock-golang/protobuf"
+	"mock-golang/rbac"
+	"mock-golang/rules"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/spf13/viper"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
 )
 
 type CustomerHandler struct {
 	protobuf.UnimplementedRPCCustomerServer
 	customerRepository customer_repo.CustomerRepository
+	auditRepository    audit_repo.AuditRepository
+	bookingRepository  booking_repo.BookingRepository
+	loyaltyRepository  loyalty_repo.LoyaltyRepository
+	guard              *auth_handler.PasswordGuard
 	mu                 *sync.Mutex
 }
 
-func NewCustomerHandler(customerRepository customer_repo.CustomerRepository) (*CustomerHandler, error) {
+func NewCustomerHandler(
+	customerRepository customer_repo.CustomerRepository,
+	auditRepository audit_repo.AuditRepository,
+	bookingRepository booking_repo.BookingRepository,
+	loyaltyRepository loyalty_repo.LoyaltyRepository,
+	guard *auth_handler.PasswordGuard) (*CustomerHandler, error) {
 	return &CustomerHandler{
 		customerRepository: customerRepository,
+		auditRepository:    auditRepository,
+		bookingRepository:  bookingRepository,
+		loyaltyRepository:  loyaltyRepository,
+		guard:              guard,
 		mu:                 &sync.Mutex{},
 	}, nil
 }
 
 func (h *CustomerHandler) FindById(ctx context.Context, in *protobuf.CustomerParamId) (*protobuf.Customer, error) {
-	out, err := h.customerRepository.FindById(ctx, uuid.MustParse(in.Id))
+	if err := rbac.RequireOwnOrAny(ctx, in.Id, rbac.PermCustomerReadOwn, rbac.PermCustomerReadAny); err != nil {
+		return nil, err
+	}
 
+	customerId, err := apperror.ParseID("id", in.Id)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, err
+	}
+
+	out, err := h.customerRepository.FindById(ctx, customerId)
+
+	if err != nil {
+		return nil, apperror.ToStatus(err)
 	}
 
 	return out.ToResponse(), nil
 }
 
 func (h *CustomerHandler) CreateCustomer(ctx context.Context, in *protobuf.Customer) (*protobuf.Customer, error) {
+	// Anybody can register, only role:assign may create staff accounts
+	role := rbac.Role(in.Role)
+	if !role.IsValid() {
+		return nil, status.Errorf(codes.InvalidArgument, "role %d is invalid", in.Role)
+	}
+	if role != rbac.RoleGuest && role != rbac.RoleCustomer {
+		if err := rbac.Require(ctx, rbac.PermRoleAssign); err != nil {
+			return nil, err
+		}
+	}
+
+	phoneNumber, err := normalizePhone(in.PhoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validatePersonalData(in.DateOfBith, in.IdentityCard); err != nil {
+		return nil, err
+	}
+
 	req := &customer_model.Customer{
 		Id:             uuid.New(),
 		Role:           in.Role,
 		Name:           in.Name,
-		Email:          in.Email,
-		PhoneNumber:    in.PhoneNumber,
-		DateOfBith:     in.DateOfBith,
-		IdentityCard:   in.IdentityCard,
-		Address:        in.Address,
+		Email:          strings.TrimSpace(in.Email),
+		PhoneNumber:    phoneNumber,
+		DateOfBith:     encryption.EncryptedString(in.DateOfBith),
+		IdentityCard:   encryption.EncryptedString(in.IdentityCard),
+		Address:        encryption.EncryptedString(in.Address),
 		MembershipCard: in.MembershipCard,
 		Password:       in.Password,
 		Status:         in.Status,
@@ -57,57 +115,71 @@ func (h *CustomerHandler) CreateCustomer(ctx context.Context, in *protobuf.Custo
 	customer, err := h.customerRepository.CreateCustomer(ctx, req)
 
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, apperror.ToStatus(err)
 	}
 
 	return customer.ToResponse(), nil
 }
 
 func (h *CustomerHandler) UpdateCustomer(ctx context.Context, in *protobuf.Customer) (*protobuf.Customer, error) {
-	req, err := h.customerRepository.FindById(ctx, uuid.MustParse(in.Id))
+	if err := rbac.RequireOwnOrAny(ctx, in.Id, rbac.PermCustomerWriteOwn, rbac.PermCustomerWriteAny); err != nil {
+		return nil, err
+	}
+
+	customerId, err := apperror.ParseID("id", in.Id)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, status.Error(codes.NotFound, err.Error())
-		}
 		return nil, err
 	}
 
-	if in.Role >= 0 {
-		req.Role = in.Role
+	req, err := h.customerRepository.FindById(ctx, customerId)
+	if err != nil {
+		return nil, apperror.ToStatus(err)
 	}
 
+	// Role is only changed through AssignRole
 	if in.Name != "" {
 		req.Name = in.Name
 	}
 
-	if in.Email != "" {
-		req.Email = in.Email
+	email := strings.TrimSpace(in.Email)
+	emailChanged := email != "" && !strings.EqualFold(email, req.Email)
+	if emailChanged {
+		req.Email = email
+		req.EmailVerifiedAt = nil
 	}
 
-	if in.PhoneNumber != "" {
-		req.PhoneNumber = in.PhoneNumber
+	phoneNumber, err := normalizePhone(in.PhoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validatePersonalData(in.DateOfBith, in.IdentityCard); err != nil {
+		return nil, err
+	}
+
+	phoneChanged := phoneNumber != "" && phoneNumber != req.PhoneNumber
+	if phoneChanged {
+		req.PhoneNumber = phoneNumber
+		req.PhoneVerifiedAt = nil
 	}
 
 	if in.DateOfBith != "" {
-		req.DateOfBith = in.DateOfBith
+		req.DateOfBith = encryption.EncryptedString(in.DateOfBith)
 	}
 
 	if in.IdentityCard != "" {
-		req.IdentityCard = in.IdentityCard
+		req.IdentityCard = encryption.EncryptedString(in.IdentityCard)
 	}
 
 	if in.Address != "" {
-		req.Address = in.Address
+		req.Address = encryption.EncryptedString(in.Address)
 	}
 
 	if in.MembershipCard != "" {
 		req.MembershipCard = in.MembershipCard
 	}
 
-	if in.Password != "" {
-		req.Password = in.Password
-	}
-
+	// The password only changes through ChangePassword, which checks the old one, and the reset flow
 	if in.Status >= 0 {
 		req.Status = in.Status
 	}
@@ -117,18 +189,53 @@ func (h *CustomerHandler) UpdateCustomer(ctx context.Context, in *protobuf.Custo
 	out, err := h.customerRepository.UpdateCustomer(ctx, req)
 
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, apperror.ToStatus(err)
+	}
+
+	// A new email or phone number has to be verified again
+	if emailChanged {
+		if err := h.customerRepository.SetEmailVerifiedAt(ctx, req.Id, nil); err != nil {
+			return nil, apperror.ToStatus(err)
+		}
+	}
+
+	if phoneChanged {
+		if err := h.customerRepository.SetPhoneVerifiedAt(ctx, req.Id, nil); err != nil {
+			return nil, apperror.ToStatus(err)
+		}
 	}
 
 	return out.ToResponse(), nil
 }
 
 func (h *CustomerHandler) ChangePassword(ctx context.Context, in *protobuf.ChangePasswordRequest) (*protobuf.ChangePasswordResponse, error) {
-	req, err := h.customerRepository.FindById(ctx, uuid.MustParse(in.CustomerId))
+	if err := rbac.RequireOwnOrAny(ctx, in.CustomerId, rbac.PermCustomerWriteOwn, rbac.PermCustomerWriteAny); err != nil {
+		return nil, err
+	}
+
+	customerId, err := apperror.ParseID("customerId", in.CustomerId)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, status.Error(codes.NotFound, err.Error())
+		return nil, err
+	}
+
+	req, err := h.customerRepository.FindById(ctx, customerId)
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	if err := h.guard.Check(ctx, in.CustomerId); err != nil {
+		return nil, err
+	}
+
+	// Old password arrives encrypted the same way it is stored
+	if subtle.ConstantTimeCompare([]byte(req.Password), []byte(in.OldPassword)) != 1 {
+		if err := h.guard.Fail(ctx, in.CustomerId); err != nil {
+			return nil, err
 		}
+		return nil, status.Error(codes.InvalidArgument, "Old password not match")
+	}
+
+	if err := h.guard.Succeed(ctx, in.CustomerId); err != nil {
 		return nil, err
 	}
 
@@ -138,10 +245,8 @@ func (h *CustomerHandler) ChangePassword(ctx context.Context, in *protobuf.Chang
 
 	req.UpdatedAt = time.Now()
 
-	custOut, err := h.customerRepository.UpdateCustomer(ctx, req)
-
-	if custOut != nil && err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+	if _, err := h.customerRepository.UpdateCustomer(ctx, req); err != nil {
+		return nil, apperror.ToStatus(err)
 	}
 
 	out := &protobuf.ChangePasswordResponse{
@@ -153,30 +258,433 @@ func (h *CustomerHandler) ChangePassword(ctx context.Context, in *protobuf.Chang
 }
 
 func (h *CustomerHandler) SearchCustomer(ctx context.Context, in *protobuf.SearchCustomerRequest) (*protobuf.SearchCustomerResponse, error) {
-	customers, err := h.customerRepository.SearchCustomer(ctx, &customer_request.SearchCustomerRequest{
-		Name:         in.Name,
-		Email:        in.Email,
-		PhoneNumber:  in.PhoneNumber,
-		IdentityCard: in.IdentityCard,
-	})
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, status.Error(codes.NotFound, err.Error())
-		}
+	if err := rbac.Require(ctx, rbac.PermCustomerReadAny); err != nil {
 		return nil, err
 	}
+	if in.IncludeDeleted {
+		if err := rbac.Require(ctx, rbac.PermDeletedRead); err != nil {
+			return nil, err
+		}
+	}
+
+	// Stored numbers are E.164, search input that can not be normalized is matched as is
+	phoneNumber := in.PhoneNumber
+	if normalized, err := normalizePhone(in.PhoneNumber); err == nil {
+		phoneNumber = normalized
+	}
+
+	page, err := pagination.NewPage(in.PageSize, in.PageToken, in.OrderBy, customer_repo.Orders)
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	req := &customer_request.SearchCustomerRequest{
+		Name:           in.Name,
+		Email:          in.Email,
+		PhoneNumber:    phoneNumber,
+		IdentityCard:   in.IdentityCard,
+		VerifiedOnly:   in.VerifiedOnly,
+		IncludeDeleted: in.IncludeDeleted,
+		EmailPrefix:    in.EmailPrefix,
+		PhoneSuffix:    in.PhoneSuffix,
+		Page:           page,
+		// Not filters of this request, 0 would only match guests with status 0
+		Role:   -1,
+		Status: -1,
+	}
+
+	customers, nextPageToken, err := h.customerRepository.SearchCustomer(ctx, req)
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	total, err := h.customerRepository.CountCustomer(ctx, req)
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
 
 	pRes := &protobuf.SearchCustomerResponse{
-		Customer: []*protobuf.Customer{},
+		Customer:      []*protobuf.Customer{},
+		TotalSize:     total,
+		NextPageToken: nextPageToken,
 	}
 
 	for _, customer := range customers {
 		pRes.Customer = append(pRes.Customer, customer.ToResponse())
 	}
 
+	return pRes, nil
+}
+
+func (h *CustomerHandler) AssignRole(ctx context.Context, in *protobuf.AssignRoleRequest) (*protobuf.Customer, error) {
+	if err := rbac.Require(ctx, rbac.PermRoleAssign); err != nil {
+		return nil, err
+	}
+
+	role, err := rbac.ParseRole(in.Role)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	customerId, err := apperror.ParseID("customerId", in.CustomerId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Admins can not lock themselves out
+	if in.CustomerId == rbac.FromContext(ctx).CustomerId && role != rbac.RoleAdmin {
+		return nil, status.Error(codes.FailedPrecondition, "admins can not change their own role")
+	}
+
+	req, err := h.customerRepository.FindById(ctx, customerId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		return nil, apperror.ToStatus(err)
+	}
+
+	req.Role = int32(role)
+	req.UpdatedAt = time.Now()
+
+	// Updates(model) skips zero values, guest is role 0
+	if err := h.customerRepository.SetRole(ctx, req.Id, req.Role); err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	return req.ToResponse(), nil
+}
+
+// DeactivateCustomer soft deletes a customer that has no upcoming trips, the bookings history is kept
+func (h *CustomerHandler) DeactivateCustomer(ctx context.Context, in *protobuf.DeactivateCustomerRequest) (*protobuf.Customer, error) {
+	if err := rbac.Require(ctx, rbac.PermCustomerDelete); err != nil {
+		return nil, err
+	}
+
+	customerId, err := apperror.ParseID("customerId", in.CustomerId)
 	if err != nil {
 		return nil, err
 	}
 
+	if in.CustomerId == rbac.FromContext(ctx).CustomerId {
+		return nil, status.Error(codes.FailedPrecondition, "admins can not deactivate themselves")
+	}
+
+	customer, err := h.customerRepository.FindById(ctx, customerId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "customer %s not found", customerId)
+		}
+		return nil, apperror.ToStatus(err)
+	}
+
+	// The upcoming bookings are counted in the deactivation transaction, under a lock of the customer
+	now := time.Now()
+	if err := h.customerRepository.DeactivateCustomer(ctx, customerId, now); err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	detail := "deactivated"
+	if reason := strings.TrimSpace(in.Reason); reason != "" {
+		detail = fmt.Sprintf("deactivated, reason: %s", reason)
+	}
+	if err := h.audit(ctx, audit_model.ActionCustomerDeleted, customerId, detail); err != nil {
+		return nil, err
+	}
+
+	customer.DeletedAt = gorm.DeletedAt{Time: now, Valid: true}
+
+	return customer.ToResponse(), nil
+}
+
+func (h *CustomerHandler) MergeCustomers(ctx context.Context, in *protobuf.MergeCustomersRequest) (*protobuf.MergeCustomersResponse, error) {
+	if err := rbac.Require(ctx, rbac.PermCustomerMerge); err != nil {
+		return nil, err
+	}
+
+	targetId, err := apperror.ParseID("targetId", in.TargetId)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceIds := []uuid.UUID{}
+	seen := map[uuid.UUID]bool{}
+	for _, id := range in.SourceIds {
+		sourceId, err := apperror.ParseID("sourceId", id)
+		if err != nil {
+			return nil, err
+		}
+		if sourceId == targetId {
+			return nil, status.Error(codes.InvalidArgument, "a customer can not be merged into itself")
+		}
+		if !seen[sourceId] {
+			seen[sourceId] = true
+			sourceIds = append(sourceIds, sourceId)
+		}
+	}
+
+	if len(sourceIds) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "source ids are required")
+	}
+
+	// Serialize merges so two admins can not merge the same records crosswise
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	target, err := h.findActiveCustomer(ctx, targetId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sourceId := range sourceIds {
+		if _, err := h.findActiveCustomer(ctx, sourceId); err != nil {
+			return nil, err
+		}
+	}
+
+	moved, err := h.customerRepository.MergeCustomers(ctx, targetId, sourceIds)
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	actorId := rbac.FromContext(ctx).CustomerId
+	for _, sourceId := range sourceIds {
+		_, err := h.auditRepository.CreateAuditLog(ctx, &audit_model.AuditLog{
+			Id:         uuid.New(),
+			ActorId:    actorId,
+			Action:     audit_model.ActionCustomerMerged,
+			TargetType: audit_model.TargetCustomer,
+			TargetId:   sourceId.String(),
+			Detail:     fmt.Sprintf("merged into %s, %d bookings moved in total", targetId, moved),
+			Ip:         auth_handler.ClientIp(ctx),
+			CreatedAt:  time.Now(),
+		})
+		if err != nil {
+			return nil, apperror.ToStatus(err)
+		}
+	}
+
+	out := &protobuf.MergeCustomersResponse{
+		Customer:      target.ToResponse(),
+		MovedBookings: moved,
+	}
+
+	return out, nil
+}
+
+func (h *CustomerHandler) FindDuplicateCustomers(ctx context.Context, in *protobuf.DuplicateCustomersRequest) (*protobuf.DuplicateCustomersResponse, error) {
+	if err := rbac.Require(ctx, rbac.PermCustomerReadAny); err != nil {
+		return nil, err
+	}
+
+	limit := int(in.Limit)
+	if limit <= 0 || limit > 100 {
+		limit = 100
+	}
+
+	groups, err := h.customerRepository.FindDuplicates(ctx, limit)
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	pRes := &protobuf.DuplicateCustomersResponse{
+		Group: []*protobuf.DuplicateGroup{},
+	}
+
+	for _, group := range groups {
+		pGroup := &protobuf.DuplicateGroup{
+			MatchField: group.MatchField,
+			Customer:   []*protobuf.Customer{},
+		}
+
+		for _, id := range group.CustomerIds {
+			customerId, err := apperror.ParseID("id", id)
+			if err != nil {
+				return nil, err
+			}
+
+			customer, err := h.customerRepository.FindById(ctx, customerId)
+			if err != nil {
+				return nil, apperror.ToStatus(err)
+			}
+			pGroup.Customer = append(pGroup.Customer, customer.ToResponse())
+		}
+
+		pRes.Group = append(pRes.Group, pGroup)
+	}
+
+	return pRes, nil
+}
+
+// normalizePhone formats the number as E.164, empty stays empty
+func normalizePhone(phone string) (string, error) {
+	if strings.TrimSpace(phone) == "" {
+		return "", nil
+	}
+
+	normalized, err := helper.NormalizePhone(phone, viper.GetString("customer.default_country_code"))
+	if err != nil {
+		return "", status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return normalized, nil
+}
+
+// validatePersonalData applies the gateway rules again for callers going straight to gRPC, empty values are skipped
+func validatePersonalData(dateOfBith string, identityCard string) error {
+	if dateOfBith != "" {
+		if err := rules.ISODate(dateOfBith); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	if identityCard != "" {
+		if err := rules.IdCard("", identityCard); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (h *CustomerHandler) findActiveCustomer(ctx context.Context, id uuid.UUID) (*customer_model.Customer, error) {
+	customer, err := h.customerRepository.FindById(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "customer %s not found", id)
+		}
+		return nil, apperror.ToStatus(err)
+	}
+
+	if customer.MergedIntoId != "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "customer %s is already merged into %s", id, customer.MergedIntoId)
+	}
+
+	return customer, nil
+}
+
+func (h *CustomerHandler) ListTravellers(ctx context.Context, in *protobuf.CustomerParamId) (*protobuf.ListTravellersResponse, error) {
+	if err := rbac.RequireOwnOrAny(ctx, in.Id, rbac.PermCustomerReadOwn, rbac.PermCustomerReadAny); err != nil {
+		return nil, err
+	}
+
+	travellers, err := h.customerRepository.ListTravellers(ctx, in.Id)
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	pRes := &protobuf.ListTravellersResponse{
+		Traveller: []*protobuf.Traveller{},
+	}
+
+	for _, traveller := range travellers {
+		pRes.Traveller = append(pRes.Traveller, traveller.ToResponse())
+	}
+
 	return pRes, nil
 }
+
+func (h *CustomerHandler) CreateTraveller(ctx context.Context, in *protobuf.Traveller) (*protobuf.Traveller, error) {
+	if err := rbac.RequireOwnOrAny(ctx, in.CustomerId, rbac.PermCustomerWriteOwn, rbac.PermCustomerWriteAny); err != nil {
+		return nil, err
+	}
+
+	customerId, err := apperror.ParseID("customerId", in.CustomerId)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := h.findActiveCustomer(ctx, customerId); err != nil {
+		return nil, err
+	}
+
+	req := &customer_model.SavedTraveller{
+		Id:         uuid.New(),
+		CustomerId: customerId.String(),
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+
+	if err := req.Apply(in); err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	traveller, err := h.customerRepository.CreateTraveller(ctx, req)
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	return traveller.ToResponse(), nil
+}
+
+func (h *CustomerHandler) UpdateTraveller(ctx context.Context, in *protobuf.Traveller) (*protobuf.Traveller, error) {
+	req, err := h.findTraveller(ctx, in.Id, rbac.PermCustomerWriteOwn, rbac.PermCustomerWriteAny)
+	if err != nil {
+		return nil, err
+	}
+
+	// Empty fields keep their value, the owner never changes
+	merged := &protobuf.Traveller{
+		Name:           firstNonEmpty(in.Name, req.Name),
+		DateOfBirth:    firstNonEmpty(in.DateOfBirth, string(req.DateOfBirth)),
+		DocumentType:   firstNonEmpty(in.DocumentType, req.DocumentType),
+		DocumentNumber: firstNonEmpty(in.DocumentNumber, string(req.DocumentNumber)),
+		DocumentExpiry: firstNonEmpty(in.DocumentExpiry, req.DocumentExpiry.Format(customer_model.DateLayout)),
+		Nationality:    firstNonEmpty(in.Nationality, req.Nationality),
+	}
+
+	if err := req.Apply(merged); err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+	req.UpdatedAt = time.Now()
+
+	traveller, err := h.customerRepository.UpdateTraveller(ctx, req)
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	return traveller.ToResponse(), nil
+}
+
+func (h *CustomerHandler) DeleteTraveller(ctx context.Context, in *protobuf.TravellerParamId) (*protobuf.Traveller, error) {
+	traveller, err := h.findTraveller(ctx, in.Id, rbac.PermCustomerWriteOwn, rbac.PermCustomerWriteAny)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.customerRepository.DeleteTraveller(ctx, traveller.Id); err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	return traveller.ToResponse(), nil
+}
+
+// findTraveller loads a saved traveller the caller may act on, as owner or with the any permission
+func (h *CustomerHandler) findTraveller(ctx context.Context, id string, own rbac.Permission, any rbac.Permission) (*customer_model.SavedTraveller, error) {
+	travellerId, err := apperror.ParseID("travellerId", id)
+	if err != nil {
+		return nil, err
+	}
+
+	traveller, err := h.customerRepository.FindTravellerById(ctx, travellerId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "traveller %s not found", travellerId)
+		}
+		return nil, apperror.ToStatus(err)
+	}
+
+	if err := rbac.RequireOwnOrAny(ctx, traveller.CustomerId, own, any); err != nil {
+		return nil, err
+	}
+
+	return traveller, nil
+}
+
+func firstNonEmpty(value string, fallback string) string {
+	if strings.TrimSpace(value) != "" {
+		return value
+	}
+
+	return fallback
+}
@@ -0,0 +1,143 @@
+package customer_handler
+
+import (
+	"context"
+	"errors"
+	"mock-golang/apperror"
+	auth_handler "mock-golang/grpc/auth-grpc/service"
+	customer_model "mock-golang/grpc/customer-grpc/model"
+	"mock-golang/notification"
+	"mock-golang/protobuf"
+	"mock-golang/rbac"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+// consentHistoryLimit bounds the consent records returned with the preferences
+const consentHistoryLimit = 50
+
+func (h *CustomerHandler) GetNotificationPreferences(ctx context.Context, in *protobuf.CustomerParamId) (*protobuf.NotificationPreferences, error) {
+	if err := rbac.RequireOwnOrAny(ctx, in.Id, rbac.PermCustomerReadOwn, rbac.PermCustomerReadAny); err != nil {
+		return nil, err
+	}
+
+	customerId, err := apperror.ParseID("id", in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := h.findActiveCustomer(ctx, customerId); err != nil {
+		return nil, err
+	}
+
+	preference, err := h.findNotificationPreference(ctx, customerId.String())
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	return h.preferenceResponse(ctx, preference)
+}
+
+// UpdateNotificationPreferences replaces the preferences, every granted or withdrawn consent is recorded
+func (h *CustomerHandler) UpdateNotificationPreferences(ctx context.Context, in *protobuf.NotificationPreferences) (*protobuf.NotificationPreferences, error) {
+	if err := rbac.RequireOwnOrAny(ctx, in.CustomerId, rbac.PermCustomerWriteOwn, rbac.PermCustomerWriteAny); err != nil {
+		return nil, err
+	}
+
+	customerId, err := apperror.ParseID("customerId", in.CustomerId)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := h.findActiveCustomer(ctx, customerId); err != nil {
+		return nil, err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	current, err := h.findNotificationPreference(ctx, customerId.String())
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	now := time.Now()
+	next := &customer_model.NotificationPreference{
+		CustomerId:     customerId.String(),
+		EmailEnabled:   in.EmailEnabled,
+		SmsEnabled:     in.SmsEnabled,
+		PushEnabled:    in.PushEnabled,
+		BookingUpdates: in.BookingUpdates,
+		FlightChanges:  in.FlightChanges,
+		Promotions:     in.Promotions,
+		Language:       firstNonEmpty(strings.ToLower(strings.TrimSpace(in.Language)), current.Language),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	if err := next.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	consents := []*customer_model.ConsentRecord{}
+	for _, change := range current.ConsentChanges(next) {
+		consents = append(consents, &customer_model.ConsentRecord{
+			Id:         uuid.New(),
+			CustomerId: customerId.String(),
+			Purpose:    change.Purpose,
+			Granted:    change.Granted,
+			ActorId:    rbac.FromContext(ctx).CustomerId,
+			Ip:         auth_handler.ClientIp(ctx),
+			CreatedAt:  now,
+		})
+	}
+
+	preference, err := h.customerRepository.SaveNotificationPreference(ctx, next, consents)
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	return h.preferenceResponse(ctx, preference)
+}
+
+// NotificationPreferences is the notification.PreferenceLookup of the sender
+func (h *CustomerHandler) NotificationPreferences(ctx context.Context, customerId string) (*notification.Preferences, error) {
+	preference, err := h.findNotificationPreference(ctx, customerId)
+	if err != nil {
+		return nil, err
+	}
+
+	return preference.ToNotification(), nil
+}
+
+// findNotificationPreference falls back to the defaults for customers that never saved theirs
+func (h *CustomerHandler) findNotificationPreference(ctx context.Context, customerId string) (*customer_model.NotificationPreference, error) {
+	preference, err := h.customerRepository.FindNotificationPreference(ctx, customerId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return customer_model.DefaultNotificationPreference(customerId), nil
+		}
+		return nil, err
+	}
+
+	return preference, nil
+}
+
+func (h *CustomerHandler) preferenceResponse(ctx context.Context, preference *customer_model.NotificationPreference) (*protobuf.NotificationPreferences, error) {
+	consents, err := h.customerRepository.ListConsentRecords(ctx, preference.CustomerId, consentHistoryLimit)
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	res := preference.ToResponse()
+	for _, consent := range consents {
+		res.Consent = append(res.Consent, consent.ToResponse())
+	}
+
+	return res, nil
+}
@@ -0,0 +1,287 @@
+package customer_handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mock-golang/apperror"
+	audit_model "mock-golang/grpc/audit-grpc/model"
+	auth_handler "mock-golang/grpc/auth-grpc/service"
+	booking_request "mock-golang/grpc/booking-grpc/request"
+	customer_model "mock-golang/grpc/customer-grpc/model"
+	customer_response "mock-golang/grpc/customer-grpc/response"
+	loyalty_request "mock-golang/grpc/loyalty-grpc/request"
+	"mock-golang/protobuf"
+	"mock-golang/rbac"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
+)
+
+// ExportCustomerData answers a data subject access request with everything stored about the customer
+func (h *CustomerHandler) ExportCustomerData(ctx context.Context, in *protobuf.ExportCustomerDataRequest) (*protobuf.ExportCustomerDataResponse, error) {
+	if err := rbac.Require(ctx, rbac.PermCustomerExport); err != nil {
+		return nil, err
+	}
+
+	customerId, err := apperror.ParseID("customerId", in.CustomerId)
+	if err != nil {
+		return nil, err
+	}
+
+	format := strings.ToLower(strings.TrimSpace(in.Format))
+	if format != "" && format != customer_response.ExportFormatJSON && format != customer_response.ExportFormatZip {
+		return nil, status.Errorf(codes.InvalidArgument, "format %q is invalid, use json or zip", in.Format)
+	}
+
+	customer, err := h.findCustomer(ctx, customerId)
+	if err != nil {
+		return nil, err
+	}
+
+	export, err := h.collectExport(ctx, customer)
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	content, contentType, fileName, err := export.Bundle(format)
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	if err := h.audit(ctx, audit_model.ActionCustomerExport, customerId, fmt.Sprintf("exported as %s", contentType)); err != nil {
+		return nil, err
+	}
+
+	out := &protobuf.ExportCustomerDataResponse{
+		FileName:    fileName,
+		ContentType: contentType,
+		Content:     content,
+	}
+
+	return out, nil
+}
+
+// EraseCustomer anonymizes the personal data of a customer on request. Bookings, loyalty
+// postings and the audit trail are kept, only what identifies the person is overwritten.
+func (h *CustomerHandler) EraseCustomer(ctx context.Context, in *protobuf.EraseCustomerRequest) (*protobuf.EraseCustomerResponse, error) {
+	if err := rbac.Require(ctx, rbac.PermCustomerErase); err != nil {
+		return nil, err
+	}
+
+	customerId, err := apperror.ParseID("customerId", in.CustomerId)
+	if err != nil {
+		return nil, err
+	}
+
+	reason := strings.TrimSpace(in.Reason)
+	if reason == "" {
+		return nil, status.Error(codes.InvalidArgument, "reason is required")
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	customer, err := h.findCustomer(ctx, customerId)
+	if err != nil {
+		return nil, err
+	}
+
+	if customer.ErasedAt != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "customer %s was already erased", customerId)
+	}
+
+	if rbac.Role(customer.Role) == rbac.RoleAdmin {
+		return nil, status.Error(codes.FailedPrecondition, "an admin account can not be erased, revoke the role first")
+	}
+
+	// Passengers must still be identifiable at check-in, upcoming trips are cancelled first
+	now := time.Now()
+	upcoming, err := h.bookingRepository.CountUpcomingBookings(ctx, customerId.String(), "", now)
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+	if upcoming > 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "customer %s has %d bookings still to fly, cancel them before erasing the customer", customerId, upcoming)
+	}
+
+	passengers, travellers, err := h.customerRepository.EraseCustomer(ctx, customerId, now, func(passengers int64, travellers int64) *audit_model.AuditLog {
+		detail := fmt.Sprintf("reason: %s, %d passengers anonymized, %d travellers deleted", reason, passengers, travellers)
+		return h.auditLog(ctx, audit_model.ActionCustomerErased, customerId, detail)
+	})
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	out := &protobuf.EraseCustomerResponse{
+		CustomerId:           customerId.String(),
+		ErasedAt:             timestamppb.New(now),
+		AnonymizedPassengers: passengers,
+		DeletedTravellers:    travellers,
+	}
+
+	return out, nil
+}
+
+// findCustomer also finds deactivated customers, their data is still held
+func (h *CustomerHandler) findCustomer(ctx context.Context, id uuid.UUID) (*customer_model.Customer, error) {
+	customer, err := h.customerRepository.FindByIdWithDeleted(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "customer %s not found", id)
+		}
+		return nil, apperror.ToStatus(err)
+	}
+
+	return customer, nil
+}
+
+func (h *CustomerHandler) audit(ctx context.Context, action string, customerId uuid.UUID, detail string) error {
+	if _, err := h.auditRepository.CreateAuditLog(ctx, h.auditLog(ctx, action, customerId, detail)); err != nil {
+		return apperror.ToStatus(err)
+	}
+
+	return nil
+}
+
+func (h *CustomerHandler) auditLog(ctx context.Context, action string, customerId uuid.UUID, detail string) *audit_model.AuditLog {
+	return &audit_model.AuditLog{
+		Id:         uuid.New(),
+		ActorId:    rbac.FromContext(ctx).CustomerId,
+		Action:     action,
+		TargetType: audit_model.TargetCustomer,
+		TargetId:   customerId.String(),
+		Detail:     detail,
+		Ip:         auth_handler.ClientIp(ctx),
+		CreatedAt:  time.Now(),
+	}
+}
+
+func (h *CustomerHandler) collectExport(ctx context.Context, customer *customer_model.Customer) (*customer_response.CustomerExport, error) {
+	customerId := customer.Id.String()
+
+	export := &customer_response.CustomerExport{
+		ExportedAt: time.Now(),
+		Customer: &customer_response.ExportCustomer{
+			Id:              customerId,
+			Role:            rbac.Role(customer.Role).String(),
+			Name:            customer.Name,
+			Email:           customer.Email,
+			PhoneNumber:     customer.PhoneNumber,
+			DateOfBirth:     string(customer.DateOfBith),
+			IdentityCard:    string(customer.IdentityCard),
+			Address:         string(customer.Address),
+			MembershipCard:  customer.MembershipCard,
+			LoyaltyTier:     customer.LoyaltyTier,
+			Status:          customer.Status,
+			EmailVerifiedAt: customer.EmailVerifiedAt,
+			PhoneVerifiedAt: customer.PhoneVerifiedAt,
+			CreatedAt:       customer.CreatedAt,
+			UpdatedAt:       customer.UpdatedAt,
+		},
+		Travellers:   []*customer_response.ExportTraveller{},
+		Bookings:     []*customer_response.ExportBooking{},
+		Loyalty:      []*customer_response.ExportLoyaltyEntry{},
+		AuditEntries: []*customer_response.ExportAuditEntry{},
+		Consents:     []*customer_response.ExportConsent{},
+	}
+
+	travellers, err := h.customerRepository.ListTravellers(ctx, customerId)
+	if err != nil {
+		return nil, err
+	}
+	for _, traveller := range travellers {
+		export.Travellers = append(export.Travellers, &customer_response.ExportTraveller{
+			Id:             traveller.Id.String(),
+			Name:           traveller.Name,
+			DateOfBirth:    string(traveller.DateOfBirth),
+			DocumentType:   traveller.DocumentType,
+			DocumentNumber: string(traveller.DocumentNumber),
+			DocumentExpiry: traveller.DocumentExpiry.Format(customer_model.DateLayout),
+			Nationality:    traveller.Nationality,
+		})
+	}
+
+	bookings, _, err := h.bookingRepository.SearchBooking(ctx, &booking_request.SearchBookingRequest{CustomerId: customerId})
+	if err != nil {
+		return nil, err
+	}
+	for _, booking := range bookings {
+		item := &customer_response.ExportBooking{
+			Id:         booking.Id.String(),
+			Code:       booking.Code,
+			FlightId:   booking.FlightId,
+			BookedSlot: booking.BookedSlot,
+			BookedDate: booking.BookedDate,
+			Status:     booking.Status,
+			Passengers: []*customer_response.ExportTraveller{},
+		}
+		if booking.Flight != nil {
+			item.FlightName = booking.Flight.NameFlight
+			item.From = booking.Flight.DepartureAirport
+			item.To = booking.Flight.DepartureArrival
+			item.DepartDate = booking.Flight.DepartDate
+		}
+		for _, passenger := range booking.Passengers {
+			item.Passengers = append(item.Passengers, &customer_response.ExportTraveller{
+				Id:             passenger.Id.String(),
+				Name:           passenger.Name,
+				DateOfBirth:    string(passenger.DateOfBirth),
+				DocumentType:   passenger.DocumentType,
+				DocumentNumber: string(passenger.DocumentNumber),
+				DocumentExpiry: passenger.DocumentExpiry.Format(customer_model.DateLayout),
+				Nationality:    passenger.Nationality,
+			})
+		}
+		export.Bookings = append(export.Bookings, item)
+	}
+
+	transactions, err := h.loyaltyRepository.SearchTransactions(ctx, &loyalty_request.StatementRequest{CustomerId: customerId})
+	if err != nil {
+		return nil, err
+	}
+	for _, transaction := range transactions {
+		export.Loyalty = append(export.Loyalty, &customer_response.ExportLoyaltyEntry{
+			Kind:        transaction.Kind,
+			Points:      transaction.Points,
+			BookingId:   transaction.BookingId,
+			Description: transaction.Description,
+			CreatedAt:   transaction.CreatedAt,
+		})
+	}
+
+	logs, err := h.auditRepository.SearchCustomerAuditLog(ctx, customerId)
+	if err != nil {
+		return nil, err
+	}
+	for _, log := range logs {
+		export.AuditEntries = append(export.AuditEntries, &customer_response.ExportAuditEntry{
+			Action:    log.Action,
+			ActorId:   log.ActorId,
+			Detail:    log.Detail,
+			Ip:        log.Ip,
+			CreatedAt: log.CreatedAt,
+		})
+	}
+
+	consents, err := h.customerRepository.ListConsentRecords(ctx, customerId, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, consent := range consents {
+		export.Consents = append(export.Consents, &customer_response.ExportConsent{
+			Purpose:   consent.Purpose,
+			Granted:   consent.Granted,
+			ActorId:   consent.ActorId,
+			Ip:        consent.Ip,
+			CreatedAt: consent.CreatedAt,
+		})
+	}
+
+	return export, nil
+}
@@ -7,8 +7,12 @@ import (
 
 	"github.com/google/uuid"
 	"google.golang.org/protobuf/types/known/timestamppb"
+	"gorm.io/gorm"
 )
 
+// StatusCompleted marks a flight that has landed, its active bookings earn loyalty points
+const StatusCompleted = "Completed"
+
 type Flight struct {
 	Id               uuid.UUID `gorm:"type:uuid;primaryKey"`
 	NameFlight       string    `gorm:"column:flights"`
@@ -19,6 +23,8 @@ type Flight struct {
 	AvailableSlot    int32     `gorm:"column:available_slot"`
 	CreatedAt        time.Time `gorm:"column:created_at"`
 	UpdatedAt        time.Time `gorm:"column:updated_at"`
+	// Deleted flights are kept for the bookings that reference them
+	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;index"`
 }
 
 func (in *Flight) ToResponse() *protobuf.Flight {
@@ -34,5 +40,9 @@ func (in *Flight) ToResponse() *protobuf.Flight {
 		UpdatedAt:     timestamppb.New(in.UpdatedAt),
 	}
 
+	if in.DeletedAt.Valid {
+		res.DeletedAt = timestamppb.New(in.DeletedAt.Time)
+	}
+
 	return res
 }
@@ -2,13 +2,18 @@ package flight_repo
 
 import (
 	"context"
+	"mock-golang/apperror"
 	"mock-golang/database"
+	booking_model "mock-golang/grpc/booking-grpc/model"
 	flight_model "mock-golang/grpc/flight-grpc/model"
 	flight_request "mock-golang/grpc/flight-grpc/request"
+	"mock-golang/pagination"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 //Embeded struct
@@ -17,21 +22,37 @@ type FlightRepository interface {
 	FindById(ctx context.Context, id uuid.UUID) (*flight_model.Flight, error)
 	CreateFlight(ctx context.Context, model *flight_model.Flight) (*flight_model.Flight, error)
 	UpdateFlight(ctx context.Context, model *flight_model.Flight) (*flight_model.Flight, error)
-	SearchFlight(ctx context.Context, req *flight_request.SearchFlightRequest) ([]*flight_model.Flight, error)
+	SearchFlight(ctx context.Context, req *flight_request.SearchFlightRequest) ([]*flight_model.Flight, string, error)
+	CountFlight(ctx context.Context, req *flight_request.SearchFlightRequest) (int64, error)
+	DeleteFlight(ctx context.Context, id uuid.UUID, now time.Time) error
+	// Ping checks the database is reachable, used by the health checks
+	Ping(ctx context.Context) error
+}
+
+// Orders are the fields SearchFlight pages by
+var Orders = pagination.Orders{
+	Default: "departDate",
+	Columns: map[string]string{
+		"departDate": "depart_date",
+		"name":       "flights",
+		"createdAt":  "created_at",
+	},
 }
 
 type dbmanager struct {
 	*gorm.DB
 }
 
+func (m *dbmanager) Ping(ctx context.Context) error {
+	return database.Ping(ctx, m.DB)
+}
+
 func NewDBManager() (FlightRepository, error) {
 	db, err := database.NewGormDB()
 	if err != nil {
 		return nil, err
 	}
 
-	db = db.Debug()
-
 	err = db.AutoMigrate(
 		&flight_model.Flight{},
 	)
@@ -45,32 +66,31 @@ func NewDBManager() (FlightRepository, error) {
 
 func (m *dbmanager) FindById(ctx context.Context, id uuid.UUID) (*flight_model.Flight, error) {
 	res := flight_model.Flight{}
-	if err := m.Where(&flight_model.Flight{Id: id}).First(&res).Error; err != nil {
-		return nil, err
+	if err := m.WithContext(ctx).Where(&flight_model.Flight{Id: id}).First(&res).Error; err != nil {
+		return nil, apperror.FromDB(err)
 	}
 
 	return &res, nil
 }
 
 func (m *dbmanager) CreateFlight(ctx context.Context, model *flight_model.Flight) (*flight_model.Flight, error) {
-	if err := m.Create(model).Error; err != nil {
-		return nil, err
+	if err := m.WithContext(ctx).Create(model).Error; err != nil {
+		return nil, apperror.FromDB(err)
 	}
 
 	return model, nil
 }
 
 func (m *dbmanager) UpdateFlight(ctx context.Context, model *flight_model.Flight) (*flight_model.Flight, error) {
-	if err := m.Where(&flight_model.Flight{Id: model.Id}).Updates(model).Error; err != nil {
-		return nil, err
+	if err := m.WithContext(ctx).Where(&flight_model.Flight{Id: model.Id}).Updates(model).Error; err != nil {
+		return nil, apperror.FromDB(err)
 	}
 
 	return model, nil
 }
 
-func (m *dbmanager) SearchFlight(ctx context.Context, req *flight_request.SearchFlightRequest) ([]*flight_model.Flight, error) {
-	flights := []*flight_model.Flight{}
-
+// searchScope builds the filters shared by SearchFlight and CountFlight
+func (m *dbmanager) searchScope(ctx context.Context, req *flight_request.SearchFlightRequest) *gorm.DB {
 	sbWhere := " 1=1 "
 	params := []interface{}{}
 	if len(strings.TrimSpace(req.Id)) > 0 {
@@ -98,9 +118,76 @@ func (m *dbmanager) SearchFlight(ctx context.Context, req *flight_request.Search
 		params = append(params, req.ToDate)
 	}
 
-	if err := m.Where(sbWhere, params...).Find(&flights).Error; err != nil {
-		return nil, err
+	db := m.WithContext(ctx).Model(&flight_model.Flight{})
+	if req.IncludeDeleted {
+		db = db.Unscoped()
 	}
 
-	return flights, nil
+	return db.Where(sbWhere, params...)
+}
+
+// SearchFlight returns a page of flights and the token of the next one
+func (m *dbmanager) SearchFlight(ctx context.Context, req *flight_request.SearchFlightRequest) ([]*flight_model.Flight, string, error) {
+	flights := []*flight_model.Flight{}
+
+	if err := req.Page.Apply(m.searchScope(ctx, req), "id").Find(&flights).Error; err != nil {
+		return nil, "", apperror.FromDB(err)
+	}
+
+	nextPageToken := ""
+	if req.Page.HasNext(len(flights)) {
+		flights = flights[:req.Page.Size]
+		last := flights[len(flights)-1]
+		nextPageToken = req.Page.NextToken(sortKey(req.Page.Order.Field, last), last.Id.String())
+	}
+
+	return flights, nextPageToken, nil
+}
+
+// CountFlight counts the matches of SearchFlight across all pages
+func (m *dbmanager) CountFlight(ctx context.Context, req *flight_request.SearchFlightRequest) (int64, error) {
+	var count int64
+
+	if err := m.searchScope(ctx, req).Count(&count).Error; err != nil {
+		return 0, apperror.FromDB(err)
+	}
+
+	return count, nil
+}
+
+// sortKey is the value of the order by field the next page starts after
+func sortKey(field string, flight *flight_model.Flight) interface{} {
+	switch field {
+	case "name":
+		return flight.NameFlight
+	case "createdAt":
+		return flight.CreatedAt
+	default:
+		return flight.DepartDate
+	}
+}
+
+// DeleteFlight soft deletes the flight when nobody is booked on it anymore, bookings still load it.
+// The flight row is locked first, a booking being made holds a share lock on it and is waited for.
+func (m *dbmanager) DeleteFlight(ctx context.Context, id uuid.UUID, now time.Time) error {
+	err := m.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		flight := flight_model.Flight{}
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&flight).Error; err != nil {
+			return err
+		}
+
+		var upcoming int64
+		err := tx.Model(&booking_model.Booking{}).Scopes(booking_model.Upcoming(now)).
+			Where("bookings.flight_id = ?", id.String()).Count(&upcoming).Error
+		if err != nil {
+			return err
+		}
+		if upcoming > 0 {
+			return apperror.FailedPrecondition("flight %s has %d active bookings, cancel them first", flight.NameFlight, upcoming)
+		}
+
+		return tx.Delete(&flight_model.Flight{Id: id}).Error
+	})
+
+	return apperror.FromDB(err)
 }
@@ -3,6 +3,8 @@ package flight_request
 import (
 	"time"
 
+	"mock-golang/pagination"
+
 	"github.com/google/uuid"
 )
 
@@ -17,4 +19,8 @@ type SearchFlightRequest struct {
 	To       string
 	FromDate time.Time
 	ToDate   time.Time
+	// IncludeDeleted also returns soft deleted flights
+	IncludeDeleted bool
+	// Page is nil to return every match
+	Page *pagination.Page
 }
@@ -2,43 +2,72 @@ package flight_handler
 
 import (
 	"context"
-	"database/sql"
+	"errors"
+	"fmt"
+	"mock-golang/apperror"
+	audit_model "mock-golang/grpc/audit-grpc/model"
+	audit_repo "mock-golang/grpc/audit-grpc/repository"
+	auth_handler "mock-golang/grpc/auth-grpc/service"
 	flight_model "mock-golang/grpc/flight-grpc/model"
 	flight_repo "mock-golang/grpc/flight-grpc/repository"
 	flight_request "mock-golang/grpc/flight-grpc/request"
+	loyalty_handler "mock-golang/grpc/loyalty-grpc/service"
+	"mock-golang/pagination"
 	"mock-golang/protobuf"
+	"mock-golang/rbac"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
 )
 
 type FlightHandler struct {
 	protobuf.UnimplementedRPCFlightServer
 	flightRepository flight_repo.FlightRepository
+	auditRepository  audit_repo.AuditRepository
+	loyalty          *loyalty_handler.LoyaltyHandler
 	mu               *sync.Mutex
 }
 
-func NewFlightHandler(flightRepository flight_repo.FlightRepository) (*FlightHandler, error) {
+func NewFlightHandler(
+	flightRepository flight_repo.FlightRepository,
+	auditRepository audit_repo.AuditRepository,
+	loyalty *loyalty_handler.LoyaltyHandler) (*FlightHandler, error) {
 	return &FlightHandler{
 		flightRepository: flightRepository,
+		auditRepository:  auditRepository,
+		loyalty:          loyalty,
 		mu:               &sync.Mutex{},
 	}, nil
 }
 
 func (h *FlightHandler) FindById(ctx context.Context, in *protobuf.FlightParamId) (*protobuf.Flight, error) {
-	flight, err := h.flightRepository.FindById(ctx, uuid.MustParse(in.Id))
+	if err := rbac.Require(ctx, rbac.PermFlightRead); err != nil {
+		return nil, err
+	}
+
+	flightId, err := apperror.ParseID("id", in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	flight, err := h.flightRepository.FindById(ctx, flightId)
 
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, apperror.ToStatus(err)
 	}
 
 	return flight.ToResponse(), nil
 }
 
 func (h *FlightHandler) CreateFlight(ctx context.Context, in *protobuf.Flight) (*protobuf.Flight, error) {
+	if err := rbac.Require(ctx, rbac.PermFlightWrite); err != nil {
+		return nil, err
+	}
+
 	req := &flight_model.Flight{
 		Id:               uuid.New(),
 		NameFlight:       in.Name,
@@ -53,21 +82,27 @@ func (h *FlightHandler) CreateFlight(ctx context.Context, in *protobuf.Flight) (
 	flight, err := h.flightRepository.CreateFlight(ctx, req)
 
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, apperror.ToStatus(err)
 	}
 
 	return flight.ToResponse(), nil
 }
 
 func (h *FlightHandler) UpdateFlight(ctx context.Context, in *protobuf.Flight) (*protobuf.Flight, error) {
-	flightIn, err := h.flightRepository.FindById(ctx, uuid.MustParse(in.Id))
+	if err := rbac.Require(ctx, rbac.PermFlightWrite); err != nil {
+		return nil, err
+	}
+
+	flightId, err := apperror.ParseID("id", in.Id)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, status.Error(codes.NotFound, err.Error())
-		}
 		return nil, err
 	}
 
+	flightIn, err := h.flightRepository.FindById(ctx, flightId)
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
 	if in.Name != "" {
 		flightIn.NameFlight = in.Name
 	}
@@ -97,39 +132,114 @@ func (h *FlightHandler) UpdateFlight(ctx context.Context, in *protobuf.Flight) (
 	flight, err := h.flightRepository.UpdateFlight(ctx, flightIn)
 
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, apperror.ToStatus(err)
+	}
+
+	// Accrual skips bookings already credited, saving a completed flight again retries failures
+	if flight.Status == flight_model.StatusCompleted {
+		if err := h.loyalty.AccrueFlight(ctx, flight.Id.String()); err != nil {
+			return nil, apperror.ToStatus(err)
+		}
 	}
 
 	return flight.ToResponse(), nil
 }
 
 func (h *FlightHandler) SearchFlight(ctx context.Context, in *protobuf.SearchFlightRequest) (*protobuf.SearchFlightResponse, error) {
-	flights, err := h.flightRepository.SearchFlight(ctx, &flight_request.SearchFlightRequest{
-		Id:   in.Id,
-		Name: in.Name,
-		From: in.From,
-		To:   in.To,
-		// FromDate: in.FromDate.AsTime(),
-		// ToDate:   in.ToDate.AsTime(),
-	})
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, status.Error(codes.NotFound, err.Error())
-		}
+	if err := rbac.Require(ctx, rbac.PermFlightRead); err != nil {
 		return nil, err
 	}
+	if in.IncludeDeleted {
+		if err := rbac.Require(ctx, rbac.PermDeletedRead); err != nil {
+			return nil, err
+		}
+	}
+
+	page, err := pagination.NewPage(in.PageSize, in.PageToken, in.OrderBy, flight_repo.Orders)
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	req := &flight_request.SearchFlightRequest{
+		Id:             in.Id,
+		Name:           in.Name,
+		From:           in.From,
+		To:             in.To,
+		IncludeDeleted: in.IncludeDeleted,
+		Page:           page,
+	}
+
+	// A nil timestamp would otherwise become the Unix epoch
+	if in.FromDate != nil {
+		req.FromDate = in.FromDate.AsTime()
+	}
+	if in.ToDate != nil {
+		req.ToDate = in.ToDate.AsTime()
+	}
+
+	flights, nextPageToken, err := h.flightRepository.SearchFlight(ctx, req)
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	total, err := h.flightRepository.CountFlight(ctx, req)
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
 
 	pRes := &protobuf.SearchFlightResponse{
-		Flight: []*protobuf.Flight{},
+		Flight:        []*protobuf.Flight{},
+		NextPageToken: nextPageToken,
+		TotalSize:     total,
 	}
 
 	for _, flight := range flights {
 		pRes.Flight = append(pRes.Flight, flight.ToResponse())
 	}
 
+	return pRes, nil
+}
+
+// DeleteFlight soft deletes a flight nobody is booked on anymore, past bookings keep loading it
+func (h *FlightHandler) DeleteFlight(ctx context.Context, in *protobuf.FlightParamId) (*protobuf.Flight, error) {
+	if err := rbac.Require(ctx, rbac.PermFlightDelete); err != nil {
+		return nil, err
+	}
+
+	flightId, err := apperror.ParseID("id", in.Id)
 	if err != nil {
 		return nil, err
 	}
 
-	return pRes, nil
+	flight, err := h.flightRepository.FindById(ctx, flightId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "flight %s not found", flightId)
+		}
+		return nil, apperror.ToStatus(err)
+	}
+
+	// The upcoming bookings are counted in the delete transaction, under a lock of the flight
+	now := time.Now()
+	if err := h.flightRepository.DeleteFlight(ctx, flightId, now); err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	_, err = h.auditRepository.CreateAuditLog(ctx, &audit_model.AuditLog{
+		Id:         uuid.New(),
+		ActorId:    rbac.FromContext(ctx).CustomerId,
+		Action:     audit_model.ActionFlightDeleted,
+		TargetType: audit_model.TargetFlight,
+		TargetId:   flightId.String(),
+		Detail:     fmt.Sprintf("deleted flight %s departing %s", flight.NameFlight, flight.DepartDate.Format(time.RFC3339)),
+		Ip:         auth_handler.ClientIp(ctx),
+		CreatedAt:  now,
+	})
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	flight.DeletedAt = gorm.DeletedAt{Time: now, Valid: true}
+
+	return flight.ToResponse(), nil
 }
@@ -0,0 +1,97 @@
+package loyalty_model
+
+import (
+	"errors"
+	"time"
+
+	"mock-golang/apperror"
+	"mock-golang/protobuf"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	KindAccrual    = "accrual"
+	KindReversal   = "reversal"
+	KindRedemption = "redemption"
+)
+
+var (
+	ErrInsufficientPoints = errors.New("balance is lower than the points to redeem")
+	ErrRequestReused      = apperror.AlreadyExists("requestId", "request id was already used for another redemption")
+)
+
+// LoyaltyAccount keeps the running balance, the row is locked while a transaction is posted
+type LoyaltyAccount struct {
+	CustomerId       string    `gorm:"column:customer_id;primaryKey"`
+	Balance          int64     `gorm:"column:balance"`
+	QualifyingPoints int64     `gorm:"column:qualifying_points"`
+	Tier             string    `gorm:"column:tier"`
+	TierUpdatedAt    time.Time `gorm:"column:tier_updated_at"`
+	CreatedAt        time.Time `gorm:"column:created_at"`
+	UpdatedAt        time.Time `gorm:"column:updated_at"`
+}
+
+// LoyaltyTransaction is an append only ledger entry, Points is negative for reversals and redemptions
+type LoyaltyTransaction struct {
+	Id          uuid.UUID `gorm:"type:uuid;primaryKey"`
+	CustomerId  string    `gorm:"column:customer_id;index"`
+	BookingId   string    `gorm:"column:booking_id;index"`
+	Kind        string    `gorm:"column:kind"`
+	Points      int64     `gorm:"column:points"`
+	Description string    `gorm:"column:description"`
+	// RequestId is the client's idempotency key of a redemption, unique per customer
+	RequestId string    `gorm:"column:request_id"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
+
+// Admit tells whether model is posted on the account. posted is the entry already holding the
+// request id of model, nil for a new request. A replay fills model with posted and is answered
+// before the balance is checked, the redemption being retried may have spent it.
+func (in *LoyaltyAccount) Admit(model *LoyaltyTransaction, posted *LoyaltyTransaction) (bool, error) {
+	if posted != nil {
+		if posted.Kind != model.Kind || posted.Points != model.Points {
+			return false, ErrRequestReused
+		}
+		*model = *posted
+		return false, nil
+	}
+
+	// Reversals may leave a negative balance when the points were already spent
+	if model.Kind == KindRedemption && in.Balance+model.Points < 0 {
+		return false, ErrInsufficientPoints
+	}
+
+	return true, nil
+}
+
+func (in *LoyaltyAccount) ToResponse() *protobuf.LoyaltyBalance {
+	res := &protobuf.LoyaltyBalance{
+		CustomerId:       in.CustomerId,
+		Balance:          in.Balance,
+		Tier:             in.Tier,
+		QualifyingPoints: in.QualifyingPoints,
+	}
+
+	if next, ok := NextTier(in.QualifyingPoints); ok {
+		res.NextTier = next.Name
+		res.PointsToNextTier = next.MinPoints - in.QualifyingPoints
+	}
+
+	return res
+}
+
+func (in *LoyaltyTransaction) ToResponse() *protobuf.LoyaltyTransaction {
+	res := &protobuf.LoyaltyTransaction{
+		Id:          in.Id.String(),
+		CustomerId:  in.CustomerId,
+		BookingId:   in.BookingId,
+		Kind:        in.Kind,
+		Points:      in.Points,
+		Description: in.Description,
+		CreatedAt:   timestamppb.New(in.CreatedAt),
+	}
+
+	return res
+}
@@ -0,0 +1,42 @@
+package loyalty_model
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdmitReplaysDrainedRedemption(t *testing.T) {
+	redemption := &LoyaltyTransaction{CustomerId: "c1", Kind: KindRedemption, Points: -500, RequestId: "r1", Description: "upgrade"}
+	account := &LoyaltyAccount{CustomerId: "c1", Balance: 500}
+
+	admitted, err := account.Admit(redemption, nil)
+	assert.Nil(t, err)
+	assert.True(t, admitted)
+	account.Balance += redemption.Points
+
+	// The retry finds the balance spent, it must still get the posted redemption
+	retry := &LoyaltyTransaction{CustomerId: "c1", Kind: KindRedemption, Points: -500, RequestId: "r1"}
+	admitted, err = account.Admit(retry, redemption)
+	assert.Nil(t, err)
+	assert.False(t, admitted)
+	assert.Equal(t, "upgrade", retry.Description)
+	assert.Equal(t, int64(0), account.Balance)
+}
+
+func TestAdmitRefusesOverdraftAndReusedRequest(t *testing.T) {
+	account := &LoyaltyAccount{CustomerId: "c1", Balance: 100}
+
+	_, err := account.Admit(&LoyaltyTransaction{Kind: KindRedemption, Points: -500, RequestId: "r2"}, nil)
+	assert.True(t, errors.Is(err, ErrInsufficientPoints))
+
+	posted := &LoyaltyTransaction{Kind: KindRedemption, Points: -50, RequestId: "r1"}
+	_, err = account.Admit(&LoyaltyTransaction{Kind: KindRedemption, Points: -80, RequestId: "r1"}, posted)
+	assert.True(t, errors.Is(err, ErrRequestReused))
+
+	// Reversals may overdraw
+	admitted, err := account.Admit(&LoyaltyTransaction{Kind: KindReversal, Points: -500}, nil)
+	assert.Nil(t, err)
+	assert.True(t, admitted)
+}
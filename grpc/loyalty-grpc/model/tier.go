@@ -0,0 +1,43 @@
+package loyalty_model
+
+import "time"
+
+// QualifyingSince starts the rolling 12 months of accrued points the tier is computed from
+func QualifyingSince(now time.Time) time.Time {
+	return now.AddDate(0, -12, 0)
+}
+
+type Tier struct {
+	Name      string
+	MinPoints int64
+}
+
+// Tiers is ordered by MinPoints, the first one is the entry tier
+var Tiers = []Tier{
+	{Name: "Silver", MinPoints: 0},
+	{Name: "Gold", MinPoints: 10000},
+	{Name: "Platinum", MinPoints: 25000},
+}
+
+// TierFor returns the highest tier the qualifying points reach
+func TierFor(points int64) Tier {
+	tier := Tiers[0]
+	for _, t := range Tiers {
+		if points >= t.MinPoints {
+			tier = t
+		}
+	}
+
+	return tier
+}
+
+// NextTier returns the tier above the one the points reach, false at the top
+func NextTier(points int64) (Tier, bool) {
+	for _, t := range Tiers {
+		if points < t.MinPoints {
+			return t, true
+		}
+	}
+
+	return Tier{}, false
+}
@@ -0,0 +1,29 @@
+package loyalty_model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTierFor(t *testing.T) {
+	assert.Equal(t, "Silver", TierFor(0).Name)
+	assert.Equal(t, "Silver", TierFor(-500).Name)
+	assert.Equal(t, "Silver", TierFor(9999).Name)
+	assert.Equal(t, "Gold", TierFor(10000).Name)
+	assert.Equal(t, "Platinum", TierFor(25000).Name)
+	assert.Equal(t, "Platinum", TierFor(1000000).Name)
+}
+
+func TestNextTier(t *testing.T) {
+	next, ok := NextTier(2500)
+	assert.True(t, ok)
+	assert.Equal(t, "Gold", next.Name)
+
+	next, ok = NextTier(10000)
+	assert.True(t, ok)
+	assert.Equal(t, "Platinum", next.Name)
+
+	_, ok = NextTier(25000)
+	assert.False(t, ok)
+}
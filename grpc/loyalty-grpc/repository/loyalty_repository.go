@@ -0,0 +1,243 @@
+package loyalty_repo
+
+import (
+	"context"
+	"errors"
+	"mock-golang/apperror"
+	"mock-golang/database"
+	loyalty_model "mock-golang/grpc/loyalty-grpc/model"
+	loyalty_request "mock-golang/grpc/loyalty-grpc/request"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInsufficientPoints = loyalty_model.ErrInsufficientPoints
+	ErrAlreadyPosted      = errors.New("booking already has this kind of loyalty transaction")
+	ErrRequestReused      = loyalty_model.ErrRequestReused
+)
+
+//Embeded struct
+
+type LoyaltyRepository interface {
+	FindAccount(ctx context.Context, customerId string) (*loyalty_model.LoyaltyAccount, error)
+	FindBookingTransaction(ctx context.Context, bookingId string, kind string) (*loyalty_model.LoyaltyTransaction, error)
+	SearchTransactions(ctx context.Context, req *loyalty_request.StatementRequest) ([]*loyalty_model.LoyaltyTransaction, error)
+	PostTransaction(ctx context.Context, model *loyalty_model.LoyaltyTransaction) (*loyalty_model.LoyaltyAccount, error)
+	RecalculateTier(ctx context.Context, customerId string) (*loyalty_model.LoyaltyAccount, error)
+}
+
+type dbmanager struct {
+	*gorm.DB
+}
+
+func NewDBManager() (LoyaltyRepository, error) {
+	db, err := database.NewGormDB()
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.AutoMigrate(
+		&loyalty_model.LoyaltyAccount{},
+		&loyalty_model.LoyaltyTransaction{},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// A booking accrues and is reversed at most once, whatever retries happen
+	err = db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS loyalty_transactions_booking_kind_key " +
+		"ON loyalty_transactions (booking_id, kind) WHERE booking_id <> ''").Error
+	if err != nil {
+		return nil, err
+	}
+
+	// A redemption retried with the same request id is only spent once
+	err = db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS loyalty_transactions_request_key " +
+		"ON loyalty_transactions (customer_id, request_id) WHERE request_id <> ''").Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &dbmanager{db}, nil
+}
+
+func (m *dbmanager) FindAccount(ctx context.Context, customerId string) (*loyalty_model.LoyaltyAccount, error) {
+	res := loyalty_model.LoyaltyAccount{}
+	if err := m.WithContext(ctx).Where(&loyalty_model.LoyaltyAccount{CustomerId: customerId}).First(&res).Error; err != nil {
+		return nil, apperror.FromDB(err)
+	}
+
+	return &res, nil
+}
+
+func (m *dbmanager) FindBookingTransaction(ctx context.Context, bookingId string, kind string) (*loyalty_model.LoyaltyTransaction, error) {
+	res := loyalty_model.LoyaltyTransaction{}
+	if err := m.WithContext(ctx).Where(&loyalty_model.LoyaltyTransaction{BookingId: bookingId, Kind: kind}).First(&res).Error; err != nil {
+		return nil, apperror.FromDB(err)
+	}
+
+	return &res, nil
+}
+
+func (m *dbmanager) SearchTransactions(ctx context.Context, req *loyalty_request.StatementRequest) ([]*loyalty_model.LoyaltyTransaction, error) {
+	transactions := []*loyalty_model.LoyaltyTransaction{}
+
+	sbWhere := " customer_id = ? "
+	params := []interface{}{req.CustomerId}
+	if !req.FromDate.IsZero() {
+		sbWhere += " AND created_at >= ? "
+		params = append(params, req.FromDate)
+	}
+	if !req.ToDate.IsZero() {
+		sbWhere += " AND created_at <= ? "
+		params = append(params, req.ToDate)
+	}
+
+	if err := m.WithContext(ctx).Where(sbWhere, params...).Order("created_at DESC").Limit(req.Limit).Find(&transactions).Error; err != nil {
+		return nil, apperror.FromDB(err)
+	}
+
+	return transactions, nil
+}
+
+// PostTransaction appends the entry and updates balance and tier in one transaction. When the
+// request id of model was already posted, model is filled with that entry and nothing changes.
+func (m *dbmanager) PostTransaction(ctx context.Context, model *loyalty_model.LoyaltyTransaction) (*loyalty_model.LoyaltyAccount, error) {
+	account := &loyalty_model.LoyaltyAccount{}
+
+	err := m.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		locked, err := lockAccount(tx, model.CustomerId)
+		if err != nil {
+			return err
+		}
+		account = locked
+
+		// Looked up under the account lock, a retry waits for the request it repeats
+		var posted *loyalty_model.LoyaltyTransaction
+		if model.RequestId != "" {
+			found := loyalty_model.LoyaltyTransaction{}
+			err := tx.Where(&loyalty_model.LoyaltyTransaction{CustomerId: model.CustomerId, RequestId: model.RequestId}).
+				Limit(1).Find(&found).Error
+			if err != nil {
+				return err
+			}
+			if found.RequestId != "" {
+				posted = &found
+			}
+		}
+
+		admitted, err := account.Admit(model, posted)
+		if err != nil || !admitted {
+			return err
+		}
+
+		if model.BookingId != "" {
+			var count int64
+			err := tx.Model(&loyalty_model.LoyaltyTransaction{}).
+				Where(&loyalty_model.LoyaltyTransaction{BookingId: model.BookingId, Kind: model.Kind}).
+				Count(&count).Error
+			if err != nil {
+				return err
+			}
+			if count > 0 {
+				return ErrAlreadyPosted
+			}
+		}
+
+		if err := tx.Create(model).Error; err != nil {
+			return err
+		}
+
+		account.Balance += model.Points
+
+		return updateTier(tx, account, model.CreatedAt)
+	})
+	if err != nil {
+		return nil, apperror.FromDB(err)
+	}
+
+	return account, nil
+}
+
+// RecalculateTier lets accruals older than 12 months drop out of the tier
+func (m *dbmanager) RecalculateTier(ctx context.Context, customerId string) (*loyalty_model.LoyaltyAccount, error) {
+	account := &loyalty_model.LoyaltyAccount{}
+
+	err := m.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		locked, err := lockAccount(tx, customerId)
+		if err != nil {
+			return err
+		}
+		account = locked
+
+		return updateTier(tx, account, time.Now())
+	})
+	if err != nil {
+		return nil, apperror.FromDB(err)
+	}
+
+	return account, nil
+}
+
+// lockAccount creates the account on first use and locks its row until the transaction ends
+func lockAccount(tx *gorm.DB, customerId string) (*loyalty_model.LoyaltyAccount, error) {
+	now := time.Now()
+	err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&loyalty_model.LoyaltyAccount{
+		CustomerId:    customerId,
+		Tier:          loyalty_model.Tiers[0].Name,
+		TierUpdatedAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}).Error
+	if err != nil {
+		return nil, apperror.FromDB(err)
+	}
+
+	account := &loyalty_model.LoyaltyAccount{}
+	err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where(&loyalty_model.LoyaltyAccount{CustomerId: customerId}).
+		First(account).Error
+	if err != nil {
+		return nil, apperror.FromDB(err)
+	}
+
+	return account, nil
+}
+
+// updateTier sums the accruals of the last 12 months whose booking was not reversed and saves the account
+func updateTier(tx *gorm.DB, account *loyalty_model.LoyaltyAccount, now time.Time) error {
+	var qualifying int64
+	err := tx.Model(&loyalty_model.LoyaltyTransaction{}).
+		Select("COALESCE(SUM(points), 0)").
+		Where("customer_id = ? AND kind = ? AND created_at >= ?",
+			account.CustomerId, loyalty_model.KindAccrual, loyalty_model.QualifyingSince(now)).
+		Where("NOT EXISTS (SELECT 1 FROM loyalty_transactions r WHERE r.booking_id = loyalty_transactions.booking_id AND r.kind = ?)",
+			loyalty_model.KindReversal).
+		Scan(&qualifying).Error
+	if err != nil {
+		return apperror.FromDB(err)
+	}
+
+	account.QualifyingPoints = qualifying
+	account.Tier = loyalty_model.TierFor(qualifying).Name
+	account.TierUpdatedAt = now
+	account.UpdatedAt = now
+
+	err = tx.Model(&loyalty_model.LoyaltyAccount{}).Where("customer_id = ?", account.CustomerId).
+		Updates(map[string]interface{}{
+			"balance":           account.Balance,
+			"qualifying_points": account.QualifyingPoints,
+			"tier":              account.Tier,
+			"tier_updated_at":   account.TierUpdatedAt,
+			"updated_at":        account.UpdatedAt,
+		}).Error
+	if err != nil {
+		return apperror.FromDB(err)
+	}
+
+	return nil
+}
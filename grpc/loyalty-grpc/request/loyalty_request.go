@@ -0,0 +1,10 @@
+package loyalty_request
+
+import "time"
+
+type StatementRequest struct {
+	CustomerId string
+	FromDate   time.Time
+	ToDate     time.Time
+	Limit      int
+}
@@ -0,0 +1,295 @@
+package loyalty_handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mock-golang/apperror"
+	booking_model "mock-golang/grpc/booking-grpc/model"
+	booking_repo "mock-golang/grpc/booking-grpc/repository"
+	booking_request "mock-golang/grpc/booking-grpc/request"
+	customer_repo "mock-golang/grpc/customer-grpc/repository"
+	loyalty_model "mock-golang/grpc/loyalty-grpc/model"
+	loyalty_repo "mock-golang/grpc/loyalty-grpc/repository"
+	loyalty_request "mock-golang/grpc/loyalty-grpc/request"
+	"mock-golang/protobuf"
+	"mock-golang/rbac"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultPointsPerSeat  = 100
+	defaultStatementLimit = 50
+	maxStatementLimit     = 200
+	// Tiers are recalculated on read at most once a day, accruals recalculate right away
+	tierRefreshInterval = 24 * time.Hour
+)
+
+type LoyaltyHandler struct {
+	protobuf.UnimplementedRPCLoyaltyServer
+	loyaltyRepository  loyalty_repo.LoyaltyRepository
+	bookingRepository  booking_repo.BookingRepository
+	customerRepository customer_repo.CustomerRepository
+	mu                 *sync.Mutex
+}
+
+func NewLoyaltyHandler(
+	loyaltyRepository loyalty_repo.LoyaltyRepository,
+	bookingRepository booking_repo.BookingRepository,
+	customerRepository customer_repo.CustomerRepository) (*LoyaltyHandler, error) {
+	return &LoyaltyHandler{
+		loyaltyRepository:  loyaltyRepository,
+		bookingRepository:  bookingRepository,
+		customerRepository: customerRepository,
+		mu:                 &sync.Mutex{},
+	}, nil
+}
+
+func (h *LoyaltyHandler) GetBalance(ctx context.Context, in *protobuf.LoyaltyParamId) (*protobuf.LoyaltyBalance, error) {
+	customerId, err := parseCustomerId(in.CustomerId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := rbac.RequireOwnOrAny(ctx, customerId, rbac.PermLoyaltyReadOwn, rbac.PermLoyaltyReadAny); err != nil {
+		return nil, err
+	}
+
+	account, err := h.findAccount(ctx, customerId)
+	if err != nil {
+		return nil, err
+	}
+
+	return account.ToResponse(), nil
+}
+
+func (h *LoyaltyHandler) GetStatement(ctx context.Context, in *protobuf.StatementRequest) (*protobuf.StatementResponse, error) {
+	customerId, err := parseCustomerId(in.CustomerId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := rbac.RequireOwnOrAny(ctx, customerId, rbac.PermLoyaltyReadOwn, rbac.PermLoyaltyReadAny); err != nil {
+		return nil, err
+	}
+
+	account, err := h.findAccount(ctx, customerId)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := int(in.Limit)
+	if limit <= 0 {
+		limit = defaultStatementLimit
+	}
+	if limit > maxStatementLimit {
+		limit = maxStatementLimit
+	}
+
+	req := &loyalty_request.StatementRequest{
+		CustomerId: customerId,
+		Limit:      limit,
+	}
+	if in.FromDate != nil {
+		req.FromDate = in.FromDate.AsTime()
+	}
+	if in.ToDate != nil {
+		req.ToDate = in.ToDate.AsTime()
+	}
+
+	transactions, err := h.loyaltyRepository.SearchTransactions(ctx, req)
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	pRes := &protobuf.StatementResponse{
+		Balance:     account.ToResponse(),
+		Transaction: []*protobuf.LoyaltyTransaction{},
+	}
+
+	for _, transaction := range transactions {
+		pRes.Transaction = append(pRes.Transaction, transaction.ToResponse())
+	}
+
+	return pRes, nil
+}
+
+func (h *LoyaltyHandler) RedeemPoints(ctx context.Context, in *protobuf.RedeemPointsRequest) (*protobuf.RedeemPointsResponse, error) {
+	customerId, err := parseCustomerId(in.CustomerId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := rbac.RequireOwnOrAny(ctx, customerId, rbac.PermLoyaltyRedeemOwn, rbac.PermLoyaltyRedeemAny); err != nil {
+		return nil, err
+	}
+
+	if in.Points <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "points to redeem must be positive")
+	}
+
+	requestId := strings.TrimSpace(in.RequestId)
+	if requestId == "" {
+		return nil, status.Error(codes.InvalidArgument, "request id is required, a retry must send the same one")
+	}
+
+	description := strings.TrimSpace(in.Description)
+	if description == "" {
+		description = "Points redeemed"
+	}
+
+	transaction := &loyalty_model.LoyaltyTransaction{
+		Id:          uuid.New(),
+		CustomerId:  customerId,
+		Kind:        loyalty_model.KindRedemption,
+		Points:      -in.Points,
+		Description: description,
+		RequestId:   requestId,
+		CreatedAt:   time.Now(),
+	}
+
+	account, err := h.post(ctx, transaction)
+	if err != nil {
+		if errors.Is(err, loyalty_repo.ErrInsufficientPoints) {
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+		return nil, apperror.ToStatus(err)
+	}
+
+	out := &protobuf.RedeemPointsResponse{
+		Transaction: transaction.ToResponse(),
+		Balance:     account.ToResponse(),
+	}
+
+	return out, nil
+}
+
+// AccrueFlight credits every active booking of a completed flight. Bookings already
+// credited are skipped, so it is safe to call again after a partial failure.
+func (h *LoyaltyHandler) AccrueFlight(ctx context.Context, flightId string) error {
+	bookings, _, err := h.bookingRepository.SearchBooking(ctx, &booking_request.SearchBookingRequest{
+		FlightId: flightId,
+		Status:   booking_model.StatusActive,
+	})
+	if err != nil {
+		return err
+	}
+
+	pointsPerSeat := viper.GetInt64("loyalty.points_per_seat")
+	if pointsPerSeat <= 0 {
+		pointsPerSeat = defaultPointsPerSeat
+	}
+
+	var firstErr error
+	for _, booking := range bookings {
+		// Guests earn nothing until they claim their account
+		if booking.Customer == nil || booking.Customer.Role == int32(rbac.RoleGuest) || booking.BookedSlot <= 0 {
+			continue
+		}
+
+		_, err := h.post(ctx, &loyalty_model.LoyaltyTransaction{
+			Id:          uuid.New(),
+			CustomerId:  booking.CustomerId,
+			BookingId:   booking.Id.String(),
+			Kind:        loyalty_model.KindAccrual,
+			Points:      pointsPerSeat * int64(booking.BookedSlot),
+			Description: fmt.Sprintf("Flight %s completed, %d seat(s)", booking.Code, booking.BookedSlot),
+			CreatedAt:   time.Now(),
+		})
+		if err != nil && !errors.Is(err, loyalty_repo.ErrAlreadyPosted) && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+// ReverseBooking takes back the points a cancelled booking earned, if any
+func (h *LoyaltyHandler) ReverseBooking(ctx context.Context, booking *booking_model.Booking) error {
+	accrual, err := h.loyaltyRepository.FindBookingTransaction(ctx, booking.Id.String(), loyalty_model.KindAccrual)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	_, err = h.post(ctx, &loyalty_model.LoyaltyTransaction{
+		Id:          uuid.New(),
+		CustomerId:  accrual.CustomerId,
+		BookingId:   accrual.BookingId,
+		Kind:        loyalty_model.KindReversal,
+		Points:      -accrual.Points,
+		Description: fmt.Sprintf("Booking %s cancelled", booking.Code),
+		CreatedAt:   time.Now(),
+	})
+	if err != nil && !errors.Is(err, loyalty_repo.ErrAlreadyPosted) {
+		return err
+	}
+
+	return nil
+}
+
+// findAccount returns an empty entry tier account for customers without transactions
+func (h *LoyaltyHandler) findAccount(ctx context.Context, customerId string) (*loyalty_model.LoyaltyAccount, error) {
+	account, err := h.loyaltyRepository.FindAccount(ctx, customerId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &loyalty_model.LoyaltyAccount{
+				CustomerId: customerId,
+				Tier:       loyalty_model.Tiers[0].Name,
+			}, nil
+		}
+		return nil, apperror.ToStatus(err)
+	}
+
+	if time.Since(account.TierUpdatedAt) > tierRefreshInterval {
+		account, err = h.loyaltyRepository.RecalculateTier(ctx, customerId)
+		if err != nil {
+			return nil, apperror.ToStatus(err)
+		}
+
+		if err := h.syncTier(ctx, account); err != nil {
+			return nil, apperror.ToStatus(err)
+		}
+	}
+
+	return account, nil
+}
+
+// post appends the transaction, then copies the account tier to the customer
+func (h *LoyaltyHandler) post(ctx context.Context, transaction *loyalty_model.LoyaltyTransaction) (*loyalty_model.LoyaltyAccount, error) {
+	account, err := h.loyaltyRepository.PostTransaction(ctx, transaction)
+	if err != nil {
+		return nil, err
+	}
+
+	return account, h.syncTier(ctx, account)
+}
+
+// syncTier writes the tier through the customer repository, the customer responses show it
+func (h *LoyaltyHandler) syncTier(ctx context.Context, account *loyalty_model.LoyaltyAccount) error {
+	customerId, err := uuid.Parse(account.CustomerId)
+	if err != nil {
+		return err
+	}
+
+	return h.customerRepository.SetLoyaltyTier(ctx, customerId, account.Tier)
+}
+
+func parseCustomerId(id string) (string, error) {
+	customerId, err := apperror.ParseID("customerId", id)
+	if err != nil {
+		return "", err
+	}
+
+	return customerId.String(), nil
+}
@@ -1,28 +1,52 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"mock-golang/clientip"
+	"mock-golang/database"
+	"mock-golang/encryption"
+	audit_repo "mock-golang/grpc/audit-grpc/repository"
+	auth_repo "mock-golang/grpc/auth-grpc/repository"
+	auth_handler "mock-golang/grpc/auth-grpc/service"
 	booking_repo "mock-golang/grpc/booking-grpc/repository"
 	booking_handler "mock-golang/grpc/booking-grpc/service"
 	customer_repo "mock-golang/grpc/customer-grpc/repository"
 	customer_handler "mock-golang/grpc/customer-grpc/service"
 	flight_repo "mock-golang/grpc/flight-grpc/repository"
 	flight_handler "mock-golang/grpc/flight-grpc/service"
+	loyalty_repo "mock-golang/grpc/loyalty-grpc/repository"
+	loyalty_handler "mock-golang/grpc/loyalty-grpc/service"
+	organization_repo "mock-golang/grpc/organization-grpc/repository"
+	organization_handler "mock-golang/grpc/organization-grpc/service"
+	"mock-golang/health"
 	"mock-golang/helper"
 	"mock-golang/intercepter"
+	"mock-golang/metrics"
+	"mock-golang/notification"
 	"mock-golang/protobuf"
+	"mock-golang/rbac"
+	"mock-golang/tracing"
 	"net"
+	"net/http"
+	"os"
+	"time"
 
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	grpc_health "google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 )
 
 var (
-	configFile = flag.String("config-file", "../helper/config.yml", "Location of config file")
-	port       = flag.Int("port", 9112, "Port grpc")
+	configFile  = flag.String("config-file", "../helper/config.yml", "Location of config file")
+	port        = flag.Int("port", 9112, "Port grpc")
+	metricsPort = flag.Int("metrics-port", 9113, "Port of the http /metrics endpoint")
 )
 
 func init() {
@@ -35,6 +59,26 @@ func main() {
 		panic(err)
 	}
 
+	err = encryption.Init()
+	if err != nil {
+		panic(err)
+	}
+
+	serviceKey, err := rbac.LoadServiceKey()
+	if err != nil {
+		panic(err)
+	}
+
+	proxies, err := clientip.FromConfig()
+	if err != nil {
+		panic(err)
+	}
+
+	shutdownTracing, err := tracing.Init("flight-booking-grpc")
+	if err != nil {
+		panic(err)
+	}
+
 	listen, err := net.Listen("tcp", fmt.Sprintf(":%v", *port))
 	if err != nil {
 		panic(err)
@@ -42,14 +86,22 @@ func main() {
 
 	logger, _ := zap.NewProduction()
 	defer logger.Sync()
+	// zap.L() for code outside a request, a request carries its own logger, see logging.FromContext
+	zap.ReplaceGlobals(logger)
 
-	s := grpc.NewServer(
-		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
-			intercepter.UnaryServerLoggingIntercepter(logger),
-		)),
-	)
+	// Initial Audit and Auth repository START
+	auditRepository, err := audit_repo.NewDBManager()
+	if err != nil {
+		panic(err)
+	}
 
-	reflection.Register(s)
+	authRepository, err := auth_repo.NewDBManager()
+	if err != nil {
+		panic(err)
+	}
+
+	guard := auth_handler.NewPasswordGuard(authRepository, auditRepository)
+	// Initial Audit and Auth repository END
 
 	// Initial customer repository START
 	customerRepository, err := customer_repo.NewDBManager()
@@ -57,12 +109,31 @@ func main() {
 		panic(err)
 	}
 
-	h, err := customer_handler.NewCustomerHandler(customerRepository)
+	// Initial customer repository END
+
+	// Initial Booking repository START
+	bookingRepository, errBooking := booking_repo.NewDBManager()
+	if errBooking != nil {
+		panic(errBooking)
+	}
+	// Initial Booking repository END
+
+	// Initial Loyalty repository START
+	loyaltyRepository, errLoyalty := loyalty_repo.NewDBManager()
+	if errLoyalty != nil {
+		panic(errLoyalty)
+	}
+
+	hLoyalty, errLoyalty := loyalty_handler.NewLoyaltyHandler(loyaltyRepository, bookingRepository, customerRepository)
+	if errLoyalty != nil {
+		panic(errLoyalty)
+	}
+	// Initial Loyalty repository END
+
+	h, err := customer_handler.NewCustomerHandler(customerRepository, auditRepository, bookingRepository, loyaltyRepository, guard)
 	if err != nil {
 		panic(err)
 	}
-	protobuf.RegisterRPCCustomerServer(s, h)
-	// Initial customer repository END
 
 	// Initial Flight repository START
 	flightRepository, errFlight := flight_repo.NewDBManager()
@@ -70,27 +141,143 @@ func main() {
 		panic(errFlight)
 	}
 
-	hFlight, errFlight := flight_handler.NewFlightHandler(flightRepository)
+	hFlight, errFlight := flight_handler.NewFlightHandler(flightRepository, auditRepository, hLoyalty)
 	if errFlight != nil {
 		panic(errFlight)
 	}
-	protobuf.RegisterRPCFlightServer(s, hFlight)
 	// Initial Flight repository END
 
-	// Initial Booking repository START
-	bookingRepository, errBooking := booking_repo.NewDBManager()
-	if errBooking != nil {
-		panic(errBooking)
+	// Initial Organization repository START
+	organizationRepository, errOrganization := organization_repo.NewDBManager()
+	if errOrganization != nil {
+		panic(errOrganization)
+	}
+
+	hOrganization, errOrganization := organization_handler.NewOrganizationHandler(organizationRepository, customerRepository)
+	if errOrganization != nil {
+		panic(errOrganization)
 	}
+	// Initial Organization repository END
 
-	hBooking, errBooking := booking_handler.NewBookingHandler(bookingRepository)
+	hBooking, errBooking := booking_handler.NewBookingHandler(bookingRepository, customerRepository, flightRepository, organizationRepository, hLoyalty)
 	if errBooking != nil {
 		panic(errBooking)
 	}
+
+	// Initial Auth handler START
+	sender, errAuth := notification.NewSender(logger)
+	if errAuth != nil {
+		panic(errAuth)
+	}
+	sender = notification.NewPreferenceSender(sender, h.NotificationPreferences, logger)
+
+	hAuth, errAuth := auth_handler.NewAuthHandler(authRepository, customerRepository, auditRepository, sender, guard)
+	if errAuth != nil {
+		panic(errAuth)
+	}
+	// Initial Auth handler END
+
+	s := grpc.NewServer(
+		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
+			// Continues the trace of the gateway, the handlers and gorm add child spans
+			otelgrpc.UnaryServerInterceptor(),
+			// Outermost so the calls recovered from a panic are counted as Internal
+			intercepter.UnaryServerMetricsIntercepter(),
+			// Before recovery and logging so their lines carry the request id
+			intercepter.UnaryServerRequestIDIntercepter(logger),
+			intercepter.UnaryServerRecoveryIntercepter(logger),
+			intercepter.UnaryServerLoggingIntercepter(logger),
+			intercepter.UnaryServerClientIpIntercepter(proxies),
+			intercepter.UnaryServerAuthIntercepter(hAuth.ResolvePrincipal, serviceKey),
+		)),
+	)
+
+	reflection.Register(s)
+
+	// Health protocol, each service is SERVING while its database answers
+	healthServer := grpc_health.NewServer()
+	grpc_health_v1.RegisterHealthServer(s, healthServer)
+	healthChecks := map[string]health.Check{
+		protobuf.RPCCustomer_ServiceDesc.ServiceName: customerRepository.Ping,
+		protobuf.RPCFlight_ServiceDesc.ServiceName:   flightRepository.Ping,
+		protobuf.RPCBooking_ServiceDesc.ServiceName:  bookingRepository.Ping,
+	}
+
+	protobuf.RegisterRPCCustomerServer(s, h)
+	protobuf.RegisterRPCFlightServer(s, hFlight)
 	protobuf.RegisterRPCBookingServer(s, hBooking)
-	// Initial Booking repository END
+	protobuf.RegisterRPCAuthServer(s, hAuth)
+	protobuf.RegisterRPCLoyaltyServer(s, hLoyalty)
+	protobuf.RegisterRPCOrganizationServer(s, hOrganization)
+
+	ctx, stop := helper.ShutdownContext()
+	defer stop()
+
+	go health.Watch(ctx, healthServer, healthChecks, health.CheckInterval(), logger)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		fmt.Printf("Listen at port: %v\n", *port)
+		serveErr <- s.Serve(listen)
+	}()
+
+	// Metrics for Prometheus, on their own http port
+	metricsMux := http.NewServeMux()
+	metricsMux.Handle("/metrics", metrics.Handler())
+	metricsServer := &http.Server{Addr: fmt.Sprintf(":%v", *metricsPort), Handler: metricsMux}
+	metricsErr := make(chan error, 1)
+	go func() {
+		if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			metricsErr <- err
+		}
+	}()
 
-	fmt.Printf("Listen at port: %v\n", *port)
+	select {
+	case err = <-serveErr:
+		logger.Error("grpc server stopped", zap.Error(err))
+	case err = <-metricsErr:
+		// Without metrics the server is not observable, stop it like a shutdown
+		logger.Error("metrics server failed", zap.Int("port", *metricsPort), zap.Error(err))
+		stop()
+		healthServer.Shutdown()
+		gracefulStop(s, helper.ShutdownTimeout(), logger)
+	case <-ctx.Done():
+		stop()
+		logger.Info("shutting down, draining in-flight calls", zap.Duration("timeout", helper.ShutdownTimeout()))
+		// Report NOT_SERVING first so the gateway stops sending new work while the calls drain
+		healthServer.Shutdown()
+		gracefulStop(s, helper.ShutdownTimeout(), logger)
+	}
+	metricsServer.Close()
+
+	if errClose := database.Close(); errClose != nil {
+		logger.Error("close database pools", zap.Error(errClose))
+	}
+	flushCtx, cancelFlush := context.WithTimeout(context.Background(), helper.ShutdownTimeout())
+	defer cancelFlush()
+	if errTracing := shutdownTracing(flushCtx); errTracing != nil {
+		logger.Error("flush traces", zap.Error(errTracing))
+	}
+	logger.Info("stopped")
+
+	if err != nil {
+		logger.Sync()
+		os.Exit(1)
+	}
+}
 
-	s.Serve(listen)
+// gracefulStop lets the running calls finish, past the timeout they are cancelled
+func gracefulStop(s *grpc.Server, timeout time.Duration, logger *zap.Logger) {
+	done := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		logger.Warn("shutdown timeout reached, cancelling the remaining calls")
+		s.Stop()
+	}
 }
@@ -0,0 +1,121 @@
+package organization_model
+
+import (
+	"fmt"
+	"time"
+
+	booking_model "mock-golang/grpc/booking-grpc/model"
+	"mock-golang/protobuf"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// RoleTraveller is booked for, bookers and approvers may also book for the other members
+	RoleTraveller = "traveller"
+	RoleBooker    = "booker"
+	RoleApprover  = "approver"
+)
+
+// Organization is a corporate account, MaxFareClass and AdvancePurchaseDays are its travel policy
+type Organization struct {
+	Id                  uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Name                string    `gorm:"column:name"`
+	MaxFareClass        string    `gorm:"column:max_fare_class"`
+	AdvancePurchaseDays int32     `gorm:"column:advance_purchase_days"`
+	CreatedAt           time.Time `gorm:"column:created_at"`
+	UpdatedAt           time.Time `gorm:"column:updated_at"`
+	Members             []*Member `gorm:"foreignKey:OrganizationId;references:Id"`
+}
+
+// Member links a customer to its organization, a customer belongs to one organization at most
+type Member struct {
+	CustomerId     string    `gorm:"column:customer_id;primaryKey"`
+	OrganizationId uuid.UUID `gorm:"type:uuid;column:organization_id;index"`
+	Role           string    `gorm:"column:role"`
+	CustomerName   string    `gorm:"-"`
+	CreatedAt      time.Time `gorm:"column:created_at"`
+	UpdatedAt      time.Time `gorm:"column:updated_at"`
+}
+
+func (Member) TableName() string {
+	return "organization_members"
+}
+
+// ValidateRole accepts the member roles only
+func ValidateRole(role string) error {
+	switch role {
+	case RoleTraveller, RoleBooker, RoleApprover:
+		return nil
+	}
+
+	return fmt.Errorf("member role %q is invalid, use traveller, booker or approver", role)
+}
+
+// CanBookForOthers tells whether the member may book for the other members of its organization
+func (in *Member) CanBookForOthers() bool {
+	return in.Role == RoleBooker || in.Role == RoleApprover
+}
+
+func (in *Member) CanApprove() bool {
+	return in.Role == RoleApprover
+}
+
+// PolicyViolations lists the travel policy rules a booking breaks, empty when it complies
+func (in *Organization) PolicyViolations(fareClass string, departDate time.Time, now time.Time) ([]string, error) {
+	violations := []string{}
+
+	if in.MaxFareClass != "" {
+		max, err := booking_model.FareClassRank(in.MaxFareClass)
+		if err != nil {
+			return nil, err
+		}
+		rank, err := booking_model.FareClassRank(fareClass)
+		if err != nil {
+			return nil, err
+		}
+		if rank > max {
+			violations = append(violations, fmt.Sprintf("fare class %s is above the allowed %s", fareClass, in.MaxFareClass))
+		}
+	}
+
+	if in.AdvancePurchaseDays > 0 {
+		deadline := now.AddDate(0, 0, int(in.AdvancePurchaseDays))
+		if departDate.Before(deadline) {
+			violations = append(violations, fmt.Sprintf("booked less than %d days before departure", in.AdvancePurchaseDays))
+		}
+	}
+
+	return violations, nil
+}
+
+func (in *Organization) ToResponse() *protobuf.Organization {
+	res := &protobuf.Organization{
+		Id:                  in.Id.String(),
+		Name:                in.Name,
+		MaxFareClass:        in.MaxFareClass,
+		AdvancePurchaseDays: in.AdvancePurchaseDays,
+		CreatedAt:           timestamppb.New(in.CreatedAt),
+		UpdatedAt:           timestamppb.New(in.UpdatedAt),
+		Member:              []*protobuf.OrganizationMember{},
+	}
+
+	for _, member := range in.Members {
+		res.Member = append(res.Member, member.ToResponse())
+	}
+
+	return res
+}
+
+func (in *Member) ToResponse() *protobuf.OrganizationMember {
+	res := &protobuf.OrganizationMember{
+		OrganizationId: in.OrganizationId.String(),
+		CustomerId:     in.CustomerId,
+		Role:           in.Role,
+		CustomerName:   in.CustomerName,
+		CreatedAt:      timestamppb.New(in.CreatedAt),
+	}
+
+	return res
+}
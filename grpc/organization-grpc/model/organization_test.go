@@ -0,0 +1,45 @@
+package organization_model
+
+import (
+	"testing"
+	"time"
+
+	booking_model "mock-golang/grpc/booking-grpc/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicyViolations(t *testing.T) {
+	now := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	organization := &Organization{
+		MaxFareClass:        booking_model.FarePremiumEconomy,
+		AdvancePurchaseDays: 14,
+	}
+
+	violations, err := organization.PolicyViolations(booking_model.FareEconomy, now.AddDate(0, 0, 30), now)
+	assert.Nil(t, err)
+	assert.Empty(t, violations)
+
+	violations, err = organization.PolicyViolations(booking_model.FareBusiness, now.AddDate(0, 0, 3), now)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(violations))
+
+	_, err = organization.PolicyViolations("cargo", now.AddDate(0, 0, 30), now)
+	assert.NotNil(t, err)
+}
+
+func TestPolicyWithoutRules(t *testing.T) {
+	now := time.Now()
+
+	violations, err := (&Organization{}).PolicyViolations(booking_model.FareFirst, now, now)
+	assert.Nil(t, err)
+	assert.Empty(t, violations)
+}
+
+func TestMemberRoles(t *testing.T) {
+	assert.False(t, (&Member{Role: RoleTraveller}).CanBookForOthers())
+	assert.True(t, (&Member{Role: RoleBooker}).CanBookForOthers())
+	assert.False(t, (&Member{Role: RoleBooker}).CanApprove())
+	assert.True(t, (&Member{Role: RoleApprover}).CanApprove())
+	assert.NotNil(t, ValidateRole("owner"))
+}
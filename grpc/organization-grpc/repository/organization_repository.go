@@ -0,0 +1,107 @@
+package organization_repo
+
+import (
+	"context"
+	"mock-golang/apperror"
+	"mock-golang/database"
+	organization_model "mock-golang/grpc/organization-grpc/model"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//Embeded struct
+
+type OrganizationRepository interface {
+	FindById(ctx context.Context, id uuid.UUID) (*organization_model.Organization, error)
+	CreateOrganization(ctx context.Context, model *organization_model.Organization) (*organization_model.Organization, error)
+	UpdateOrganization(ctx context.Context, model *organization_model.Organization) (*organization_model.Organization, error)
+	FindMember(ctx context.Context, customerId string) (*organization_model.Member, error)
+	SaveMember(ctx context.Context, model *organization_model.Member) (*organization_model.Member, error)
+	RemoveMember(ctx context.Context, customerId string) error
+}
+
+type dbmanager struct {
+	*gorm.DB
+}
+
+func NewDBManager() (OrganizationRepository, error) {
+	db, err := database.NewGormDB()
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.AutoMigrate(
+		&organization_model.Organization{},
+		&organization_model.Member{},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &dbmanager{db}, nil
+}
+
+func (m *dbmanager) FindById(ctx context.Context, id uuid.UUID) (*organization_model.Organization, error) {
+	res := organization_model.Organization{}
+	if err := m.WithContext(ctx).Where(&organization_model.Organization{Id: id}).Preload("Members", func(db *gorm.DB) *gorm.DB {
+		return db.Order("role, created_at")
+	}).First(&res).Error; err != nil {
+		return nil, apperror.FromDB(err)
+	}
+
+	return &res, nil
+}
+
+func (m *dbmanager) CreateOrganization(ctx context.Context, model *organization_model.Organization) (*organization_model.Organization, error) {
+	if err := m.WithContext(ctx).Create(model).Error; err != nil {
+		return nil, apperror.FromDB(err)
+	}
+
+	return model, nil
+}
+
+// UpdateOrganization writes the policy even when a rule is switched off (zero value)
+func (m *dbmanager) UpdateOrganization(ctx context.Context, model *organization_model.Organization) (*organization_model.Organization, error) {
+	err := m.WithContext(ctx).Model(&organization_model.Organization{}).Where("id = ?", model.Id).
+		Updates(map[string]interface{}{
+			"name":                  model.Name,
+			"max_fare_class":        model.MaxFareClass,
+			"advance_purchase_days": model.AdvancePurchaseDays,
+			"updated_at":            model.UpdatedAt,
+		}).Error
+	if err != nil {
+		return nil, apperror.FromDB(err)
+	}
+
+	return model, nil
+}
+
+func (m *dbmanager) FindMember(ctx context.Context, customerId string) (*organization_model.Member, error) {
+	res := organization_model.Member{}
+	if err := m.WithContext(ctx).Where(&organization_model.Member{CustomerId: customerId}).First(&res).Error; err != nil {
+		return nil, apperror.FromDB(err)
+	}
+
+	return &res, nil
+}
+
+// SaveMember adds the customer to the organization or changes its role
+func (m *dbmanager) SaveMember(ctx context.Context, model *organization_model.Member) (*organization_model.Member, error) {
+	err := m.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "customer_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"role": model.Role, "updated_at": time.Now()}),
+	}).Create(model).Error
+	if err != nil {
+		return nil, apperror.FromDB(err)
+	}
+
+	return model, nil
+}
+
+func (m *dbmanager) RemoveMember(ctx context.Context, customerId string) error {
+	return apperror.FromDB(m.WithContext(ctx).Where(&organization_model.Member{CustomerId: customerId}).Delete(&organization_model.Member{}).Error)
+}
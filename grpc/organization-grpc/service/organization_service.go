@@ -0,0 +1,254 @@
+package organization_handler
+
+import (
+	"context"
+	"errors"
+	"mock-golang/apperror"
+	booking_model "mock-golang/grpc/booking-grpc/model"
+	customer_repo "mock-golang/grpc/customer-grpc/repository"
+	organization_model "mock-golang/grpc/organization-grpc/model"
+	organization_repo "mock-golang/grpc/organization-grpc/repository"
+	"mock-golang/protobuf"
+	"mock-golang/rbac"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"gorm.io/gorm"
+)
+
+type OrganizationHandler struct {
+	protobuf.UnimplementedRPCOrganizationServer
+	organizationRepository organization_repo.OrganizationRepository
+	customerRepository     customer_repo.CustomerRepository
+	mu                     *sync.Mutex
+}
+
+func NewOrganizationHandler(
+	organizationRepository organization_repo.OrganizationRepository,
+	customerRepository customer_repo.CustomerRepository) (*OrganizationHandler, error) {
+	return &OrganizationHandler{
+		organizationRepository: organizationRepository,
+		customerRepository:     customerRepository,
+		mu:                     &sync.Mutex{},
+	}, nil
+}
+
+// FindById is open to the organization managers and to the members of the organization
+func (h *OrganizationHandler) FindById(ctx context.Context, in *protobuf.OrganizationParamId) (*protobuf.Organization, error) {
+	org, err := h.findOrganization(ctx, in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	principal := rbac.FromContext(ctx)
+	if !principal.Can(rbac.PermOrganizationManage) {
+		if err := rbac.Require(ctx, rbac.PermCustomerReadOwn); err != nil {
+			return nil, err
+		}
+		if !isMember(org, principal.CustomerId) {
+			return nil, status.Errorf(codes.PermissionDenied, "not a member of organization %s", org.Id)
+		}
+	}
+
+	for _, member := range org.Members {
+		h.fillCustomerName(ctx, member)
+	}
+
+	return org.ToResponse(), nil
+}
+
+func (h *OrganizationHandler) CreateOrganization(ctx context.Context, in *protobuf.Organization) (*protobuf.Organization, error) {
+	if err := rbac.Require(ctx, rbac.PermOrganizationManage); err != nil {
+		return nil, err
+	}
+
+	if err := validatePolicy(in); err != nil {
+		return nil, err
+	}
+
+	req := &organization_model.Organization{
+		Id:                  uuid.New(),
+		Name:                strings.TrimSpace(in.Name),
+		MaxFareClass:        in.MaxFareClass,
+		AdvancePurchaseDays: in.AdvancePurchaseDays,
+		CreatedAt:           time.Now(),
+		UpdatedAt:           time.Now(),
+	}
+
+	out, err := h.organizationRepository.CreateOrganization(ctx, req)
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	return out.ToResponse(), nil
+}
+
+// UpdateOrganization replaces the name and the travel policy, existing bookings are not re-checked
+func (h *OrganizationHandler) UpdateOrganization(ctx context.Context, in *protobuf.Organization) (*protobuf.Organization, error) {
+	if err := rbac.Require(ctx, rbac.PermOrganizationManage); err != nil {
+		return nil, err
+	}
+
+	if err := validatePolicy(in); err != nil {
+		return nil, err
+	}
+
+	org, err := h.findOrganization(ctx, in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	org.Name = strings.TrimSpace(in.Name)
+	org.MaxFareClass = in.MaxFareClass
+	org.AdvancePurchaseDays = in.AdvancePurchaseDays
+	org.UpdatedAt = time.Now()
+
+	out, err := h.organizationRepository.UpdateOrganization(ctx, org)
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	return out.ToResponse(), nil
+}
+
+// SetMember adds a customer to the organization or changes its role there
+func (h *OrganizationHandler) SetMember(ctx context.Context, in *protobuf.OrganizationMember) (*protobuf.OrganizationMember, error) {
+	if err := rbac.Require(ctx, rbac.PermOrganizationManage); err != nil {
+		return nil, err
+	}
+
+	if err := organization_model.ValidateRole(in.Role); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	org, err := h.findOrganization(ctx, in.OrganizationId)
+	if err != nil {
+		return nil, err
+	}
+
+	customerId, err := apperror.ParseID("customerId", in.CustomerId)
+	if err != nil {
+		return nil, err
+	}
+
+	customer, err := h.customerRepository.FindById(ctx, customerId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "customer %s not found", customerId)
+		}
+		return nil, apperror.ToStatus(err)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	existing, err := h.organizationRepository.FindMember(ctx, customerId.String())
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.ToStatus(err)
+	}
+	if err == nil && existing.OrganizationId != org.Id {
+		return nil, status.Errorf(codes.FailedPrecondition, "customer %s already belongs to organization %s", customerId, existing.OrganizationId)
+	}
+
+	member := &organization_model.Member{
+		CustomerId:     customerId.String(),
+		OrganizationId: org.Id,
+		Role:           in.Role,
+		CustomerName:   customer.Name,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+	if existing != nil {
+		member.CreatedAt = existing.CreatedAt
+	}
+
+	out, err := h.organizationRepository.SaveMember(ctx, member)
+	if err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	return out.ToResponse(), nil
+}
+
+func (h *OrganizationHandler) RemoveMember(ctx context.Context, in *protobuf.OrganizationMember) (*protobuf.OrganizationMember, error) {
+	if err := rbac.Require(ctx, rbac.PermOrganizationManage); err != nil {
+		return nil, err
+	}
+
+	member, err := h.organizationRepository.FindMember(ctx, in.CustomerId)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperror.ToStatus(err)
+	}
+	if err != nil || (in.OrganizationId != "" && member.OrganizationId.String() != in.OrganizationId) {
+		return nil, status.Errorf(codes.NotFound, "customer %s is not a member", in.CustomerId)
+	}
+
+	if err := h.organizationRepository.RemoveMember(ctx, member.CustomerId); err != nil {
+		return nil, apperror.ToStatus(err)
+	}
+
+	h.fillCustomerName(ctx, member)
+
+	return member.ToResponse(), nil
+}
+
+func (h *OrganizationHandler) findOrganization(ctx context.Context, id string) (*organization_model.Organization, error) {
+	orgId, err := apperror.ParseID("organizationId", id)
+	if err != nil {
+		return nil, err
+	}
+
+	org, err := h.organizationRepository.FindById(ctx, orgId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, status.Errorf(codes.NotFound, "organization %s not found", orgId)
+		}
+		return nil, apperror.ToStatus(err)
+	}
+
+	return org, nil
+}
+
+// fillCustomerName is best effort, deactivated customers are listed without a name
+func (h *OrganizationHandler) fillCustomerName(ctx context.Context, member *organization_model.Member) {
+	customerId, err := uuid.Parse(member.CustomerId)
+	if err != nil {
+		return
+	}
+
+	if customer, err := h.customerRepository.FindById(ctx, customerId); err == nil {
+		member.CustomerName = customer.Name
+	}
+}
+
+func validatePolicy(in *protobuf.Organization) error {
+	if strings.TrimSpace(in.Name) == "" {
+		return status.Error(codes.InvalidArgument, "organization name is required")
+	}
+
+	if in.MaxFareClass != "" {
+		if _, err := booking_model.FareClassRank(in.MaxFareClass); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	if in.AdvancePurchaseDays < 0 {
+		return status.Errorf(codes.InvalidArgument, "advance purchase days %d must not be negative", in.AdvancePurchaseDays)
+	}
+
+	return nil
+}
+
+func isMember(org *organization_model.Organization, customerId string) bool {
+	for _, member := range org.Members {
+		if member.CustomerId == customerId {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,65 @@
+package health
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+	grpc_health "google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Check reports whether a dependency of a service is reachable, e.g. a DB ping
+type Check func(ctx context.Context) error
+
+const (
+	checkTimeout         = 2 * time.Second
+	defaultCheckInterval = 10 * time.Second
+)
+
+// CheckInterval is how often Watch runs the checks, health.check_interval in the config
+func CheckInterval() time.Duration {
+	if d := viper.GetDuration("health.check_interval"); d > 0 {
+		return d
+	}
+	return defaultCheckInterval
+}
+
+// Watch runs the checks of every service at once and then every interval until ctx is done.
+// Each service is SERVING while its check passes, the overall status "" only while all of them pass.
+func Watch(ctx context.Context, srv *grpc_health.Server, checks map[string]Check, interval time.Duration, logger *zap.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		Run(ctx, srv, checks, logger)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Run runs the checks once and updates the statuses of srv
+func Run(ctx context.Context, srv *grpc_health.Server, checks map[string]Check, logger *zap.Logger) {
+	overall := grpc_health_v1.HealthCheckResponse_SERVING
+
+	for service, check := range checks {
+		checkCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+		err := check(checkCtx)
+		cancel()
+
+		st := grpc_health_v1.HealthCheckResponse_SERVING
+		if err != nil {
+			logger.Warn("health check failed", zap.String("service", service), zap.Error(err))
+			st = grpc_health_v1.HealthCheckResponse_NOT_SERVING
+			overall = grpc_health_v1.HealthCheckResponse_NOT_SERVING
+		}
+		srv.SetServingStatus(service, st)
+	}
+
+	srv.SetServingStatus("", overall)
+}
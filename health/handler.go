@@ -0,0 +1,53 @@
+package health
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// Report lists the status of every gRPC service the gateway depends on
+type Report struct {
+	Services map[string]string `json:"services"`
+}
+
+// LivenessHandler answers as long as the process serves http, it does not look at the dependencies
+func LivenessHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": http.StatusText(http.StatusOK)})
+	}
+}
+
+// ReadinessHandler asks the gRPC health service about every service, it fails (503)
+// when one is not SERVING or the backend cannot be reached
+func ReadinessHandler(client grpc_health_v1.HealthClient, services []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), checkTimeout)
+		defer cancel()
+
+		report := Report{Services: map[string]string{}}
+		ready := true
+		for _, service := range services {
+			res, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: service})
+			switch {
+			case err != nil:
+				report.Services[service] = status.Code(err).String()
+				ready = false
+			case res.Status != grpc_health_v1.HealthCheckResponse_SERVING:
+				report.Services[service] = res.Status.String()
+				ready = false
+			default:
+				report.Services[service] = res.Status.String()
+			}
+		}
+
+		httpStatus := http.StatusOK
+		if !ready {
+			httpStatus = http.StatusServiceUnavailable
+		}
+		c.JSON(httpStatus, gin.H{"status": http.StatusText(httpStatus), "payload": report})
+	}
+}
@@ -0,0 +1,73 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	grpc_health "google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestRun(t *testing.T) {
+	srv := grpc_health.NewServer()
+	up := func(context.Context) error { return nil }
+	down := func(context.Context) error { return errors.New("connection refused") }
+
+	Run(context.Background(), srv, map[string]Check{"a": up, "b": down}, zap.NewNop())
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, servingStatus(t, srv, "a"))
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, servingStatus(t, srv, "b"))
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, servingStatus(t, srv, ""))
+
+	Run(context.Background(), srv, map[string]Check{"a": up, "b": up}, zap.NewNop())
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, servingStatus(t, srv, ""))
+}
+
+func servingStatus(t *testing.T, srv *grpc_health.Server, service string) grpc_health_v1.HealthCheckResponse_ServingStatus {
+	res, err := srv.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: service})
+	assert.Nil(t, err)
+	return res.Status
+}
+
+// serverClient calls the health server directly, without a connection
+type serverClient struct {
+	grpc_health_v1.HealthClient
+	srv *grpc_health.Server
+}
+
+func (c serverClient) Check(ctx context.Context, in *grpc_health_v1.HealthCheckRequest, _ ...grpc.CallOption) (*grpc_health_v1.HealthCheckResponse, error) {
+	return c.srv.Check(ctx, in)
+}
+
+func TestReadinessHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	srv := grpc_health.NewServer()
+	srv.SetServingStatus("a", grpc_health_v1.HealthCheckResponse_SERVING)
+	srv.SetServingStatus("b", grpc_health_v1.HealthCheckResponse_SERVING)
+
+	g := gin.New()
+	g.GET("/readyz", ReadinessHandler(serverClient{srv: srv}, []string{"a", "b"}))
+
+	w := httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	srv.SetServingStatus("b", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+	g.GET("/readyz-unknown", ReadinessHandler(serverClient{srv: srv}, []string{"a", "b", "c"}))
+	w = httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/readyz-unknown", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	body := struct {
+		Payload Report `json:"payload"`
+	}{}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, map[string]string{"a": "SERVING", "b": "NOT_SERVING", "c": "NotFound"}, body.Payload.Services)
+}
@@ -0,0 +1,22 @@
+package helper
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// DateRange turns an inclusive YYYY-MM-DD range into timestamps, an empty bound stays nil.
+// The upper bound is moved to the end of its day so that day still matches.
+func DateRange(from string, to string) (*timestamppb.Timestamp, *timestamppb.Timestamp) {
+	var fromTs, toTs *timestamppb.Timestamp
+
+	if d, err := time.Parse("2006-01-02", from); err == nil {
+		fromTs = timestamppb.New(d)
+	}
+	if d, err := time.Parse("2006-01-02", to); err == nil {
+		toTs = timestamppb.New(d.Add(24*time.Hour - time.Nanosecond))
+	}
+
+	return fromTs, toTs
+}
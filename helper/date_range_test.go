@@ -0,0 +1,18 @@
+package helper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDateRange(t *testing.T) {
+	from, to := DateRange("2021-09-01", "2021-09-30")
+	assert.Equal(t, time.Date(2021, 9, 1, 0, 0, 0, 0, time.UTC), from.AsTime())
+	assert.Equal(t, time.Date(2021, 9, 30, 23, 59, 59, 999999999, time.UTC), to.AsTime())
+
+	from, to = DateRange("", "")
+	assert.Nil(t, from)
+	assert.Nil(t, to)
+}
@@ -0,0 +1,26 @@
+package helper
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const defaultShutdownTimeout = 30 * time.Second
+
+// ShutdownTimeout bounds how long in-flight requests may drain, server.shutdown_timeout in the config
+func ShutdownTimeout() time.Duration {
+	if d := viper.GetDuration("server.shutdown_timeout"); d > 0 {
+		return d
+	}
+	return defaultShutdownTimeout
+}
+
+// ShutdownContext is done on SIGINT or SIGTERM, stop restores the default handling so a second signal kills the process
+func ShutdownContext() (ctx context.Context, stop context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+}
@@ -0,0 +1,18 @@
+package helper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShutdownTimeout(t *testing.T) {
+	t.Cleanup(func() { viper.Set("server.shutdown_timeout", nil) })
+
+	assert.Equal(t, defaultShutdownTimeout, ShutdownTimeout())
+
+	viper.Set("server.shutdown_timeout", "5s")
+	assert.Equal(t, 5*time.Second, ShutdownTimeout())
+}
@@ -0,0 +1,10 @@
+package helper
+
+import "strings"
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// EscapeLike escapes the LIKE wildcards in user input, so it only matches literally
+func EscapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
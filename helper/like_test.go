@@ -0,0 +1,12 @@
+package helper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscapeLike(t *testing.T) {
+	assert.Equal(t, "nguyen", EscapeLike("nguyen"))
+	assert.Equal(t, `50\%\_off\\`, EscapeLike(`50%_off\`))
+}
@@ -0,0 +1,42 @@
+package helper
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// NormalizePhone formats a phone number as E.164 (+<country code><number>).
+// Numbers written with a national trunk prefix (leading 0) get defaultCountryCode.
+func NormalizePhone(raw string, defaultCountryCode string) (string, error) {
+	phone := strings.TrimSpace(raw)
+	international := strings.HasPrefix(phone, "+")
+
+	digits := strings.Builder{}
+	for _, r := range phone {
+		switch {
+		case unicode.IsDigit(r):
+			digits.WriteRune(r)
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')':
+		case r == '+' && digits.Len() == 0:
+		default:
+			return "", fmt.Errorf("phone number %q contains %q", raw, r)
+		}
+	}
+
+	number := digits.String()
+	switch {
+	case international:
+	case strings.HasPrefix(number, "00"):
+		number = strings.TrimPrefix(number, "00")
+	case strings.HasPrefix(number, "0"):
+		number = strings.TrimPrefix(defaultCountryCode, "+") + strings.TrimPrefix(number, "0")
+	}
+
+	// E.164 allows at most 15 digits, country code included
+	if len(number) < 8 || len(number) > 15 || strings.HasPrefix(number, "0") {
+		return "", fmt.Errorf("phone number %q is not a valid E.164 number", raw)
+	}
+
+	return "+" + number, nil
+}
@@ -0,0 +1,27 @@
+package helper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	cases := map[string]string{
+		"0912 345 678":      "+84912345678",
+		"+84 912-345-678":   "+84912345678",
+		"0084912345678":     "+84912345678",
+		"84912345678":       "+84912345678",
+		"+1 (415) 555.2671": "+14155552671",
+	}
+	for raw, want := range cases {
+		got, err := NormalizePhone(raw, "84")
+		assert.Nil(t, err, raw)
+		assert.Equal(t, want, got, raw)
+	}
+
+	for _, raw := range []string{"", "12345", "0912abc678", "+0912345678", "+1234567890123456"} {
+		_, err := NormalizePhone(raw, "84")
+		assert.NotNil(t, err, raw)
+	}
+}
@@ -0,0 +1,45 @@
+package intercepter
+
+import (
+	"context"
+	"crypto/subtle"
+	"mock-golang/rbac"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type Authenticator func(ctx context.Context, accessToken string) (rbac.Principal, error)
+
+// UnaryServerAuthIntercepter puts the caller into the context, handlers check permissions with rbac.Require.
+// Calls without credentials continue as guest.
+func UnaryServerAuthIntercepter(authenticate Authenticator, serviceKey string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+
+		if key := firstValue(md, rbac.ServiceKeyHeader); serviceKey != "" && key != "" &&
+			subtle.ConstantTimeCompare([]byte(key), []byte(serviceKey)) == 1 {
+			return handler(rbac.NewContext(ctx, rbac.Principal{System: true}), req)
+		}
+
+		accessToken := strings.TrimSpace(strings.TrimPrefix(firstValue(md, rbac.AuthorizationHeader), "Bearer "))
+		if accessToken == "" {
+			return handler(ctx, req)
+		}
+
+		principal, err := authenticate(ctx, accessToken)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(rbac.NewContext(ctx, principal), req)
+	}
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
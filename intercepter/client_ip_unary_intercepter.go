@@ -0,0 +1,25 @@
+package intercepter
+
+import (
+	"context"
+	"mock-golang/clientip"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// UnaryServerClientIpIntercepter puts the end user address in the context: the peer address,
+// or the x-client-ip the gateway reported when the peer is a trusted proxy
+func UnaryServerClientIpIntercepter(proxies clientip.Proxies) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		remote := ""
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			remote = p.Addr.String()
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+
+		return handler(clientip.NewContext(ctx, proxies.Resolve(remote, firstValue(md, clientip.Metadata))), req)
+	}
+}
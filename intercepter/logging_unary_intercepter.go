@@ -2,6 +2,7 @@ package intercepter
 
 import (
 	"context"
+	"mock-golang/logging"
 	"time"
 
 	"go.uber.org/zap"
@@ -16,7 +17,7 @@ func UnaryServerLoggingIntercepter(logger *zap.Logger) grpc.UnaryServerIntercept
 		end := time.Since(start)
 		method := info.FullMethod
 
-		logger.Info("Unary call has completed", zap.String("method", method), zap.String("duration", end.String()))
+		logging.FromContext(ctx, logger).Info("Unary call has completed", zap.String("method", method), zap.String("duration", end.String()))
 		return res, err
 	}
 }
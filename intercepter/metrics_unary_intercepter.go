@@ -0,0 +1,24 @@
+package intercepter
+
+import (
+	"context"
+	"mock-golang/metrics"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerMetricsIntercepter counts and times the calls by method and status code
+func UnaryServerMetricsIntercepter() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		start := time.Now()
+		res, err := handler(ctx, req)
+
+		code := status.Code(err).String()
+		metrics.GRPCRequests.WithLabelValues(info.FullMethod, code).Inc()
+		metrics.GRPCDuration.WithLabelValues(info.FullMethod, code).Observe(time.Since(start).Seconds())
+
+		return res, err
+	}
+}
@@ -0,0 +1,36 @@
+package intercepter
+
+import (
+	"context"
+	"testing"
+
+	"mock-golang/metrics"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMetricsIntercepterCodeLabel(t *testing.T) {
+	intercept := UnaryServerMetricsIntercepter()
+	info := &grpc.UnaryServerInfo{FullMethod: "/protobuf.Flight/FindById"}
+
+	ok := metrics.GRPCRequests.WithLabelValues(info.FullMethod, codes.OK.String())
+	notFound := metrics.GRPCRequests.WithLabelValues(info.FullMethod, codes.NotFound.String())
+	okBefore, notFoundBefore := testutil.ToFloat64(ok), testutil.ToFloat64(notFound)
+
+	_, err := intercept(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "flight", nil
+	})
+	assert.Nil(t, err)
+
+	_, err = intercept(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.NotFound, "flight not found")
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	assert.Equal(t, okBefore+1, testutil.ToFloat64(ok))
+	assert.Equal(t, notFoundBefore+1, testutil.ToFloat64(notFound))
+}
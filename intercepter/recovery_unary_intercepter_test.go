@@ -0,0 +1,41 @@
+package intercepter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRecoveryIntercepterAnswersInternal(t *testing.T) {
+	core, logs := observer.New(zap.ErrorLevel)
+	intercept := UnaryServerRecoveryIntercepter(zap.New(core))
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/protobuf.Booking/CreateBooking"}
+	resp, err := intercept(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		panic("nil booking")
+	})
+
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.Internal, status.Code(err))
+	// the panic is logged, never sent to the client
+	assert.Equal(t, "internal error", status.Convert(err).Message())
+	assert.Equal(t, 1, logs.FilterMessage("Unary call panicked").Len())
+}
+
+func TestRecoveryIntercepterPassesResults(t *testing.T) {
+	intercept := UnaryServerRecoveryIntercepter(zap.NewNop())
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/protobuf.Booking/CreateBooking"}
+	resp, err := intercept(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "ok", nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "ok", resp)
+}
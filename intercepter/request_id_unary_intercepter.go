@@ -0,0 +1,24 @@
+package intercepter
+
+import (
+	"context"
+	"mock-golang/logging"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// UnaryServerRequestIDIntercepter takes the request id the gateway forwarded, or makes one for direct callers,
+// and puts a logger carrying it in the context of the handler
+func UnaryServerRequestIDIntercepter(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		requestID := logging.RequestID(firstValue(md, logging.RequestIDMetadata))
+
+		// Callers that did not send one can still find it in the response header
+		_ = grpc.SetHeader(ctx, metadata.Pairs(logging.RequestIDMetadata, requestID))
+
+		return handler(logging.NewContext(ctx, logger, requestID), req)
+	}
+}
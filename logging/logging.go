@@ -0,0 +1,50 @@
+package logging
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// RequestIDHeader is the http header the gateway reads and answers
+	RequestIDHeader = "X-Request-ID"
+	// RequestIDMetadata is the gRPC metadata key the gateway forwards it in
+	RequestIDMetadata = "x-request-id"
+)
+
+// ids chosen by clients end up in every log line, anything else is replaced
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+type loggerKey struct{}
+type requestIDKey struct{}
+
+// RequestID keeps a well formed id from the caller, otherwise it generates one
+func RequestID(incoming string) string {
+	if validRequestID.MatchString(incoming) {
+		return incoming
+	}
+	return uuid.New().String()
+}
+
+// NewContext attaches the request id and a logger that carries it to ctx
+func NewContext(ctx context.Context, logger *zap.Logger, requestID string) context.Context {
+	ctx = context.WithValue(ctx, requestIDKey{}, requestID)
+	return context.WithValue(ctx, loggerKey{}, logger.With(zap.String("request_id", requestID)))
+}
+
+// FromContext is the request-scoped logger of ctx, or fallback outside a request
+func FromContext(ctx context.Context, fallback *zap.Logger) *zap.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*zap.Logger); ok {
+		return logger
+	}
+	return fallback
+}
+
+// RequestIDFromContext is the request id of ctx, empty outside a request
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
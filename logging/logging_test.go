@@ -0,0 +1,34 @@
+package logging
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRequestID(t *testing.T) {
+	assert.Equal(t, "abc-123", RequestID("abc-123"))
+
+	for _, incoming := range []string{"", "a b", "line\nbreak", strings.Repeat("a", 129)} {
+		id := RequestID(incoming)
+		assert.NotEqual(t, incoming, id)
+		assert.Len(t, id, 36)
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	fallback := zap.NewNop()
+	assert.Equal(t, fallback, FromContext(context.Background(), fallback))
+	assert.Equal(t, "", RequestIDFromContext(context.Background()))
+
+	core, logs := observer.New(zap.InfoLevel)
+	ctx := NewContext(context.Background(), zap.New(core), "abc-123")
+	FromContext(ctx, fallback).Info("hello")
+
+	assert.Equal(t, "abc-123", RequestIDFromContext(ctx))
+	assert.Equal(t, "abc-123", logs.All()[0].ContextMap()["request_id"])
+}
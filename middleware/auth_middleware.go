@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"mock-golang/apperror"
+	"mock-golang/protobuf"
+	"mock-golang/rbac"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// AuthMiddleware resolves the bearer token into the caller and forwards it to the gRPC services.
+// Requests without a token continue as guest.
+func AuthMiddleware(authClient protobuf.RPCAuthClient) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		accessToken := strings.TrimSpace(strings.TrimPrefix(header, "Bearer "))
+		if accessToken == "" {
+			c.Next()
+			return
+		}
+
+		pRes, err := authClient.Authenticate(c.Request.Context(), &protobuf.AuthenticateRequest{
+			AccessToken: accessToken,
+		})
+		if err != nil {
+			apperror.AbortWithRPCError(c, status.Error(codes.Unauthenticated, status.Convert(err).Message()))
+			return
+		}
+
+		principal := rbac.Principal{
+			CustomerId: pRes.CustomerId,
+			Role:       rbac.Role(pRes.Role),
+		}
+
+		ctx := rbac.NewContext(c.Request.Context(), principal)
+		ctx = metadata.AppendToOutgoingContext(ctx, rbac.AuthorizationHeader, "Bearer "+accessToken)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
+// RequirePermission rejects callers whose role was not granted any of the permissions
+func RequirePermission(perms ...rbac.Permission) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		principal := rbac.FromContext(c.Request.Context())
+		for _, perm := range perms {
+			if principal.Can(perm) {
+				c.Next()
+				return
+			}
+		}
+
+		code := codes.PermissionDenied
+		if !principal.IsAuthenticated() {
+			code = codes.Unauthenticated
+		}
+
+		apperror.AbortWithRPCError(c, status.Error(code, "permission denied"))
+	}
+}
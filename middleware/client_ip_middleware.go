@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"mock-golang/clientip"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/metadata"
+)
+
+// ClientIPMiddleware forwards the end user ip to the gRPC services, used for per-ip throttling.
+// X-Forwarded-For is only read when the request comes from a trusted proxy.
+func ClientIPMiddleware(proxies clientip.Proxies) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		ip := proxies.Resolve(c.Request.RemoteAddr, c.GetHeader("X-Forwarded-For"))
+
+		ctx := metadata.AppendToOutgoingContext(c.Request.Context(), clientip.Metadata, ip)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"mock-golang/logging"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -13,7 +14,7 @@ func LoggingMiddleware(logger *zap.Logger) func(c *gin.Context) {
 		c.Next()
 		end := time.Since(start)
 
-		logger.Info("Completed api call",
+		logging.FromContext(c.Request.Context(), logger).Info("Completed api call",
 			zap.String("address", c.Request.RequestURI),
 			zap.String("duration", end.String()))
 	}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"mock-golang/metrics"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricsMiddlewareLabels(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	g := gin.New()
+	g.Use(MetricsMiddleware())
+	g.DELETE("/v1/api/flight/:id", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	found := metrics.HTTPRequests.WithLabelValues("/v1/api/flight/:id", http.MethodDelete, "204")
+	unmatched := metrics.HTTPRequests.WithLabelValues("unmatched", http.MethodGet, "404")
+	foundBefore, unmatchedBefore := testutil.ToFloat64(found), testutil.ToFloat64(unmatched)
+
+	for _, id := range []string{"a", "b"} {
+		g.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodDelete, "/v1/api/flight/"+id, nil))
+	}
+	g.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/nowhere", nil))
+
+	// ids share the route template, a label per id would grow without bound
+	assert.Equal(t, foundBefore+2, testutil.ToFloat64(found))
+	assert.Equal(t, unmatchedBefore+1, testutil.ToFloat64(unmatched))
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	"mock-golang/apperror"
+	"mock-golang/logging"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RecoveryMiddleware answers a panic with the standard error envelope instead of dropping the connection
+func RecoveryMiddleware(logger *zap.Logger) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		defer func() {
+			if r := recover(); r != nil {
+				logging.FromContext(c.Request.Context(), logger).Error("Api call panicked",
+					zap.String("address", c.Request.RequestURI),
+					zap.Any("panic", r),
+					zap.ByteString("stack", debug.Stack()))
+
+				if c.Writer.Written() {
+					c.Abort()
+					return
+				}
+				apperror.AbortWithRPCError(c, status.Error(codes.Internal, fmt.Sprint(r)))
+			}
+		}()
+
+		c.Next()
+	}
+}
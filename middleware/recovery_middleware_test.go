@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRecoveryMiddlewareAnswersEnvelope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	core, logs := observer.New(zap.ErrorLevel)
+
+	g := gin.New()
+	g.Use(RecoveryMiddleware(zap.New(core)))
+	g.GET("/booking/:id", func(c *gin.Context) {
+		panic("nil booking")
+	})
+
+	w := httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/booking/1", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	body := map[string]string{}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, map[string]string{
+		"status": http.StatusText(http.StatusInternalServerError),
+		"code":   "internal",
+		"error":  "internal error",
+	}, body)
+	assert.Equal(t, 1, logs.FilterMessage("Api call panicked").Len())
+}
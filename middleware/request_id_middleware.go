@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"mock-golang/logging"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/metadata"
+)
+
+// RequestIDMiddleware answers the X-Request-ID of the caller, or a new one, forwards it to the gRPC services
+// and puts a logger carrying it in the request context
+func RequestIDMiddleware(logger *zap.Logger) func(c *gin.Context) {
+	return func(c *gin.Context) {
+		requestID := logging.RequestID(c.GetHeader(logging.RequestIDHeader))
+		c.Header(logging.RequestIDHeader, requestID)
+
+		ctx := logging.NewContext(c.Request.Context(), logger, requestID)
+		ctx = metadata.AppendToOutgoingContext(ctx, logging.RequestIDMetadata, requestID)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
@@ -0,0 +1,103 @@
+package notification
+
+import (
+	"context"
+	"mock-golang/logging"
+
+	"go.uber.org/zap"
+)
+
+const (
+	ChannelEmail = "email"
+	ChannelSms   = "sms"
+)
+
+const (
+	// CategoryAccount covers security messages (password reset, verification codes), they ignore preferences
+	CategoryAccount        = "account"
+	CategoryBookingUpdates = "booking_updates"
+	CategoryFlightChanges  = "flight_changes"
+	CategoryPromotions     = "promotions"
+)
+
+// Preferences are the channels and categories a customer agreed to receive
+type Preferences struct {
+	Channels   map[string]bool
+	Categories map[string]bool
+	Language   string
+}
+
+// Allows reports whether a message of the category may be sent on the channel
+func (p *Preferences) Allows(channel string, category string) bool {
+	if !p.Channels[channel] {
+		return false
+	}
+
+	return category == CategoryAccount || p.Categories[category]
+}
+
+// PreferenceLookup returns the preferences of a customer, defaults when none were saved
+type PreferenceLookup func(ctx context.Context, customerId string) (*Preferences, error)
+
+type preferenceSender struct {
+	next   Sender
+	lookup PreferenceLookup
+	logger *zap.Logger
+}
+
+// NewPreferenceSender drops the channels and categories a customer opted out of before
+// handing the event to next. Account events always go out, in the customer language.
+func NewPreferenceSender(next Sender, lookup PreferenceLookup, logger *zap.Logger) Sender {
+	return &preferenceSender{
+		next:   next,
+		lookup: lookup,
+		logger: logger,
+	}
+}
+
+func (s *preferenceSender) Send(ctx context.Context, event *Event) error {
+	if event.CustomerId == "" {
+		return s.next.Send(ctx, event)
+	}
+
+	category := event.Category
+	if category == "" {
+		category = CategoryAccount
+	}
+
+	preferences, err := s.lookup(ctx, event.CustomerId)
+	if err != nil {
+		// Security messages must not depend on the preferences being readable
+		if category == CategoryAccount {
+			return s.next.Send(ctx, event)
+		}
+		return err
+	}
+
+	// The caller may send the same event again, only the copy is changed
+	filtered := *event
+	if filtered.Language == "" {
+		filtered.Language = preferences.Language
+	}
+
+	if category == CategoryAccount {
+		return s.next.Send(ctx, &filtered)
+	}
+
+	if !preferences.Allows(ChannelEmail, category) {
+		filtered.Email = ""
+	}
+	if !preferences.Allows(ChannelSms, category) {
+		filtered.Phone = ""
+	}
+
+	if filtered.Email == "" && filtered.Phone == "" {
+		logging.FromContext(ctx, s.logger).Info("Notification suppressed by customer preferences",
+			zap.String("type", event.Type),
+			zap.String("category", category),
+			zap.String("customer_id", event.CustomerId))
+		return nil
+	}
+
+	return s.next.Send(ctx, &filtered)
+}
@@ -0,0 +1,84 @@
+package notification
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+type recordingSender struct {
+	events []*Event
+}
+
+func (s *recordingSender) Send(ctx context.Context, event *Event) error {
+	s.events = append(s.events, event)
+	return nil
+}
+
+func newPreferenceSender(preferences *Preferences) (Sender, *recordingSender) {
+	next := &recordingSender{}
+	lookup := func(ctx context.Context, customerId string) (*Preferences, error) {
+		return preferences, nil
+	}
+
+	return NewPreferenceSender(next, lookup, zap.NewNop()), next
+}
+
+func TestPreferenceSenderAccountEventsIgnoreOptOut(t *testing.T) {
+	sender, next := newPreferenceSender(&Preferences{Language: "en"})
+
+	err := sender.Send(context.Background(), &Event{
+		Type:       EventPasswordResetRequested,
+		CustomerId: "c1",
+		Email:      "a@example.com",
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(next.events))
+	assert.Equal(t, "a@example.com", next.events[0].Email)
+	assert.Equal(t, "en", next.events[0].Language)
+}
+
+func TestPreferenceSenderDropsChannelsAndCategories(t *testing.T) {
+	sender, next := newPreferenceSender(&Preferences{
+		Channels:   map[string]bool{ChannelEmail: true, ChannelSms: false},
+		Categories: map[string]bool{CategoryFlightChanges: true, CategoryPromotions: false},
+		Language:   "vi",
+	})
+
+	err := sender.Send(context.Background(), &Event{
+		Type:       "flight_delayed",
+		Category:   CategoryFlightChanges,
+		CustomerId: "c1",
+		Email:      "a@example.com",
+		Phone:      "+84912345678",
+	})
+	assert.Nil(t, err)
+
+	err = sender.Send(context.Background(), &Event{
+		Type:       "summer_sale",
+		Category:   CategoryPromotions,
+		CustomerId: "c1",
+		Email:      "a@example.com",
+	})
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, len(next.events))
+	assert.Equal(t, "a@example.com", next.events[0].Email)
+	assert.Equal(t, "", next.events[0].Phone)
+}
+
+func TestPreferenceSenderKeepsCallerEvent(t *testing.T) {
+	sender, next := newPreferenceSender(&Preferences{Language: "en"})
+
+	event := &Event{
+		Type:       EventPasswordResetRequested,
+		CustomerId: "c1",
+		Email:      "a@example.com",
+	}
+	assert.Nil(t, sender.Send(context.Background(), event))
+
+	assert.Equal(t, "", event.Language)
+	assert.Equal(t, "en", next.events[0].Language)
+}
@@ -0,0 +1,110 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mock-golang/logging"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+const (
+	EventPasswordResetRequested = "password_reset_requested"
+	EventPasswordResetCompleted = "password_reset_completed"
+	EventEmailVerification      = "email_verification_requested"
+	EventPhoneOtp               = "phone_otp_requested"
+	EventAccountClaim           = "account_claim_requested"
+)
+
+type Event struct {
+	Type string `json:"type"`
+	// Category is one of the Category constants, empty means CategoryAccount
+	Category   string            `json:"category,omitempty"`
+	Language   string            `json:"language,omitempty"`
+	CustomerId string            `json:"customerId"`
+	Email      string            `json:"email"`
+	Phone      string            `json:"phone,omitempty"`
+	Data       map[string]string `json:"data,omitempty"`
+	CreatedAt  time.Time         `json:"createdAt"`
+}
+
+// Sender delivers notification events to customers (email, sms, ...)
+type Sender interface {
+	Send(ctx context.Context, event *Event) error
+}
+
+// NewSender picks the sender configured by notification.sender (log or file)
+func NewSender(logger *zap.Logger) (Sender, error) {
+	switch kind := viper.GetString("notification.sender"); kind {
+	case "", "log":
+		return NewLogSender(logger), nil
+	case "file":
+		return NewFileSender(viper.GetString("notification.file_path"))
+	default:
+		return nil, fmt.Errorf("unknown notification sender %q", kind)
+	}
+}
+
+type logSender struct {
+	logger *zap.Logger
+}
+
+// NewLogSender writes every event to the logger, useful for local development
+func NewLogSender(logger *zap.Logger) Sender {
+	return &logSender{
+		logger: logger,
+	}
+}
+
+func (s *logSender) Send(ctx context.Context, event *Event) error {
+	logging.FromContext(ctx, s.logger).Info("Notification event",
+		zap.String("type", event.Type),
+		zap.String("category", event.Category),
+		zap.String("language", event.Language),
+		zap.String("customer_id", event.CustomerId),
+		zap.String("email", event.Email),
+		zap.String("phone", event.Phone),
+		zap.Any("data", event.Data))
+	return nil
+}
+
+type fileSender struct {
+	path string
+	mu   *sync.Mutex
+}
+
+// NewFileSender appends every event as one JSON line to the given file
+func NewFileSender(path string) (Sender, error) {
+	if path == "" {
+		return nil, fmt.Errorf("notification.file_path is required for the file sender")
+	}
+
+	return &fileSender{
+		path: path,
+		mu:   &sync.Mutex{},
+	}, nil
+}
+
+func (s *fileSender) Send(ctx context.Context, event *Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
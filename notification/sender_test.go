@@ -0,0 +1,46 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileSender(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notifications.log")
+
+	sender, err := NewFileSender(path)
+	assert.Nil(t, err)
+
+	for _, email := range []string{"a@example.com", "b@example.com"} {
+		err = sender.Send(context.Background(), &Event{
+			Type:      EventPasswordResetRequested,
+			Email:     email,
+			Data:      map[string]string{"token": "abc"},
+			CreatedAt: time.Now(),
+		})
+		assert.Nil(t, err)
+	}
+
+	content, err := os.ReadFile(path)
+	assert.Nil(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	assert.Equal(t, 2, len(lines))
+
+	event := Event{}
+	assert.Nil(t, json.Unmarshal([]byte(lines[1]), &event))
+	assert.Equal(t, "b@example.com", event.Email)
+	assert.Equal(t, "abc", event.Data["token"])
+}
+
+func TestFileSenderRequiresPath(t *testing.T) {
+	_, err := NewFileSender("")
+	assert.NotNil(t, err)
+}
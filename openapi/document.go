@@ -0,0 +1,325 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"mock-golang/pagination"
+	"mock-golang/rbac"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Operation documents one gateway route, the key of the operations map is "METHOD /path" as gin has it
+type Operation struct {
+	Summary string
+	// Permissions the route accepts, any one of them; none for a public route
+	Permissions []rbac.Permission
+	Query       interface{}
+	Body        interface{}
+	// Payload is what the success envelope carries, nil when the route answers ContentType instead
+	Payload interface{}
+	// Paged adds nextPageToken and totalSize to the envelope and the paging query parameters
+	Paged       bool
+	ContentType string
+	// Transcoded routes are served by the REST proxy and documented by the http annotations of the protos
+	Transcoded bool
+}
+
+type Document struct {
+	OpenAPI    string                          `json:"openapi"`
+	Info       Info                            `json:"info"`
+	Paths      map[string]map[string]*PathItem `json:"paths"`
+	Components Components                      `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme"`
+}
+
+// PathItem is the operation object of one method of a path
+type PathItem struct {
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []*Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string                `json:"description"`
+	Headers     map[string]*Header    `json:"headers,omitempty"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type Header struct {
+	Description string  `json:"description"`
+	Schema      *Schema `json:"schema"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// bearerAuth is the security scheme of the routes needing a permission
+const bearerAuth = "bearerAuth"
+
+var pathParam = regexp.MustCompile(`:(\w+)`)
+
+// New documents the gin routes with their operations, see Undocumented for the routes left out.
+// The Transcoded routes are documented from the google.api.http options of services instead.
+func New(info Info, routes gin.RoutesInfo, operations map[string]Operation, services ...protoreflect.ServiceDescriptor) *Document {
+	s := newSchemas()
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info:    info,
+		Paths:   map[string]map[string]*PathItem{},
+		Components: Components{
+			Schemas:         s.byName,
+			SecuritySchemes: map[string]*SecurityScheme{bearerAuth: {Type: "http", Scheme: "bearer"}},
+		},
+	}
+	s.byName["Error"] = errorSchema()
+	s.byName["ValidationError"] = validationErrorSchema()
+
+	for _, route := range routes {
+		op, ok := operations[Key(route.Method, route.Path)]
+		if !ok || op.Transcoded {
+			continue
+		}
+
+		path := pathParam.ReplaceAllString(route.Path, "{$1}")
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = map[string]*PathItem{}
+		}
+		doc.Paths[path][strings.ToLower(route.Method)] = s.pathItem(route.Path, op)
+	}
+
+	for _, service := range services {
+		s.transcoded(doc, service)
+	}
+
+	return doc
+}
+
+// Key is the operations map key of a route
+func Key(method string, path string) string {
+	return method + " " + path
+}
+
+// Undocumented lists the routes without an operation and the operations without a route
+func Undocumented(routes gin.RoutesInfo, operations map[string]Operation) []string {
+	missing := []string{}
+	seen := map[string]bool{}
+	for _, route := range routes {
+		key := Key(route.Method, route.Path)
+		seen[key] = true
+		if _, ok := operations[key]; !ok {
+			missing = append(missing, "no operation for route "+key)
+		}
+	}
+
+	for key := range operations {
+		if !seen[key] {
+			missing = append(missing, "no route for operation "+key)
+		}
+	}
+	sort.Strings(missing)
+
+	return missing
+}
+
+func (s *schemas) pathItem(ginPath string, op Operation) *PathItem {
+	item := &PathItem{
+		Summary:   op.Summary,
+		Tags:      []string{tag(ginPath)},
+		Responses: map[string]*Response{},
+	}
+
+	for _, match := range pathParam.FindAllStringSubmatch(ginPath, -1) {
+		item.Parameters = append(item.Parameters, &Parameter{Name: match[1], In: "path", Required: true, Schema: &Schema{Type: "string"}})
+	}
+	query := []Field{}
+	if op.Query != nil {
+		query = s.fields(reflect.TypeOf(op.Query), "form")
+	}
+	if op.Paged && !hasField(query, "pageToken") {
+		query = append(query, s.fields(reflect.TypeOf(pagination.Query{}), "form")...)
+	}
+	for _, field := range query {
+		item.Parameters = append(item.Parameters, &Parameter{Name: field.Name, In: "query", Required: field.Required, Schema: field.Schema})
+	}
+	if op.Body != nil {
+		item.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]*MediaType{"application/json": {Schema: s.of(reflect.TypeOf(op.Body))}},
+		}
+	}
+
+	if len(op.Permissions) > 0 {
+		permissions := []string{}
+		for _, permission := range op.Permissions {
+			permissions = append(permissions, string(permission))
+		}
+		item.Description = "Needs one of the permissions: " + strings.Join(permissions, ", ")
+		item.Security = []map[string][]string{{bearerAuth: {}}}
+		item.Responses["401"] = errorResponse(http.StatusUnauthorized)
+		item.Responses["403"] = errorResponse(http.StatusForbidden)
+	}
+
+	item.Responses["200"] = s.success(op)
+	item.Responses["400"] = validationResponse(http.StatusBadRequest)
+	item.Responses["422"] = validationResponse(http.StatusUnprocessableEntity)
+	item.Responses["default"] = errorResponse(http.StatusInternalServerError)
+
+	return item
+}
+
+// success is the {status, payload} envelope, or the raw content of a download
+func (s *schemas) success(op Operation) *Response {
+	if op.Payload == nil {
+		contentType := op.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		return &Response{
+			Description: http.StatusText(http.StatusOK),
+			Content:     map[string]*MediaType{contentType: {Schema: &Schema{Type: "string", Format: "binary"}}},
+		}
+	}
+
+	envelope := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"status":  {Type: "string"},
+			"payload": s.of(reflect.TypeOf(op.Payload)),
+		},
+		Required: []string{"status", "payload"},
+	}
+	res := &Response{Description: http.StatusText(http.StatusOK)}
+
+	if op.Paged {
+		envelope.Properties["nextPageToken"] = &Schema{Type: "string", Description: "empty on the last page"}
+		envelope.Properties["totalSize"] = &Schema{Type: "integer", Format: "int64"}
+		res.Headers = map[string]*Header{
+			"Link": {Description: `<url of the next page>; rel="next"`, Schema: &Schema{Type: "string"}},
+		}
+	}
+	res.Content = map[string]*MediaType{"application/json": {Schema: envelope}}
+
+	return res
+}
+
+func hasField(fields []Field, name string) bool {
+	for _, field := range fields {
+		if field.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// tag groups the operations by the first path segment after the api prefix
+func tag(ginPath string) string {
+	segments := strings.Split(strings.TrimPrefix(ginPath, "/v1/api/"), "/")
+
+	return segments[0]
+}
+
+func errorResponse(code int) *Response {
+	return &Response{
+		Description: http.StatusText(code),
+		Content:     map[string]*MediaType{"application/json": {Schema: &Schema{Ref: "#/components/schemas/Error"}}},
+	}
+}
+
+// validationResponse is the envelope with the field details from the binding, or the plain envelope from the services
+func validationResponse(code int) *Response {
+	return &Response{
+		Description: http.StatusText(code),
+		Content: map[string]*MediaType{"application/json": {Schema: &Schema{OneOf: []*Schema{
+			{Ref: "#/components/schemas/ValidationError"},
+			{Ref: "#/components/schemas/Error"},
+		}}}},
+	}
+}
+
+func errorSchema() *Schema {
+	return &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"status": {Type: "string"},
+			"code":   {Type: "string", Description: "stable error code such as not_found or already_exists"},
+			"error":  {Type: "string"},
+			"field":  {Type: "string", Description: "the field an already_exists or invalid_argument error is about"},
+		},
+		Required: []string{"status", "code", "error"},
+	}
+}
+
+func validationErrorSchema() *Schema {
+	return &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"status": {Type: "string"},
+			"code":   {Type: "string", Enum: []string{"invalid_argument", "validation_failed"}},
+			"error":  {Type: "string", Description: "every problem in one sentence"},
+			"field":  {Type: "string", Description: "the field of the first problem"},
+			"details": {Type: "array", Items: &Schema{
+				Type: "object",
+				Properties: map[string]*Schema{
+					"field":   {Type: "string"},
+					"code":    {Type: "string"},
+					"message": {Type: "string"},
+				},
+			}},
+		},
+		Required: []string{"status", "code", "error", "details"},
+	}
+}
+
+// Handler serves the document, encoded once
+func Handler(doc *Document) gin.HandlerFunc {
+	raw, err := json.Marshal(doc)
+
+	return func(c *gin.Context) {
+		if err != nil {
+			c.AbortWithError(http.StatusInternalServerError, err)
+			return
+		}
+
+		c.Data(http.StatusOK, "application/json; charset=utf-8", raw)
+	}
+}
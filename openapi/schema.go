@@ -0,0 +1,241 @@
+package openapi
+
+import (
+	"encoding"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema is the subset of the OpenAPI schema object the gateway types need
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+}
+
+// Field is a documented field of a query or path
+type Field struct {
+	Name     string
+	Required bool
+	Schema   *Schema
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// schemas collects the named struct schemas of a document under components
+type schemas struct {
+	byName map[string]*Schema
+	names  map[reflect.Type]string
+}
+
+func newSchemas() *schemas {
+	return &schemas{byName: map[string]*Schema{}, names: map[reflect.Type]string{}}
+}
+
+// of returns the schema of a value as encoding/json writes it, named structs become references
+func (s *schemas) of(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case reflect.PtrTo(t).Implements(textMarshalerType):
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: s.of(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.of(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.object(t, "json")
+		}
+		return &Schema{Ref: "#/components/schemas/" + s.name(t)}
+	}
+
+	return &Schema{}
+}
+
+// name registers a struct under components, types of two packages sharing a name are prefixed
+func (s *schemas) name(t reflect.Type) string {
+	if name, ok := s.names[t]; ok {
+		return name
+	}
+
+	name := t.Name()
+	if _, taken := s.byName[name]; taken {
+		name = strings.ReplaceAll(path.Base(t.PkgPath()), "-", "_") + "_" + name
+	}
+
+	s.names[t] = name
+	s.byName[name] = &Schema{}
+	*s.byName[name] = *s.object(t, "json")
+
+	return name
+}
+
+// object documents the exported fields of a struct, embedded structs are inlined as encoding/json does
+func (s *schemas) object(t reflect.Type, tag string) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for _, field := range s.fields(t, tag) {
+		schema.Properties[field.Name] = field.Schema
+		if field.Required {
+			schema.Required = append(schema.Required, field.Name)
+		}
+	}
+
+	return schema
+}
+
+// fields lists the documented fields of a struct named after tag, json for bodies and form for queries
+func (s *schemas) fields(t reflect.Type, tag string) []Field {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	fields := []Field{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct && f.Tag.Get(tag) == "" {
+			fields = append(fields, s.fields(f.Type, tag)...)
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+
+		name := strings.Split(f.Tag.Get(tag), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		schema := s.of(f.Type)
+		required := applyBinding(schema, f.Tag.Get("binding"))
+		fields = append(fields, Field{Name: name, Required: required, Schema: schema})
+	}
+
+	return fields
+}
+
+// applyBinding documents the validation rules of a binding tag and tells if the field is required
+func applyBinding(schema *Schema, binding string) bool {
+	required := false
+	notes := []string{}
+
+	for _, rule := range strings.Split(binding, ",") {
+		key, param := rule, ""
+		if i := strings.Index(rule, "="); i >= 0 {
+			key, param = rule[:i], rule[i+1:]
+		}
+
+		switch key {
+		case "required":
+			required = true
+		case "required_without":
+			notes = append(notes, "required when "+lowerFirst(param)+" is empty")
+		case "oneof":
+			schema.Enum = strings.Fields(param)
+		case "email":
+			schema.Format = "email"
+		case "isodate":
+			schema.Format = "date"
+		case "phone":
+			schema.Format = "phone"
+			notes = append(notes, "normalized to E.164")
+		case "idcard":
+			notes = append(notes, "national identity card number")
+		case "datetime":
+			notes = append(notes, "layout "+param)
+		case "numeric":
+			schema.Pattern = "^[0-9]+$"
+		case "eqfield":
+			notes = append(notes, "must equal "+lowerFirst(param))
+		case "min", "max", "len", "gte", "lte":
+			applyBound(schema, key, param)
+		}
+	}
+
+	schema.Description = strings.Join(notes, ", ")
+
+	return required
+}
+
+// applyBound maps min, max and len to the bound of the schema type
+func applyBound(schema *Schema, key string, param string) {
+	n, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+
+	lower := key == "min" || key == "gte" || key == "len"
+	upper := key == "max" || key == "lte" || key == "len"
+	size := int(n)
+
+	switch schema.Type {
+	case "integer", "number":
+		if lower {
+			schema.Minimum = &n
+		}
+		if upper {
+			schema.Maximum = &n
+		}
+	case "string":
+		if lower {
+			schema.MinLength = &size
+		}
+		if upper {
+			schema.MaxLength = &size
+		}
+	case "array":
+		if lower {
+			schema.MinItems = &size
+		}
+	}
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+
+	return strings.ToLower(s[:1]) + s[1:]
+}
@@ -0,0 +1,74 @@
+package openapi
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"mock-golang/pagination"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type testAddress struct {
+	City string `json:"city" binding:"required"`
+}
+
+type testRequest struct {
+	Email    string        `json:"email" binding:"required,email"`
+	Born     string        `json:"born" binding:"omitempty,isodate"`
+	Fare     string        `json:"fare" binding:"omitempty,oneof=economy business"`
+	Slot     int32         `json:"slot" binding:"required_without=Names,omitempty,min=1,max=9"`
+	Names    []string      `json:"names"`
+	At       time.Time     `json:"at"`
+	Address  *testAddress  `json:"address"`
+	Previous []testAddress `json:"previous"`
+	Hidden   string        `json:"-"`
+	internal string
+}
+
+type testQuery struct {
+	Name string `form:"name"`
+	pagination.Query
+}
+
+func TestSchemas(t *testing.T) {
+	s := newSchemas()
+
+	ref := s.of(reflect.TypeOf(&testRequest{}))
+	assert.Equal(t, "#/components/schemas/testRequest", ref.Ref)
+
+	schema := s.byName["testRequest"]
+	assert.Equal(t, []string{"email"}, schema.Required)
+	assert.Equal(t, "email", schema.Properties["email"].Format)
+	assert.Equal(t, "date", schema.Properties["born"].Format)
+	assert.Equal(t, []string{"economy", "business"}, schema.Properties["fare"].Enum)
+	assert.Equal(t, 1.0, *schema.Properties["slot"].Minimum)
+	assert.Equal(t, 9.0, *schema.Properties["slot"].Maximum)
+	assert.Equal(t, "required when names is empty", schema.Properties["slot"].Description)
+	assert.Equal(t, "date-time", schema.Properties["at"].Format)
+	assert.Equal(t, "#/components/schemas/testAddress", schema.Properties["address"].Ref)
+	assert.Equal(t, "#/components/schemas/testAddress", schema.Properties["previous"].Items.Ref)
+	assert.NotContains(t, schema.Properties, "Hidden")
+	assert.NotContains(t, schema.Properties, "internal")
+
+	names := []string{}
+	for _, field := range s.fields(reflect.TypeOf(testQuery{}), "form") {
+		names = append(names, field.Name)
+	}
+	assert.Equal(t, []string{"name", "pageSize", "pageToken", "orderBy"}, names)
+}
+
+func TestUndocumented(t *testing.T) {
+	routes := gin.RoutesInfo{{Method: "GET", Path: "/v1/api/flight/:id"}, {Method: "POST", Path: "/v1/api/flight"}}
+	operations := map[string]Operation{
+		"GET /v1/api/flight/:id": {Summary: "Get a flight"},
+		"PUT /v1/api/flight":     {Summary: "Update a flight"},
+	}
+
+	assert.Equal(t, []string{
+		"no operation for route POST /v1/api/flight",
+		"no route for operation PUT /v1/api/flight",
+	}, Undocumented(routes, operations))
+}
@@ -0,0 +1,187 @@
+package openapi
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// templateParam matches a variable of an http rule path, {name} or {name=pattern}
+var templateParam = regexp.MustCompile(`\{([\w.]+)(=[^}]*)?\}`)
+
+// transcoded documents the methods of service carrying a google.api.http option, as the REST proxy serves them:
+// the proto messages in JSON without the {status, payload} envelope
+func (s *schemas) transcoded(doc *Document, service protoreflect.ServiceDescriptor) {
+	methods := service.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		method := methods.Get(i)
+
+		rule, _ := proto.GetExtension(method.Options(), annotations.E_Http).(*annotations.HttpRule)
+		if rule == nil {
+			continue
+		}
+
+		httpMethod, template := httpPattern(rule)
+		if template == "" {
+			continue
+		}
+
+		path := templateParam.ReplaceAllString(template, "{$1}")
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = map[string]*PathItem{}
+		}
+		doc.Paths[path][strings.ToLower(httpMethod)] = s.transcodedItem(service, method, rule, template)
+	}
+}
+
+// httpPattern is the method and path template of an http rule
+func httpPattern(rule *annotations.HttpRule) (string, string) {
+	switch {
+	case rule.GetGet() != "":
+		return http.MethodGet, rule.GetGet()
+	case rule.GetPost() != "":
+		return http.MethodPost, rule.GetPost()
+	case rule.GetPut() != "":
+		return http.MethodPut, rule.GetPut()
+	case rule.GetPatch() != "":
+		return http.MethodPatch, rule.GetPatch()
+	case rule.GetDelete() != "":
+		return http.MethodDelete, rule.GetDelete()
+	}
+
+	return "", ""
+}
+
+func (s *schemas) transcodedItem(service protoreflect.ServiceDescriptor, method protoreflect.MethodDescriptor,
+	rule *annotations.HttpRule, template string) *PathItem {
+	item := &PathItem{
+		Summary:     string(service.Name()) + "." + string(method.Name()),
+		Description: "Transcoded to gRPC, the service checks the permissions",
+		Tags:        []string{"v2"},
+		Security:    []map[string][]string{{bearerAuth: {}}},
+		Responses: map[string]*Response{
+			"200": {
+				Description: http.StatusText(http.StatusOK),
+				Content:     map[string]*MediaType{"application/json": {Schema: s.message(method.Output())}},
+			},
+			"400":     errorResponse(http.StatusBadRequest),
+			"401":     errorResponse(http.StatusUnauthorized),
+			"403":     errorResponse(http.StatusForbidden),
+			"422":     errorResponse(http.StatusUnprocessableEntity),
+			"default": errorResponse(http.StatusInternalServerError),
+		},
+	}
+
+	input := method.Input()
+	inPath := map[string]bool{}
+	for _, match := range templateParam.FindAllStringSubmatch(template, -1) {
+		inPath[match[1]] = true
+		item.Parameters = append(item.Parameters, &Parameter{Name: match[1], In: "path", Required: true, Schema: &Schema{Type: "string"}})
+	}
+
+	switch rule.Body {
+	case "":
+		// The fields left out of the path are read from the query
+		fields := input.Fields()
+		for i := 0; i < fields.Len(); i++ {
+			field := fields.Get(i)
+			if inPath[string(field.Name())] || !queryable(field) {
+				continue
+			}
+			item.Parameters = append(item.Parameters, &Parameter{Name: field.JSONName(), In: "query", Schema: s.field(field)})
+		}
+	case "*":
+		item.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]*MediaType{"application/json": {Schema: s.message(input)}},
+		}
+	default:
+		if field := input.Fields().ByName(protoreflect.Name(rule.Body)); field != nil {
+			item.RequestBody = &RequestBody{
+				Required: true,
+				Content:  map[string]*MediaType{"application/json": {Schema: s.field(field)}},
+			}
+		}
+	}
+
+	return item
+}
+
+// queryable tells if the REST proxy reads the field from the query, scalars and timestamps only
+func queryable(field protoreflect.FieldDescriptor) bool {
+	if field.IsMap() {
+		return false
+	}
+	if field.Kind() == protoreflect.MessageKind {
+		return field.Message().FullName() == "google.protobuf.Timestamp"
+	}
+
+	return true
+}
+
+// message returns the schema of a proto message as protojson writes it, messages are referenced by their full name
+func (s *schemas) message(message protoreflect.MessageDescriptor) *Schema {
+	if message.FullName() == "google.protobuf.Timestamp" {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	name := string(message.FullName())
+	if _, ok := s.byName[name]; !ok {
+		schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+		// registered before the fields, a message may refer to itself
+		s.byName[name] = schema
+
+		fields := message.Fields()
+		for i := 0; i < fields.Len(); i++ {
+			schema.Properties[fields.Get(i).JSONName()] = s.field(fields.Get(i))
+		}
+	}
+
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+func (s *schemas) field(field protoreflect.FieldDescriptor) *Schema {
+	if field.IsMap() {
+		return &Schema{Type: "object", AdditionalProperties: s.kind(field.MapValue())}
+	}
+	if field.IsList() {
+		return &Schema{Type: "array", Items: s.kind(field)}
+	}
+
+	return s.kind(field)
+}
+
+// kind maps a proto field type to its protojson form, 64 bit integers are strings
+func (s *schemas) kind(field protoreflect.FieldDescriptor) *Schema {
+	switch field.Kind() {
+	case protoreflect.BoolKind:
+		return &Schema{Type: "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return &Schema{Type: "integer", Format: "int32"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return &Schema{Type: "string", Format: "int64"}
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return &Schema{Type: "number"}
+	case protoreflect.StringKind:
+		return &Schema{Type: "string"}
+	case protoreflect.BytesKind:
+		return &Schema{Type: "string", Format: "byte"}
+	case protoreflect.EnumKind:
+		values := field.Enum().Values()
+		names := make([]string, 0, values.Len())
+		for i := 0; i < values.Len(); i++ {
+			names = append(names, string(values.Get(i).Name()))
+		}
+		return &Schema{Type: "string", Enum: names}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return s.message(field.Message())
+	}
+
+	return &Schema{}
+}
@@ -0,0 +1,35 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// uiPage loads Swagger UI from its CDN, the gateway only serves the document
+const uiPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>%[1]s</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({url: %[2]q, dom_id: "#swagger-ui"});
+  </script>
+</body>
+</html>
+`
+
+// UIHandler serves the docs page reading the document at specURL
+func UIHandler(title string, specURL string) gin.HandlerFunc {
+	page := []byte(fmt.Sprintf(uiPage, title, specURL))
+
+	return func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", page)
+	}
+}
@@ -0,0 +1,28 @@
+package pagination
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Query holds the paging query parameters of the gateway search endpoints
+type Query struct {
+	PageSize  int32  `form:"pageSize" binding:"omitempty,min=1"`
+	PageToken string `form:"pageToken"`
+	OrderBy   string `form:"orderBy"`
+}
+
+// SetLinkHeader points the client at the next page, the same request with pageToken set
+func SetLinkHeader(c *gin.Context, nextPageToken string) {
+	if nextPageToken == "" {
+		return
+	}
+
+	next := *c.Request.URL
+	query := next.Query()
+	query.Set("pageToken", nextPageToken)
+	next.RawQuery = query.Encode()
+
+	c.Header("Link", fmt.Sprintf(`<%s>; rel="next"`, next.RequestURI()))
+}
@@ -0,0 +1,156 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"mock-golang/apperror"
+
+	"gorm.io/gorm"
+)
+
+// Page sizes of the search endpoints
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// Orders lists the fields a search can be ordered by, api name to sql column
+type Orders struct {
+	Default string
+	Columns map[string]string
+}
+
+// Order is a parsed order_by, ties are broken by the id in the same direction
+type Order struct {
+	Field  string
+	Column string
+	Desc   bool
+}
+
+func (o Order) String() string {
+	if o.Desc {
+		return o.Field + " desc"
+	}
+
+	return o.Field
+}
+
+// Cursor is the position after the last row of a page, it travels as the opaque page token
+type Cursor struct {
+	Order string      `json:"o"`
+	Key   interface{} `json:"k"`
+	Id    string      `json:"id"`
+}
+
+// Page is the slice of a search asked for, a nil page returns every row
+type Page struct {
+	Size  int
+	Order Order
+	After *Cursor
+}
+
+// NewPage validates the paging fields of a search request
+func NewPage(pageSize int32, pageToken string, orderBy string, orders Orders) (*Page, error) {
+	if pageSize < 0 {
+		return nil, invalid("pageSize", "page size must not be negative")
+	}
+
+	size := int(pageSize)
+	if size == 0 {
+		size = DefaultPageSize
+	}
+	if size > MaxPageSize {
+		size = MaxPageSize
+	}
+
+	order, err := orders.parse(orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &Page{Size: size, Order: order}
+	if pageToken == "" {
+		return page, nil
+	}
+
+	cursor := &Cursor{}
+	raw, err := base64.RawURLEncoding.DecodeString(pageToken)
+	if err != nil || json.Unmarshal(raw, cursor) != nil || cursor.Id == "" {
+		return nil, invalid("pageToken", "page token is invalid")
+	}
+	if cursor.Order != order.String() {
+		return nil, invalid("pageToken", "page token was issued for orderBy %q", cursor.Order)
+	}
+	page.After = cursor
+
+	return page, nil
+}
+
+// parse reads "field" or "field desc", an empty order_by is the default order
+func (o Orders) parse(orderBy string) (Order, error) {
+	orderBy = strings.TrimSpace(orderBy)
+	if orderBy == "" {
+		orderBy = o.Default
+	}
+
+	parts := strings.Fields(orderBy)
+	if len(parts) > 2 {
+		return Order{}, invalid("orderBy", "orderBy takes a single field")
+	}
+
+	column, ok := o.Columns[parts[0]]
+	if !ok {
+		return Order{}, invalid("orderBy", "can not order by %q", parts[0])
+	}
+
+	order := Order{Field: parts[0], Column: column}
+	if len(parts) == 2 {
+		switch strings.ToLower(parts[1]) {
+		case "asc":
+		case "desc":
+			order.Desc = true
+		default:
+			return Order{}, invalid("orderBy", "direction must be asc or desc")
+		}
+	}
+
+	return order, nil
+}
+
+// Apply orders the query, starts it after the cursor and fetches one row more than
+// the page size to know whether another page follows
+func (p *Page) Apply(db *gorm.DB, idColumn string) *gorm.DB {
+	if p == nil {
+		return db
+	}
+
+	direction, compare := "ASC", ">"
+	if p.Order.Desc {
+		direction, compare = "DESC", "<"
+	}
+
+	if p.After != nil {
+		db = db.Where(fmt.Sprintf("(%s, %s) %s (?, ?)", p.Order.Column, idColumn, compare), p.After.Key, p.After.Id)
+	}
+
+	return db.Order(fmt.Sprintf("%s %s, %s %s", p.Order.Column, direction, idColumn, direction)).Limit(p.Size + 1)
+}
+
+// HasNext tells if the rows fetched through Apply go past the page
+func (p *Page) HasNext(fetched int) bool {
+	return p != nil && fetched > p.Size
+}
+
+// NextToken is the page token following the row with this sort key and id
+func (p *Page) NextToken(key interface{}, id string) string {
+	raw, _ := json.Marshal(&Cursor{Order: p.Order.String(), Key: key, Id: id})
+
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func invalid(field string, format string, args ...interface{}) error {
+	return &apperror.Error{Kind: apperror.ErrInvalidArgument, Field: field, Message: fmt.Sprintf(format, args...)}
+}
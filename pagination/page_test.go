@@ -0,0 +1,78 @@
+package pagination
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"mock-golang/apperror"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+var testOrders = Orders{
+	Default: "departDate",
+	Columns: map[string]string{"departDate": "depart_date", "name": "flights"},
+}
+
+func TestNewPage(t *testing.T) {
+	page, err := NewPage(0, "", "", testOrders)
+	assert.Nil(t, err)
+	assert.Equal(t, DefaultPageSize, page.Size)
+	assert.Equal(t, Order{Field: "departDate", Column: "depart_date"}, page.Order)
+	assert.Nil(t, page.After)
+
+	page, err = NewPage(500, "", "name DESC", testOrders)
+	assert.Nil(t, err)
+	assert.Equal(t, MaxPageSize, page.Size)
+	assert.Equal(t, "name desc", page.Order.String())
+
+	for _, orderBy := range []string{"price", "name sideways", "name desc id"} {
+		_, err = NewPage(10, "", orderBy, testOrders)
+		assert.True(t, errors.Is(err, apperror.ErrInvalidArgument), orderBy)
+	}
+
+	_, err = NewPage(-1, "", "", testOrders)
+	assert.True(t, errors.Is(err, apperror.ErrInvalidArgument))
+}
+
+func TestPageToken(t *testing.T) {
+	page, _ := NewPage(2, "", "name desc", testOrders)
+	assert.False(t, page.HasNext(2))
+	assert.True(t, page.HasNext(3))
+
+	token := page.NextToken("VN123", "7f1c7c4e-3c4e-4d5e-9a43-0a1b2c3d4e5f")
+
+	next, err := NewPage(2, token, "name desc", testOrders)
+	assert.Nil(t, err)
+	assert.Equal(t, &Cursor{Order: "name desc", Key: "VN123", Id: "7f1c7c4e-3c4e-4d5e-9a43-0a1b2c3d4e5f"}, next.After)
+
+	// a token only continues the order it was issued for
+	_, err = NewPage(2, token, "name", testOrders)
+	assert.True(t, errors.Is(err, apperror.ErrInvalidArgument))
+
+	_, err = NewPage(2, "not a token", "name desc", testOrders)
+	assert.True(t, errors.Is(err, apperror.ErrInvalidArgument))
+
+	var nilPage *Page
+	assert.False(t, nilPage.HasNext(1000))
+}
+
+func TestSetLinkHeader(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/v1/api/flight/search?from=HAN&pageToken=old", nil)
+
+	SetLinkHeader(c, "abc")
+	assert.Equal(t, `</v1/api/flight/search?from=HAN&pageToken=abc>; rel="next"`, w.Header().Get("Link"))
+
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/v1/api/flight/search", nil)
+
+	SetLinkHeader(c, "")
+	assert.Empty(t, w.Header().Get("Link"))
+}
@@ -26,6 +26,9 @@ type RPCBookingClient interface {
 	CreateBooking(ctx context.Context, in *Booking, opts ...grpc.CallOption) (*Booking, error)
 	UpdateBooking(ctx context.Context, in *Booking, opts ...grpc.CallOption) (*Booking, error)
 	SearchBooking(ctx context.Context, in *SearchBookingRequest, opts ...grpc.CallOption) (*SearchBookingResponse, error)
+	ListPendingApprovals(ctx context.Context, in *PendingApprovalsRequest, opts ...grpc.CallOption) (*SearchBookingResponse, error)
+	ApproveBooking(ctx context.Context, in *BookingDecisionRequest, opts ...grpc.CallOption) (*Booking, error)
+	RejectBooking(ctx context.Context, in *BookingDecisionRequest, opts ...grpc.CallOption) (*Booking, error)
 }
 
 type rPCBookingClient struct {
@@ -72,6 +75,33 @@ func (c *rPCBookingClient) SearchBooking(ctx context.Context, in *SearchBookingR
 	return out, nil
 }
 
+func (c *rPCBookingClient) ListPendingApprovals(ctx context.Context, in *PendingApprovalsRequest, opts ...grpc.CallOption) (*SearchBookingResponse, error) {
+	out := new(SearchBookingResponse)
+	err := c.cc.Invoke(ctx, "/tuns_go_flight.RPCBooking/ListPendingApprovals", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rPCBookingClient) ApproveBooking(ctx context.Context, in *BookingDecisionRequest, opts ...grpc.CallOption) (*Booking, error) {
+	out := new(Booking)
+	err := c.cc.Invoke(ctx, "/tuns_go_flight.RPCBooking/ApproveBooking", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rPCBookingClient) RejectBooking(ctx context.Context, in *BookingDecisionRequest, opts ...grpc.CallOption) (*Booking, error) {
+	out := new(Booking)
+	err := c.cc.Invoke(ctx, "/tuns_go_flight.RPCBooking/RejectBooking", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RPCBookingServer is the server API for RPCBooking service.
 // All implementations must embed UnimplementedRPCBookingServer
 // for forward compatibility
@@ -80,6 +110,9 @@ type RPCBookingServer interface {
 	CreateBooking(context.Context, *Booking) (*Booking, error)
 	UpdateBooking(context.Context, *Booking) (*Booking, error)
 	SearchBooking(context.Context, *SearchBookingRequest) (*SearchBookingResponse, error)
+	ListPendingApprovals(context.Context, *PendingApprovalsRequest) (*SearchBookingResponse, error)
+	ApproveBooking(context.Context, *BookingDecisionRequest) (*Booking, error)
+	RejectBooking(context.Context, *BookingDecisionRequest) (*Booking, error)
 	mustEmbedUnimplementedRPCBookingServer()
 }
 
@@ -99,6 +132,15 @@ func (UnimplementedRPCBookingServer) UpdateBooking(context.Context, *Booking) (*
 func (UnimplementedRPCBookingServer) SearchBooking(context.Context, *SearchBookingRequest) (*SearchBookingResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SearchBooking not implemented")
 }
+func (UnimplementedRPCBookingServer) ListPendingApprovals(context.Context, *PendingApprovalsRequest) (*SearchBookingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPendingApprovals not implemented")
+}
+func (UnimplementedRPCBookingServer) ApproveBooking(context.Context, *BookingDecisionRequest) (*Booking, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveBooking not implemented")
+}
+func (UnimplementedRPCBookingServer) RejectBooking(context.Context, *BookingDecisionRequest) (*Booking, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RejectBooking not implemented")
+}
 func (UnimplementedRPCBookingServer) mustEmbedUnimplementedRPCBookingServer() {}
 
 // UnsafeRPCBookingServer may be embedded to opt out of forward compatibility for this service.
@@ -184,6 +226,60 @@ func _RPCBooking_SearchBooking_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RPCBooking_ListPendingApprovals_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PendingApprovalsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RPCBookingServer).ListPendingApprovals(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tuns_go_flight.RPCBooking/ListPendingApprovals",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RPCBookingServer).ListPendingApprovals(ctx, req.(*PendingApprovalsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RPCBooking_ApproveBooking_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BookingDecisionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RPCBookingServer).ApproveBooking(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tuns_go_flight.RPCBooking/ApproveBooking",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RPCBookingServer).ApproveBooking(ctx, req.(*BookingDecisionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RPCBooking_RejectBooking_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BookingDecisionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RPCBookingServer).RejectBooking(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tuns_go_flight.RPCBooking/RejectBooking",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RPCBookingServer).RejectBooking(ctx, req.(*BookingDecisionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // RPCBooking_ServiceDesc is the grpc.ServiceDesc for RPCBooking service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -207,6 +303,18 @@ var RPCBooking_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SearchBooking",
 			Handler:    _RPCBooking_SearchBooking_Handler,
 		},
+		{
+			MethodName: "ListPendingApprovals",
+			Handler:    _RPCBooking_ListPendingApprovals_Handler,
+		},
+		{
+			MethodName: "ApproveBooking",
+			Handler:    _RPCBooking_ApproveBooking_Handler,
+		},
+		{
+			MethodName: "RejectBooking",
+			Handler:    _RPCBooking_RejectBooking_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "rpc_booking.proto",
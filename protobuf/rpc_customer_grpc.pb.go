@@ -27,6 +27,18 @@ type RPCCustomerClient interface {
 	UpdateCustomer(ctx context.Context, in *Customer, opts ...grpc.CallOption) (*Customer, error)
 	ChangePassword(ctx context.Context, in *ChangePasswordRequest, opts ...grpc.CallOption) (*ChangePasswordResponse, error)
 	SearchCustomer(ctx context.Context, in *SearchCustomerRequest, opts ...grpc.CallOption) (*SearchCustomerResponse, error)
+	AssignRole(ctx context.Context, in *AssignRoleRequest, opts ...grpc.CallOption) (*Customer, error)
+	MergeCustomers(ctx context.Context, in *MergeCustomersRequest, opts ...grpc.CallOption) (*MergeCustomersResponse, error)
+	FindDuplicateCustomers(ctx context.Context, in *DuplicateCustomersRequest, opts ...grpc.CallOption) (*DuplicateCustomersResponse, error)
+	ListTravellers(ctx context.Context, in *CustomerParamId, opts ...grpc.CallOption) (*ListTravellersResponse, error)
+	CreateTraveller(ctx context.Context, in *Traveller, opts ...grpc.CallOption) (*Traveller, error)
+	UpdateTraveller(ctx context.Context, in *Traveller, opts ...grpc.CallOption) (*Traveller, error)
+	DeleteTraveller(ctx context.Context, in *TravellerParamId, opts ...grpc.CallOption) (*Traveller, error)
+	ExportCustomerData(ctx context.Context, in *ExportCustomerDataRequest, opts ...grpc.CallOption) (*ExportCustomerDataResponse, error)
+	EraseCustomer(ctx context.Context, in *EraseCustomerRequest, opts ...grpc.CallOption) (*EraseCustomerResponse, error)
+	DeactivateCustomer(ctx context.Context, in *DeactivateCustomerRequest, opts ...grpc.CallOption) (*Customer, error)
+	GetNotificationPreferences(ctx context.Context, in *CustomerParamId, opts ...grpc.CallOption) (*NotificationPreferences, error)
+	UpdateNotificationPreferences(ctx context.Context, in *NotificationPreferences, opts ...grpc.CallOption) (*NotificationPreferences, error)
 }
 
 type rPCCustomerClient struct {
@@ -82,6 +94,114 @@ func (c *rPCCustomerClient) SearchCustomer(ctx context.Context, in *SearchCustom
 	return out, nil
 }
 
+func (c *rPCCustomerClient) AssignRole(ctx context.Context, in *AssignRoleRequest, opts ...grpc.CallOption) (*Customer, error) {
+	out := new(Customer)
+	err := c.cc.Invoke(ctx, "/tuns_go_flight.RPCCustomer/AssignRole", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rPCCustomerClient) MergeCustomers(ctx context.Context, in *MergeCustomersRequest, opts ...grpc.CallOption) (*MergeCustomersResponse, error) {
+	out := new(MergeCustomersResponse)
+	err := c.cc.Invoke(ctx, "/tuns_go_flight.RPCCustomer/MergeCustomers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rPCCustomerClient) FindDuplicateCustomers(ctx context.Context, in *DuplicateCustomersRequest, opts ...grpc.CallOption) (*DuplicateCustomersResponse, error) {
+	out := new(DuplicateCustomersResponse)
+	err := c.cc.Invoke(ctx, "/tuns_go_flight.RPCCustomer/FindDuplicateCustomers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rPCCustomerClient) ListTravellers(ctx context.Context, in *CustomerParamId, opts ...grpc.CallOption) (*ListTravellersResponse, error) {
+	out := new(ListTravellersResponse)
+	err := c.cc.Invoke(ctx, "/tuns_go_flight.RPCCustomer/ListTravellers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rPCCustomerClient) CreateTraveller(ctx context.Context, in *Traveller, opts ...grpc.CallOption) (*Traveller, error) {
+	out := new(Traveller)
+	err := c.cc.Invoke(ctx, "/tuns_go_flight.RPCCustomer/CreateTraveller", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rPCCustomerClient) UpdateTraveller(ctx context.Context, in *Traveller, opts ...grpc.CallOption) (*Traveller, error) {
+	out := new(Traveller)
+	err := c.cc.Invoke(ctx, "/tuns_go_flight.RPCCustomer/UpdateTraveller", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rPCCustomerClient) DeleteTraveller(ctx context.Context, in *TravellerParamId, opts ...grpc.CallOption) (*Traveller, error) {
+	out := new(Traveller)
+	err := c.cc.Invoke(ctx, "/tuns_go_flight.RPCCustomer/DeleteTraveller", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rPCCustomerClient) ExportCustomerData(ctx context.Context, in *ExportCustomerDataRequest, opts ...grpc.CallOption) (*ExportCustomerDataResponse, error) {
+	out := new(ExportCustomerDataResponse)
+	err := c.cc.Invoke(ctx, "/tuns_go_flight.RPCCustomer/ExportCustomerData", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rPCCustomerClient) EraseCustomer(ctx context.Context, in *EraseCustomerRequest, opts ...grpc.CallOption) (*EraseCustomerResponse, error) {
+	out := new(EraseCustomerResponse)
+	err := c.cc.Invoke(ctx, "/tuns_go_flight.RPCCustomer/EraseCustomer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rPCCustomerClient) DeactivateCustomer(ctx context.Context, in *DeactivateCustomerRequest, opts ...grpc.CallOption) (*Customer, error) {
+	out := new(Customer)
+	err := c.cc.Invoke(ctx, "/tuns_go_flight.RPCCustomer/DeactivateCustomer", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rPCCustomerClient) GetNotificationPreferences(ctx context.Context, in *CustomerParamId, opts ...grpc.CallOption) (*NotificationPreferences, error) {
+	out := new(NotificationPreferences)
+	err := c.cc.Invoke(ctx, "/tuns_go_flight.RPCCustomer/GetNotificationPreferences", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rPCCustomerClient) UpdateNotificationPreferences(ctx context.Context, in *NotificationPreferences, opts ...grpc.CallOption) (*NotificationPreferences, error) {
+	out := new(NotificationPreferences)
+	err := c.cc.Invoke(ctx, "/tuns_go_flight.RPCCustomer/UpdateNotificationPreferences", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RPCCustomerServer is the server API for RPCCustomer service.
 // All implementations must embed UnimplementedRPCCustomerServer
 // for forward compatibility
@@ -91,6 +211,18 @@ type RPCCustomerServer interface {
 	UpdateCustomer(context.Context, *Customer) (*Customer, error)
 	ChangePassword(context.Context, *ChangePasswordRequest) (*ChangePasswordResponse, error)
 	SearchCustomer(context.Context, *SearchCustomerRequest) (*SearchCustomerResponse, error)
+	AssignRole(context.Context, *AssignRoleRequest) (*Customer, error)
+	MergeCustomers(context.Context, *MergeCustomersRequest) (*MergeCustomersResponse, error)
+	FindDuplicateCustomers(context.Context, *DuplicateCustomersRequest) (*DuplicateCustomersResponse, error)
+	ListTravellers(context.Context, *CustomerParamId) (*ListTravellersResponse, error)
+	CreateTraveller(context.Context, *Traveller) (*Traveller, error)
+	UpdateTraveller(context.Context, *Traveller) (*Traveller, error)
+	DeleteTraveller(context.Context, *TravellerParamId) (*Traveller, error)
+	ExportCustomerData(context.Context, *ExportCustomerDataRequest) (*ExportCustomerDataResponse, error)
+	EraseCustomer(context.Context, *EraseCustomerRequest) (*EraseCustomerResponse, error)
+	DeactivateCustomer(context.Context, *DeactivateCustomerRequest) (*Customer, error)
+	GetNotificationPreferences(context.Context, *CustomerParamId) (*NotificationPreferences, error)
+	UpdateNotificationPreferences(context.Context, *NotificationPreferences) (*NotificationPreferences, error)
 	mustEmbedUnimplementedRPCCustomerServer()
 }
 
@@ -113,6 +245,42 @@ func (UnimplementedRPCCustomerServer) ChangePassword(context.Context, *ChangePas
 func (UnimplementedRPCCustomerServer) SearchCustomer(context.Context, *SearchCustomerRequest) (*SearchCustomerResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SearchCustomer not implemented")
 }
+func (UnimplementedRPCCustomerServer) AssignRole(context.Context, *AssignRoleRequest) (*Customer, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AssignRole not implemented")
+}
+func (UnimplementedRPCCustomerServer) MergeCustomers(context.Context, *MergeCustomersRequest) (*MergeCustomersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MergeCustomers not implemented")
+}
+func (UnimplementedRPCCustomerServer) FindDuplicateCustomers(context.Context, *DuplicateCustomersRequest) (*DuplicateCustomersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindDuplicateCustomers not implemented")
+}
+func (UnimplementedRPCCustomerServer) ListTravellers(context.Context, *CustomerParamId) (*ListTravellersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTravellers not implemented")
+}
+func (UnimplementedRPCCustomerServer) CreateTraveller(context.Context, *Traveller) (*Traveller, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTraveller not implemented")
+}
+func (UnimplementedRPCCustomerServer) UpdateTraveller(context.Context, *Traveller) (*Traveller, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateTraveller not implemented")
+}
+func (UnimplementedRPCCustomerServer) DeleteTraveller(context.Context, *TravellerParamId) (*Traveller, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTraveller not implemented")
+}
+func (UnimplementedRPCCustomerServer) ExportCustomerData(context.Context, *ExportCustomerDataRequest) (*ExportCustomerDataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportCustomerData not implemented")
+}
+func (UnimplementedRPCCustomerServer) EraseCustomer(context.Context, *EraseCustomerRequest) (*EraseCustomerResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EraseCustomer not implemented")
+}
+func (UnimplementedRPCCustomerServer) DeactivateCustomer(context.Context, *DeactivateCustomerRequest) (*Customer, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeactivateCustomer not implemented")
+}
+func (UnimplementedRPCCustomerServer) GetNotificationPreferences(context.Context, *CustomerParamId) (*NotificationPreferences, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNotificationPreferences not implemented")
+}
+func (UnimplementedRPCCustomerServer) UpdateNotificationPreferences(context.Context, *NotificationPreferences) (*NotificationPreferences, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateNotificationPreferences not implemented")
+}
 func (UnimplementedRPCCustomerServer) mustEmbedUnimplementedRPCCustomerServer() {}
 
 // UnsafeRPCCustomerServer may be embedded to opt out of forward compatibility for this service.
@@ -216,6 +384,222 @@ func _RPCCustomer_SearchCustomer_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RPCCustomer_AssignRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AssignRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RPCCustomerServer).AssignRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tuns_go_flight.RPCCustomer/AssignRole",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RPCCustomerServer).AssignRole(ctx, req.(*AssignRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RPCCustomer_MergeCustomers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MergeCustomersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RPCCustomerServer).MergeCustomers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tuns_go_flight.RPCCustomer/MergeCustomers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RPCCustomerServer).MergeCustomers(ctx, req.(*MergeCustomersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RPCCustomer_FindDuplicateCustomers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DuplicateCustomersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RPCCustomerServer).FindDuplicateCustomers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tuns_go_flight.RPCCustomer/FindDuplicateCustomers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RPCCustomerServer).FindDuplicateCustomers(ctx, req.(*DuplicateCustomersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RPCCustomer_ListTravellers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CustomerParamId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RPCCustomerServer).ListTravellers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tuns_go_flight.RPCCustomer/ListTravellers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RPCCustomerServer).ListTravellers(ctx, req.(*CustomerParamId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RPCCustomer_CreateTraveller_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Traveller)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RPCCustomerServer).CreateTraveller(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tuns_go_flight.RPCCustomer/CreateTraveller",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RPCCustomerServer).CreateTraveller(ctx, req.(*Traveller))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RPCCustomer_UpdateTraveller_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Traveller)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RPCCustomerServer).UpdateTraveller(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tuns_go_flight.RPCCustomer/UpdateTraveller",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RPCCustomerServer).UpdateTraveller(ctx, req.(*Traveller))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RPCCustomer_DeleteTraveller_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TravellerParamId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RPCCustomerServer).DeleteTraveller(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tuns_go_flight.RPCCustomer/DeleteTraveller",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RPCCustomerServer).DeleteTraveller(ctx, req.(*TravellerParamId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RPCCustomer_ExportCustomerData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportCustomerDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RPCCustomerServer).ExportCustomerData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tuns_go_flight.RPCCustomer/ExportCustomerData",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RPCCustomerServer).ExportCustomerData(ctx, req.(*ExportCustomerDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RPCCustomer_EraseCustomer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EraseCustomerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RPCCustomerServer).EraseCustomer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tuns_go_flight.RPCCustomer/EraseCustomer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RPCCustomerServer).EraseCustomer(ctx, req.(*EraseCustomerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RPCCustomer_DeactivateCustomer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeactivateCustomerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RPCCustomerServer).DeactivateCustomer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tuns_go_flight.RPCCustomer/DeactivateCustomer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RPCCustomerServer).DeactivateCustomer(ctx, req.(*DeactivateCustomerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RPCCustomer_GetNotificationPreferences_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CustomerParamId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RPCCustomerServer).GetNotificationPreferences(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tuns_go_flight.RPCCustomer/GetNotificationPreferences",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RPCCustomerServer).GetNotificationPreferences(ctx, req.(*CustomerParamId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RPCCustomer_UpdateNotificationPreferences_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NotificationPreferences)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RPCCustomerServer).UpdateNotificationPreferences(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tuns_go_flight.RPCCustomer/UpdateNotificationPreferences",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RPCCustomerServer).UpdateNotificationPreferences(ctx, req.(*NotificationPreferences))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // RPCCustomer_ServiceDesc is the grpc.ServiceDesc for RPCCustomer service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -243,6 +627,54 @@ var RPCCustomer_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SearchCustomer",
 			Handler:    _RPCCustomer_SearchCustomer_Handler,
 		},
+		{
+			MethodName: "AssignRole",
+			Handler:    _RPCCustomer_AssignRole_Handler,
+		},
+		{
+			MethodName: "MergeCustomers",
+			Handler:    _RPCCustomer_MergeCustomers_Handler,
+		},
+		{
+			MethodName: "FindDuplicateCustomers",
+			Handler:    _RPCCustomer_FindDuplicateCustomers_Handler,
+		},
+		{
+			MethodName: "ListTravellers",
+			Handler:    _RPCCustomer_ListTravellers_Handler,
+		},
+		{
+			MethodName: "CreateTraveller",
+			Handler:    _RPCCustomer_CreateTraveller_Handler,
+		},
+		{
+			MethodName: "UpdateTraveller",
+			Handler:    _RPCCustomer_UpdateTraveller_Handler,
+		},
+		{
+			MethodName: "DeleteTraveller",
+			Handler:    _RPCCustomer_DeleteTraveller_Handler,
+		},
+		{
+			MethodName: "ExportCustomerData",
+			Handler:    _RPCCustomer_ExportCustomerData_Handler,
+		},
+		{
+			MethodName: "EraseCustomer",
+			Handler:    _RPCCustomer_EraseCustomer_Handler,
+		},
+		{
+			MethodName: "DeactivateCustomer",
+			Handler:    _RPCCustomer_DeactivateCustomer_Handler,
+		},
+		{
+			MethodName: "GetNotificationPreferences",
+			Handler:    _RPCCustomer_GetNotificationPreferences_Handler,
+		},
+		{
+			MethodName: "UpdateNotificationPreferences",
+			Handler:    _RPCCustomer_UpdateNotificationPreferences_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "rpc_customer.proto",
@@ -26,6 +26,7 @@ type RPCFlightClient interface {
 	CreateFlight(ctx context.Context, in *Flight, opts ...grpc.CallOption) (*Flight, error)
 	UpdateFlight(ctx context.Context, in *Flight, opts ...grpc.CallOption) (*Flight, error)
 	SearchFlight(ctx context.Context, in *SearchFlightRequest, opts ...grpc.CallOption) (*SearchFlightResponse, error)
+	DeleteFlight(ctx context.Context, in *FlightParamId, opts ...grpc.CallOption) (*Flight, error)
 }
 
 type rPCFlightClient struct {
@@ -72,6 +73,15 @@ func (c *rPCFlightClient) SearchFlight(ctx context.Context, in *SearchFlightRequ
 	return out, nil
 }
 
+func (c *rPCFlightClient) DeleteFlight(ctx context.Context, in *FlightParamId, opts ...grpc.CallOption) (*Flight, error) {
+	out := new(Flight)
+	err := c.cc.Invoke(ctx, "/tuns_go_flight.RPCFlight/DeleteFlight", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RPCFlightServer is the server API for RPCFlight service.
 // All implementations must embed UnimplementedRPCFlightServer
 // for forward compatibility
@@ -80,6 +90,7 @@ type RPCFlightServer interface {
 	CreateFlight(context.Context, *Flight) (*Flight, error)
 	UpdateFlight(context.Context, *Flight) (*Flight, error)
 	SearchFlight(context.Context, *SearchFlightRequest) (*SearchFlightResponse, error)
+	DeleteFlight(context.Context, *FlightParamId) (*Flight, error)
 	mustEmbedUnimplementedRPCFlightServer()
 }
 
@@ -99,6 +110,9 @@ func (UnimplementedRPCFlightServer) UpdateFlight(context.Context, *Flight) (*Fli
 func (UnimplementedRPCFlightServer) SearchFlight(context.Context, *SearchFlightRequest) (*SearchFlightResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SearchFlight not implemented")
 }
+func (UnimplementedRPCFlightServer) DeleteFlight(context.Context, *FlightParamId) (*Flight, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteFlight not implemented")
+}
 func (UnimplementedRPCFlightServer) mustEmbedUnimplementedRPCFlightServer() {}
 
 // UnsafeRPCFlightServer may be embedded to opt out of forward compatibility for this service.
@@ -184,6 +198,24 @@ func _RPCFlight_SearchFlight_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RPCFlight_DeleteFlight_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FlightParamId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RPCFlightServer).DeleteFlight(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tuns_go_flight.RPCFlight/DeleteFlight",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RPCFlightServer).DeleteFlight(ctx, req.(*FlightParamId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // RPCFlight_ServiceDesc is the grpc.ServiceDesc for RPCFlight service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -207,6 +239,10 @@ var RPCFlight_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SearchFlight",
 			Handler:    _RPCFlight_SearchFlight_Handler,
 		},
+		{
+			MethodName: "DeleteFlight",
+			Handler:    _RPCFlight_DeleteFlight_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "rpc_flight.proto",
@@ -0,0 +1,177 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.21.5
+// source: rpc_loyalty.proto
+
+package protobuf
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// RPCLoyaltyClient is the client API for RPCLoyalty service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type RPCLoyaltyClient interface {
+	GetBalance(ctx context.Context, in *LoyaltyParamId, opts ...grpc.CallOption) (*LoyaltyBalance, error)
+	GetStatement(ctx context.Context, in *StatementRequest, opts ...grpc.CallOption) (*StatementResponse, error)
+	RedeemPoints(ctx context.Context, in *RedeemPointsRequest, opts ...grpc.CallOption) (*RedeemPointsResponse, error)
+}
+
+type rPCLoyaltyClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRPCLoyaltyClient(cc grpc.ClientConnInterface) RPCLoyaltyClient {
+	return &rPCLoyaltyClient{cc}
+}
+
+func (c *rPCLoyaltyClient) GetBalance(ctx context.Context, in *LoyaltyParamId, opts ...grpc.CallOption) (*LoyaltyBalance, error) {
+	out := new(LoyaltyBalance)
+	err := c.cc.Invoke(ctx, "/tuns_go_flight.RPCLoyalty/GetBalance", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rPCLoyaltyClient) GetStatement(ctx context.Context, in *StatementRequest, opts ...grpc.CallOption) (*StatementResponse, error) {
+	out := new(StatementResponse)
+	err := c.cc.Invoke(ctx, "/tuns_go_flight.RPCLoyalty/GetStatement", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rPCLoyaltyClient) RedeemPoints(ctx context.Context, in *RedeemPointsRequest, opts ...grpc.CallOption) (*RedeemPointsResponse, error) {
+	out := new(RedeemPointsResponse)
+	err := c.cc.Invoke(ctx, "/tuns_go_flight.RPCLoyalty/RedeemPoints", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RPCLoyaltyServer is the server API for RPCLoyalty service.
+// All implementations must embed UnimplementedRPCLoyaltyServer
+// for forward compatibility
+type RPCLoyaltyServer interface {
+	GetBalance(context.Context, *LoyaltyParamId) (*LoyaltyBalance, error)
+	GetStatement(context.Context, *StatementRequest) (*StatementResponse, error)
+	RedeemPoints(context.Context, *RedeemPointsRequest) (*RedeemPointsResponse, error)
+	mustEmbedUnimplementedRPCLoyaltyServer()
+}
+
+// UnimplementedRPCLoyaltyServer must be embedded to have forward compatible implementations.
+type UnimplementedRPCLoyaltyServer struct {
+}
+
+func (UnimplementedRPCLoyaltyServer) GetBalance(context.Context, *LoyaltyParamId) (*LoyaltyBalance, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBalance not implemented")
+}
+func (UnimplementedRPCLoyaltyServer) GetStatement(context.Context, *StatementRequest) (*StatementResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatement not implemented")
+}
+func (UnimplementedRPCLoyaltyServer) RedeemPoints(context.Context, *RedeemPointsRequest) (*RedeemPointsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RedeemPoints not implemented")
+}
+func (UnimplementedRPCLoyaltyServer) mustEmbedUnimplementedRPCLoyaltyServer() {}
+
+// UnsafeRPCLoyaltyServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RPCLoyaltyServer will
+// result in compilation errors.
+type UnsafeRPCLoyaltyServer interface {
+	mustEmbedUnimplementedRPCLoyaltyServer()
+}
+
+func RegisterRPCLoyaltyServer(s grpc.ServiceRegistrar, srv RPCLoyaltyServer) {
+	s.RegisterService(&RPCLoyalty_ServiceDesc, srv)
+}
+
+func _RPCLoyalty_GetBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoyaltyParamId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RPCLoyaltyServer).GetBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tuns_go_flight.RPCLoyalty/GetBalance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RPCLoyaltyServer).GetBalance(ctx, req.(*LoyaltyParamId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RPCLoyalty_GetStatement_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RPCLoyaltyServer).GetStatement(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tuns_go_flight.RPCLoyalty/GetStatement",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RPCLoyaltyServer).GetStatement(ctx, req.(*StatementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RPCLoyalty_RedeemPoints_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RedeemPointsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RPCLoyaltyServer).RedeemPoints(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tuns_go_flight.RPCLoyalty/RedeemPoints",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RPCLoyaltyServer).RedeemPoints(ctx, req.(*RedeemPointsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// RPCLoyalty_ServiceDesc is the grpc.ServiceDesc for RPCLoyalty service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RPCLoyalty_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "tuns_go_flight.RPCLoyalty",
+	HandlerType: (*RPCLoyaltyServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetBalance",
+			Handler:    _RPCLoyalty_GetBalance_Handler,
+		},
+		{
+			MethodName: "GetStatement",
+			Handler:    _RPCLoyalty_GetStatement_Handler,
+		},
+		{
+			MethodName: "RedeemPoints",
+			Handler:    _RPCLoyalty_RedeemPoints_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "rpc_loyalty.proto",
+}
@@ -0,0 +1,249 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.21.5
+// source: rpc_organization.proto
+
+package protobuf
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// RPCOrganizationClient is the client API for RPCOrganization service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type RPCOrganizationClient interface {
+	FindById(ctx context.Context, in *OrganizationParamId, opts ...grpc.CallOption) (*Organization, error)
+	CreateOrganization(ctx context.Context, in *Organization, opts ...grpc.CallOption) (*Organization, error)
+	UpdateOrganization(ctx context.Context, in *Organization, opts ...grpc.CallOption) (*Organization, error)
+	SetMember(ctx context.Context, in *OrganizationMember, opts ...grpc.CallOption) (*OrganizationMember, error)
+	RemoveMember(ctx context.Context, in *OrganizationMember, opts ...grpc.CallOption) (*OrganizationMember, error)
+}
+
+type rPCOrganizationClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRPCOrganizationClient(cc grpc.ClientConnInterface) RPCOrganizationClient {
+	return &rPCOrganizationClient{cc}
+}
+
+func (c *rPCOrganizationClient) FindById(ctx context.Context, in *OrganizationParamId, opts ...grpc.CallOption) (*Organization, error) {
+	out := new(Organization)
+	err := c.cc.Invoke(ctx, "/tuns_go_flight.RPCOrganization/FindById", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rPCOrganizationClient) CreateOrganization(ctx context.Context, in *Organization, opts ...grpc.CallOption) (*Organization, error) {
+	out := new(Organization)
+	err := c.cc.Invoke(ctx, "/tuns_go_flight.RPCOrganization/CreateOrganization", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rPCOrganizationClient) UpdateOrganization(ctx context.Context, in *Organization, opts ...grpc.CallOption) (*Organization, error) {
+	out := new(Organization)
+	err := c.cc.Invoke(ctx, "/tuns_go_flight.RPCOrganization/UpdateOrganization", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rPCOrganizationClient) SetMember(ctx context.Context, in *OrganizationMember, opts ...grpc.CallOption) (*OrganizationMember, error) {
+	out := new(OrganizationMember)
+	err := c.cc.Invoke(ctx, "/tuns_go_flight.RPCOrganization/SetMember", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *rPCOrganizationClient) RemoveMember(ctx context.Context, in *OrganizationMember, opts ...grpc.CallOption) (*OrganizationMember, error) {
+	out := new(OrganizationMember)
+	err := c.cc.Invoke(ctx, "/tuns_go_flight.RPCOrganization/RemoveMember", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RPCOrganizationServer is the server API for RPCOrganization service.
+// All implementations must embed UnimplementedRPCOrganizationServer
+// for forward compatibility
+type RPCOrganizationServer interface {
+	FindById(context.Context, *OrganizationParamId) (*Organization, error)
+	CreateOrganization(context.Context, *Organization) (*Organization, error)
+	UpdateOrganization(context.Context, *Organization) (*Organization, error)
+	SetMember(context.Context, *OrganizationMember) (*OrganizationMember, error)
+	RemoveMember(context.Context, *OrganizationMember) (*OrganizationMember, error)
+	mustEmbedUnimplementedRPCOrganizationServer()
+}
+
+// UnimplementedRPCOrganizationServer must be embedded to have forward compatible implementations.
+type UnimplementedRPCOrganizationServer struct {
+}
+
+func (UnimplementedRPCOrganizationServer) FindById(context.Context, *OrganizationParamId) (*Organization, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindById not implemented")
+}
+func (UnimplementedRPCOrganizationServer) CreateOrganization(context.Context, *Organization) (*Organization, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateOrganization not implemented")
+}
+func (UnimplementedRPCOrganizationServer) UpdateOrganization(context.Context, *Organization) (*Organization, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateOrganization not implemented")
+}
+func (UnimplementedRPCOrganizationServer) SetMember(context.Context, *OrganizationMember) (*OrganizationMember, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetMember not implemented")
+}
+func (UnimplementedRPCOrganizationServer) RemoveMember(context.Context, *OrganizationMember) (*OrganizationMember, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveMember not implemented")
+}
+func (UnimplementedRPCOrganizationServer) mustEmbedUnimplementedRPCOrganizationServer() {}
+
+// UnsafeRPCOrganizationServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RPCOrganizationServer will
+// result in compilation errors.
+type UnsafeRPCOrganizationServer interface {
+	mustEmbedUnimplementedRPCOrganizationServer()
+}
+
+func RegisterRPCOrganizationServer(s grpc.ServiceRegistrar, srv RPCOrganizationServer) {
+	s.RegisterService(&RPCOrganization_ServiceDesc, srv)
+}
+
+func _RPCOrganization_FindById_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrganizationParamId)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RPCOrganizationServer).FindById(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tuns_go_flight.RPCOrganization/FindById",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RPCOrganizationServer).FindById(ctx, req.(*OrganizationParamId))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RPCOrganization_CreateOrganization_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Organization)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RPCOrganizationServer).CreateOrganization(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tuns_go_flight.RPCOrganization/CreateOrganization",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RPCOrganizationServer).CreateOrganization(ctx, req.(*Organization))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RPCOrganization_UpdateOrganization_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Organization)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RPCOrganizationServer).UpdateOrganization(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tuns_go_flight.RPCOrganization/UpdateOrganization",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RPCOrganizationServer).UpdateOrganization(ctx, req.(*Organization))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RPCOrganization_SetMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrganizationMember)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RPCOrganizationServer).SetMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tuns_go_flight.RPCOrganization/SetMember",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RPCOrganizationServer).SetMember(ctx, req.(*OrganizationMember))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RPCOrganization_RemoveMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrganizationMember)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RPCOrganizationServer).RemoveMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tuns_go_flight.RPCOrganization/RemoveMember",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RPCOrganizationServer).RemoveMember(ctx, req.(*OrganizationMember))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// RPCOrganization_ServiceDesc is the grpc.ServiceDesc for RPCOrganization service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RPCOrganization_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "tuns_go_flight.RPCOrganization",
+	HandlerType: (*RPCOrganizationServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "FindById",
+			Handler:    _RPCOrganization_FindById_Handler,
+		},
+		{
+			MethodName: "CreateOrganization",
+			Handler:    _RPCOrganization_CreateOrganization_Handler,
+		},
+		{
+			MethodName: "UpdateOrganization",
+			Handler:    _RPCOrganization_UpdateOrganization_Handler,
+		},
+		{
+			MethodName: "SetMember",
+			Handler:    _RPCOrganization_SetMember_Handler,
+		},
+		{
+			MethodName: "RemoveMember",
+			Handler:    _RPCOrganization_RemoveMember_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "rpc_organization.proto",
+}
@@ -0,0 +1,86 @@
+package rbac
+
+type Permission string
+
+const (
+	PermFlightRead         Permission = "flight:read"
+	PermFlightWrite        Permission = "flight:write"
+	PermBookingReadOwn     Permission = "booking:read:own"
+	PermBookingReadAny     Permission = "booking:read:any"
+	PermBookingWriteOwn    Permission = "booking:write:own"
+	PermBookingWriteAny    Permission = "booking:write:any"
+	PermCustomerReadOwn    Permission = "customer:read:own"
+	PermCustomerReadAny    Permission = "customer:read:any"
+	PermCustomerWriteOwn   Permission = "customer:write:own"
+	PermCustomerWriteAny   Permission = "customer:write:any"
+	PermRoleAssign         Permission = "role:assign"
+	PermAccountUnlock      Permission = "account:unlock"
+	PermCustomerMerge      Permission = "customer:merge"
+	PermLoyaltyReadOwn     Permission = "loyalty:read:own"
+	PermLoyaltyReadAny     Permission = "loyalty:read:any"
+	PermLoyaltyRedeemOwn   Permission = "loyalty:redeem:own"
+	PermLoyaltyRedeemAny   Permission = "loyalty:redeem:any"
+	PermCustomerExport     Permission = "customer:export"
+	PermCustomerErase      Permission = "customer:erase"
+	PermCustomerDelete     Permission = "customer:delete"
+	PermFlightDelete       Permission = "flight:delete"
+	PermDeletedRead        Permission = "deleted:read"
+	PermOrganizationManage Permission = "organization:manage"
+	PermPersonalDataRead   Permission = "personal-data:read"
+)
+
+var customerPermissions = []Permission{
+	PermFlightRead,
+	PermBookingReadOwn,
+	PermBookingWriteOwn,
+	PermCustomerReadOwn,
+	PermCustomerWriteOwn,
+	PermLoyaltyReadOwn,
+	PermLoyaltyRedeemOwn,
+}
+
+var rolePermissions = map[Role][]Permission{
+	RoleGuest:    {PermFlightRead},
+	RoleCustomer: customerPermissions,
+	RoleAgent: append([]Permission{
+		PermBookingReadAny,
+		PermBookingWriteAny,
+		PermCustomerReadAny,
+		PermLoyaltyReadAny,
+		PermLoyaltyRedeemAny,
+		PermOrganizationManage,
+	}, customerPermissions...),
+	RoleOps: {
+		PermFlightRead,
+		PermFlightWrite,
+		PermBookingReadAny,
+		PermCustomerReadAny,
+		PermLoyaltyReadAny,
+	},
+	RoleAdmin: {
+		PermFlightRead,
+		PermFlightWrite,
+		PermBookingReadOwn,
+		PermBookingReadAny,
+		PermBookingWriteOwn,
+		PermBookingWriteAny,
+		PermCustomerReadOwn,
+		PermCustomerReadAny,
+		PermCustomerWriteOwn,
+		PermCustomerWriteAny,
+		PermRoleAssign,
+		PermAccountUnlock,
+		PermCustomerMerge,
+		PermLoyaltyReadOwn,
+		PermLoyaltyReadAny,
+		PermLoyaltyRedeemOwn,
+		PermLoyaltyRedeemAny,
+		PermCustomerExport,
+		PermCustomerErase,
+		PermCustomerDelete,
+		PermFlightDelete,
+		PermDeletedRead,
+		PermOrganizationManage,
+		PermPersonalDataRead,
+	},
+}
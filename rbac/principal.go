@@ -0,0 +1,87 @@
+package rbac
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	AuthorizationHeader = "authorization"
+	ServiceKeyHeader    = "x-service-key"
+)
+
+// Principal is the caller of a request. System is the gateway itself
+// running an orchestration step (e.g. guest booking) on its own behalf.
+type Principal struct {
+	CustomerId string
+	Role       Role
+	System     bool
+}
+
+func (p Principal) Can(perm Permission) bool {
+	return p.System || p.Role.Can(perm)
+}
+
+func (p Principal) IsAuthenticated() bool {
+	return p.System || p.CustomerId != ""
+}
+
+type principalKey struct{}
+
+func NewContext(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the caller, anonymous callers are guests
+func FromContext(ctx context.Context) Principal {
+	if p, ok := ctx.Value(principalKey{}).(Principal); ok {
+		return p
+	}
+	return Principal{Role: RoleGuest}
+}
+
+// Require fails with Unauthenticated for guests and PermissionDenied otherwise
+func Require(ctx context.Context, perm Permission) error {
+	p := FromContext(ctx)
+	if p.Can(perm) {
+		return nil
+	}
+	return denied(p, perm)
+}
+
+// RequireOwnOrAny allows the owner with the own permission, everybody else needs the any permission
+func RequireOwnOrAny(ctx context.Context, ownerId string, own Permission, any Permission) error {
+	p := FromContext(ctx)
+	if p.Can(any) {
+		return nil
+	}
+	if p.CustomerId != "" && p.CustomerId == ownerId && p.Can(own) {
+		return nil
+	}
+	return denied(p, any)
+}
+
+// CanReadPersonalData lets the owner and callers with personal-data:read (admins) see
+// birth dates and document numbers, other readers get them masked
+func CanReadPersonalData(ctx context.Context, ownerId string) bool {
+	p := FromContext(ctx)
+	return p.Can(PermPersonalDataRead) || (p.CustomerId != "" && p.CustomerId == ownerId)
+}
+
+func denied(p Principal, perm Permission) error {
+	if !p.IsAuthenticated() {
+		return status.Errorf(codes.Unauthenticated, "login required for %s", perm)
+	}
+	return status.Errorf(codes.PermissionDenied, "missing permission %s", perm)
+}
+
+// ServiceContext marks an outgoing gRPC call as made by the gateway itself
+func ServiceContext(ctx context.Context) context.Context {
+	serviceKeyMu.RLock()
+	defer serviceKeyMu.RUnlock()
+
+	return metadata.AppendToOutgoingContext(ctx, ServiceKeyHeader, serviceKey)
+}
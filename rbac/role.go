@@ -0,0 +1,60 @@
+package rbac
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Role keeps the int values already stored in customers.role
+type Role int32
+
+const (
+	RoleGuest    Role = 0
+	RoleCustomer Role = 1
+	RoleAdmin    Role = 2
+	RoleAgent    Role = 3
+	RoleOps      Role = 4
+)
+
+var roleNames = map[Role]string{
+	RoleGuest:    "guest",
+	RoleCustomer: "customer",
+	RoleAdmin:    "admin",
+	RoleAgent:    "agent",
+	RoleOps:      "ops",
+}
+
+func (r Role) String() string {
+	if name, ok := roleNames[r]; ok {
+		return name
+	}
+	return fmt.Sprintf("role(%d)", int32(r))
+}
+
+func (r Role) IsValid() bool {
+	_, ok := roleNames[r]
+	return ok
+}
+
+func ParseRole(name string) (Role, error) {
+	for role, roleName := range roleNames {
+		if strings.EqualFold(roleName, strings.TrimSpace(name)) {
+			return role, nil
+		}
+	}
+	return RoleGuest, fmt.Errorf("unknown role %q", name)
+}
+
+// Can reports whether the role was granted the permission
+func (r Role) Can(p Permission) bool {
+	for _, granted := range rolePermissions[r] {
+		if granted == p {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Role) Permissions() []Permission {
+	return rolePermissions[r]
+}
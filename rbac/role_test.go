@@ -0,0 +1,57 @@
+package rbac
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRolePermissions(t *testing.T) {
+	assert.True(t, RoleGuest.Can(PermFlightRead))
+	assert.False(t, RoleGuest.Can(PermBookingReadOwn))
+	assert.False(t, RoleCustomer.Can(PermFlightWrite))
+	assert.True(t, RoleAgent.Can(PermBookingReadAny))
+	assert.True(t, RoleAgent.Can(PermBookingWriteOwn))
+	assert.True(t, RoleOps.Can(PermFlightWrite))
+	assert.False(t, RoleOps.Can(PermRoleAssign))
+	assert.True(t, RoleAdmin.Can(PermRoleAssign))
+	assert.False(t, Role(99).Can(PermFlightRead))
+}
+
+func TestParseRole(t *testing.T) {
+	role, err := ParseRole("Agent")
+	assert.Nil(t, err)
+	assert.Equal(t, RoleAgent, role)
+	assert.Equal(t, "agent", role.String())
+
+	_, err = ParseRole("root")
+	assert.NotNil(t, err)
+}
+
+func TestRequireOwnOrAny(t *testing.T) {
+	guest := context.Background()
+	err := RequireOwnOrAny(guest, "c1", PermBookingReadOwn, PermBookingReadAny)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	owner := NewContext(guest, Principal{CustomerId: "c1", Role: RoleCustomer})
+	assert.Nil(t, RequireOwnOrAny(owner, "c1", PermBookingReadOwn, PermBookingReadAny))
+
+	err = RequireOwnOrAny(owner, "c2", PermBookingReadOwn, PermBookingReadAny)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	agent := NewContext(guest, Principal{CustomerId: "a1", Role: RoleAgent})
+	assert.Nil(t, RequireOwnOrAny(agent, "c2", PermBookingReadOwn, PermBookingReadAny))
+
+	system := NewContext(guest, Principal{System: true})
+	assert.Nil(t, Require(system, PermRoleAssign))
+}
+
+func TestCanReadPersonalData(t *testing.T) {
+	assert.False(t, CanReadPersonalData(context.Background(), "c1"))
+	assert.True(t, CanReadPersonalData(NewContext(context.Background(), Principal{CustomerId: "c1", Role: RoleCustomer}), "c1"))
+	assert.False(t, CanReadPersonalData(NewContext(context.Background(), Principal{CustomerId: "a1", Role: RoleAgent}), "c1"))
+	assert.True(t, CanReadPersonalData(NewContext(context.Background(), Principal{CustomerId: "x1", Role: RoleAdmin}), "c1"))
+}
@@ -0,0 +1,37 @@
+package rbac
+
+import (
+	"errors"
+	"os"
+	"sync"
+)
+
+// ServiceKeyEnv holds the shared secret the gateway sends for calls made on its own behalf (guest booking)
+const ServiceKeyEnv = "AUTH_SERVICE_KEY"
+
+// placeholders that were once committed to the config, never accepted as a key
+var defaultServiceKeys = map[string]bool{
+	"change-me-local-service-key": true,
+}
+
+var (
+	serviceKeyMu sync.RWMutex
+	serviceKey   string
+)
+
+// LoadServiceKey reads the key from AUTH_SERVICE_KEY, both binaries refuse to start without a real one
+func LoadServiceKey() (string, error) {
+	key := os.Getenv(ServiceKeyEnv)
+	if key == "" {
+		return "", errors.New(ServiceKeyEnv + " is not set")
+	}
+	if defaultServiceKeys[key] {
+		return "", errors.New(ServiceKeyEnv + " is left at a default value")
+	}
+
+	serviceKeyMu.Lock()
+	serviceKey = key
+	serviceKeyMu.Unlock()
+
+	return key, nil
+}
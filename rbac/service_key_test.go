@@ -0,0 +1,27 @@
+package rbac
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestLoadServiceKey(t *testing.T) {
+	t.Setenv(ServiceKeyEnv, "")
+	_, err := LoadServiceKey()
+	assert.NotNil(t, err)
+
+	t.Setenv(ServiceKeyEnv, "change-me-local-service-key")
+	_, err = LoadServiceKey()
+	assert.NotNil(t, err)
+
+	t.Setenv(ServiceKeyEnv, "s3cr3t-from-the-vault")
+	key, err := LoadServiceKey()
+	assert.Nil(t, err)
+	assert.Equal(t, "s3cr3t-from-the-vault", key)
+
+	md, _ := metadata.FromOutgoingContext(ServiceContext(context.Background()))
+	assert.Equal(t, []string{"s3cr3t-from-the-vault"}, md.Get(ServiceKeyHeader))
+}
@@ -0,0 +1,85 @@
+package rules
+
+import (
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+
+	"mock-golang/helper"
+
+	"github.com/spf13/viper"
+)
+
+// DateLayout is the ISO 8601 calendar date every API date field uses
+const DateLayout = "2006-01-02"
+
+// defaultCountry applies when no country is given and customer.default_country is not set
+const defaultCountry = "VN"
+
+// idCardFormats are the national identity card numbers we accept, keyed by ISO 3166 alpha-2 code
+var idCardFormats = map[string]*regexp.Regexp{
+	// 9 digit CMND or 12 digit CCCD
+	"VN": regexp.MustCompile(`^(\d{9}|\d{12})$`),
+	"TH": regexp.MustCompile(`^\d{13}$`),
+	"SG": regexp.MustCompile(`^[STFGM]\d{7}[A-Z]$`),
+	"MY": regexp.MustCompile(`^\d{6}-?\d{2}-?\d{4}$`),
+	"ID": regexp.MustCompile(`^\d{16}$`),
+	"CN": regexp.MustCompile(`^\d{17}[\dX]$`),
+}
+
+// genericIdCard is used for the countries without a known format
+var genericIdCard = regexp.MustCompile(`^[A-Z0-9]{5,20}$`)
+
+// Phone accepts numbers that normalize to E.164, local numbers get customer.default_country_code
+func Phone(value string) error {
+	_, err := helper.NormalizePhone(value, viper.GetString("customer.default_country_code"))
+	return err
+}
+
+// ISODate accepts a YYYY-MM-DD calendar date
+func ISODate(value string) error {
+	if _, err := time.Parse(DateLayout, value); err != nil {
+		return fmt.Errorf("date %q must be YYYY-MM-DD", value)
+	}
+
+	return nil
+}
+
+// Email accepts a bare address, display names ("Name <a@b.c>") are refused
+func Email(value string) error {
+	address, err := mail.ParseAddress(value)
+	if err != nil || address.Address != value || !strings.Contains(value[strings.LastIndex(value, "@"):], ".") {
+		return fmt.Errorf("email %q is invalid", value)
+	}
+
+	return nil
+}
+
+// IdCard checks the identity card number against the format of the country,
+// an empty country means customer.default_country
+func IdCard(country string, value string) error {
+	if country == "" {
+		country = DefaultCountry()
+	}
+
+	format, ok := idCardFormats[strings.ToUpper(country)]
+	if !ok {
+		format = genericIdCard
+	}
+
+	if !format.MatchString(strings.ToUpper(value)) {
+		return fmt.Errorf("identity card %q is not valid for %s", value, strings.ToUpper(country))
+	}
+
+	return nil
+}
+
+func DefaultCountry() string {
+	if country := viper.GetString("customer.default_country"); country != "" {
+		return strings.ToUpper(country)
+	}
+
+	return defaultCountry
+}
@@ -0,0 +1,31 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRules(t *testing.T) {
+	viper.Set("customer.default_country_code", "84")
+
+	assert.Nil(t, Phone("0912 345 678"))
+	assert.Nil(t, Phone("+6591234567"))
+	assert.NotNil(t, Phone("12ab"))
+
+	assert.Nil(t, ISODate("1990-02-28"))
+	assert.NotNil(t, ISODate("1990-02-30"))
+	assert.NotNil(t, ISODate("1990-02-28T00:00:00.000Z"))
+
+	assert.Nil(t, Email("an@example.com"))
+	assert.NotNil(t, Email("An <an@example.com>"))
+	assert.NotNil(t, Email("an@localhost"))
+
+	assert.Nil(t, IdCard("", "001099012345"))
+	assert.Nil(t, IdCard("VN", "123456789"))
+	assert.NotNil(t, IdCard("VN", "12345"))
+	assert.NotNil(t, IdCard("VN", ""))
+	assert.Nil(t, IdCard("SG", "S1234567D"))
+	assert.Nil(t, IdCard("FR", "X4RTBPFW4"))
+}
@@ -5,27 +5,35 @@ CREATE TABLE "flights" (
   "departure_airport" varchar(20) NOT NULL,	--departure_airport
   "departure_arrival" varchar(20) NOT NULL,	--departure_arrival
   "depart_date" timestamptz NOT NULL,	--flight_date
-  "status" varchar(10) NOT NULL,	--status	(1: active, 0: not_active)
+  "status" varchar(10) NOT NULL,	--status	(1: active, 0: not_active, Completed: landed, bookings earn loyalty points)
   "available_slot" int NOT NULL,	-- number of slot available
   "created_at" timestamptz NOT NULL DEFAULT 'now()',
-  "updated_at" timestamptz NOT NULL DEFAULT 'now()'
+  "updated_at" timestamptz NOT NULL DEFAULT 'now()',
+  "deleted_at" timestamptz	--soft delete, bookings still reference the flight
 );
 
 --// customer table
 CREATE TABLE "customers" (
   "id" varchar PRIMARY KEY,	--ID
-  "role" int,	--Role (0: not_register, 1: registed, 2: Admin)
+  "role" int,	--Role (0: guest, 1: customer, 2: admin, 3: agent, 4: ops), see package rbac
   "customer_name" varchar(200) NOT NULL,	--customer_name
   "email" varchar(200) NOT NULL,	--Email
-  "phone_number" varchar(20) NOT NULL,	--SĐT
-  "date_of_bith" varchar(20) NOT NULL,	-- Ngày sinh
-  "identity_card" varchar(20) NOT NULL,	--identity_card
-  "address" varchar(200) NOT NULL,	--address
+  "phone_number" varchar(20) NOT NULL,	--SĐT, E.164 (+84912345678)
+  "date_of_bith" text NOT NULL,	-- Ngày sinh (encrypted)
+  "identity_card" text NOT NULL,	--identity_card (encrypted)
+  "identity_card_index" varchar(64),	--blind index (HMAC) of identity_card for equality search
+  "address" text NOT NULL,	--address (encrypted)
   "membership_card"  varchar(20),	--membership_card
   "password" varchar(200),	--Password
   "status" int,	--status (0: inactive, 1: Active)
   "created_at" timestamptz NOT NULL DEFAULT 'now()',
-  "updated_at" timestamptz NOT NULL DEFAULT 'now()'
+  "updated_at" timestamptz NOT NULL DEFAULT 'now()',
+  "email_verified_at" timestamptz,	--null until the email is verified
+  "phone_verified_at" timestamptz,	--null until the phone OTP is verified
+  "merged_into_id" varchar,	--set when this duplicate was merged into another customer
+  "loyalty_tier" varchar(20),	--copy of loyalty_accounts.tier, written by the loyalty service through the customer repository
+  "erased_at" timestamptz,	--set when the personal data was erased on request
+  "deleted_at" timestamptz	--soft delete, set when the customer was deactivated
 );
 
 
@@ -36,12 +44,203 @@ CREATE TABLE "bookings" (
   "flight_id" varchar NOT NULL,	--flight_id
   "flight_number" varchar(20) NOT NULL,	--number flight
   "booked_slot" int,	-- Số ghế booking
-  "status" varchar(10) NOT NULL,	-- status  booking
+  "status" varchar(20) NOT NULL,	-- status  booking (Active, Cancel, PendingApproval, Rejected)
   "booked_date" timestamp NOT NULL DEFAULT 'now()',
+  "fare_class" varchar(20),	--economy, premium_economy, business, first
+  "organization_id" varchar,	--organization of the customer at booking time
+  "policy_violation" text,	--travel policy rules broken, the booking waits for approval
+  "approver_id" varchar,
+  "decided_at" timestamptz,
+  "decision_comment" text,
+  "created_at" timestamptz NOT NULL DEFAULT 'now()',
+  "updated_at" timestamptz NOT NULL DEFAULT 'now()'
+);
+
+--// corporate accounts, the travel policy applies to the bookings of every member
+CREATE TABLE "organizations" (
+  "id" varchar PRIMARY KEY,
+  "name" varchar(200) NOT NULL,
+  "max_fare_class" varchar(20),	--highest fare class allowed, empty for no limit
+  "advance_purchase_days" int NOT NULL DEFAULT 0,	--0 disables the rule
   "created_at" timestamptz NOT NULL DEFAULT 'now()',
   "updated_at" timestamptz NOT NULL DEFAULT 'now()'
 );
 
+--// a customer belongs to one organization at most
+CREATE TABLE "organization_members" (
+  "customer_id" varchar PRIMARY KEY,
+  "organization_id" varchar NOT NULL,
+  "role" varchar(20) NOT NULL,	--traveller, booker, approver
+  "created_at" timestamptz NOT NULL DEFAULT 'now()',
+  "updated_at" timestamptz NOT NULL DEFAULT 'now()'
+);
+
+--// travellers a customer saved to book for again (family, colleagues)
+CREATE TABLE "saved_travellers" (
+  "id" varchar PRIMARY KEY,
+  "customer_id" varchar NOT NULL,
+  "name" varchar(200) NOT NULL,
+  "date_of_birth" text NOT NULL,	--encrypted
+  "document_type" varchar(20) NOT NULL,	--passport, id_card
+  "document_number" text NOT NULL,	--encrypted
+  "document_expiry" date NOT NULL,
+  "nationality" varchar(2) NOT NULL,	--ISO 3166 alpha-2
+  "created_at" timestamptz NOT NULL DEFAULT 'now()',
+  "updated_at" timestamptz NOT NULL DEFAULT 'now()'
+);
+
+--// notification channels and categories of a customer, defaults apply until the first save
+CREATE TABLE "notification_preferences" (
+  "customer_id" varchar PRIMARY KEY,
+  "email_enabled" boolean NOT NULL,
+  "sms_enabled" boolean NOT NULL,
+  "push_enabled" boolean NOT NULL,
+  "booking_updates" boolean NOT NULL,
+  "flight_changes" boolean NOT NULL,
+  "promotions" boolean NOT NULL,	--opt in only
+  "language" varchar(5) NOT NULL,	--vi, en
+  "created_at" timestamptz NOT NULL DEFAULT 'now()',
+  "updated_at" timestamptz NOT NULL DEFAULT 'now()'
+);
+
+--// append only history of every consent granted or withdrawn
+CREATE TABLE "consent_records" (
+  "id" varchar PRIMARY KEY,
+  "customer_id" varchar NOT NULL,
+  "purpose" varchar(30) NOT NULL,	--email, sms, booking_updates, flight_changes, promotions
+  "granted" boolean NOT NULL,
+  "actor_id" varchar,	--customer or agent that made the change
+  "ip" varchar(64),
+  "created_at" timestamptz NOT NULL DEFAULT 'now()'
+);
+
+--// passengers of a booking, copied from the saved traveller or given inline at booking time
+CREATE TABLE "booking_passengers" (
+  "id" varchar PRIMARY KEY,
+  "booking_id" varchar NOT NULL,
+  "traveller_id" varchar,	--saved traveller the data was copied from, if any
+  "name" varchar(200) NOT NULL,
+  "date_of_birth" text NOT NULL,	--encrypted
+  "document_type" varchar(20) NOT NULL,
+  "document_number" text NOT NULL,	--encrypted
+  "document_expiry" date NOT NULL,
+  "nationality" varchar(2) NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT 'now()'
+);
+
+CREATE INDEX ON "customers" ("identity_card_index");
+CREATE INDEX ON "saved_travellers" ("customer_id");
+CREATE INDEX ON "booking_passengers" ("booking_id");
+CREATE INDEX ON "consent_records" ("customer_id");
+CREATE INDEX ON "customers" ("merged_into_id");
+CREATE INDEX ON "customers" ("deleted_at");
+CREATE INDEX ON "flights" ("deleted_at");
+CREATE INDEX ON "bookings" ("organization_id");
+-- Keyset pages of the searches
+CREATE INDEX ON "flights" ("depart_date", "id");
+CREATE INDEX ON "bookings" ("booked_date", "id");
+CREATE INDEX ON "customers" ("customer_name", "id");
+CREATE INDEX ON "organization_members" ("organization_id");
+
+--// registered (non guest), not merged, not deactivated customers can not share email, phone or identity card
+CREATE UNIQUE INDEX "customers_email_key" ON "customers" (LOWER(TRIM("email")))
+  WHERE "role" <> 0 AND COALESCE("merged_into_id", '') = '' AND "deleted_at" IS NULL AND LOWER(TRIM("email")) <> '';
+CREATE UNIQUE INDEX "customers_phone_number_key" ON "customers" ("phone_number")
+  WHERE "role" <> 0 AND COALESCE("merged_into_id", '') = '' AND "deleted_at" IS NULL AND "phone_number" <> '';
+CREATE UNIQUE INDEX "customers_identity_card_key" ON "customers" ("identity_card_index")
+  WHERE "role" <> 0 AND COALESCE("merged_into_id", '') = '' AND "deleted_at" IS NULL AND "identity_card_index" <> '';
+
+--// customer search: partial, case and accent insensitive name, email prefix, phone suffix
+CREATE EXTENSION IF NOT EXISTS unaccent;
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE OR REPLACE FUNCTION immutable_unaccent(text) RETURNS text
+  AS $$ SELECT public.unaccent('public.unaccent', $1) $$
+  LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT;
+CREATE INDEX "customers_name_trgm_idx" ON "customers" USING gin (immutable_unaccent(LOWER("customer_name")) gin_trgm_ops);
+CREATE INDEX "customers_email_prefix_idx" ON "customers" (LOWER("email") text_pattern_ops);
+CREATE INDEX "customers_email_normalized_idx" ON "customers" (LOWER(TRIM("email")));
+CREATE INDEX "customers_phone_trgm_idx" ON "customers" USING gin ("phone_number" gin_trgm_ops);
+
 ALTER TABLE "bookings" ADD FOREIGN KEY ("customer_id") REFERENCES "customers" ("id");
 
 ALTER TABLE "bookings" ADD FOREIGN KEY ("flight_id") REFERENCES "flights" ("id");
+
+ALTER TABLE "saved_travellers" ADD FOREIGN KEY ("customer_id") REFERENCES "customers" ("id");
+
+ALTER TABLE "booking_passengers" ADD FOREIGN KEY ("booking_id") REFERENCES "bookings" ("id");
+
+ALTER TABLE "organization_members" ADD FOREIGN KEY ("organization_id") REFERENCES "organizations" ("id");
+
+--// auth tokens (access, refresh, password_reset), only the sha256 hash is stored
+CREATE TABLE "auth_tokens" (
+  "id" varchar PRIMARY KEY,
+  "customer_id" varchar NOT NULL,	--customer_id
+  "kind" varchar(20) NOT NULL,	--access, refresh, password_reset, email_verification, phone_otp, account_claim
+  "token_hash" varchar(64) NOT NULL,	--sha256 of the token (of customer_id:code for OTP)
+  "target" varchar(200),	--email or phone a verification was sent to
+  "expires_at" timestamptz NOT NULL,
+  "used_at" timestamptz,	--single-use tokens are consumed once
+  "revoked_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT 'now()',
+  "updated_at" timestamptz NOT NULL DEFAULT 'now()'
+);
+
+CREATE INDEX ON "auth_tokens" ("customer_id");
+CREATE INDEX ON "auth_tokens" ("token_hash");
+
+ALTER TABLE "auth_tokens" ADD FOREIGN KEY ("customer_id") REFERENCES "customers" ("id");
+
+
+--// failed password checks per account ("account:<id>") or client ip ("ip:<addr>")
+CREATE TABLE "login_throttles" (
+  "key" varchar PRIMARY KEY,
+  "failures" int NOT NULL DEFAULT 0,
+  "last_failure_at" timestamptz NOT NULL,
+  "blocked_until" timestamptz,	--exponential backoff or lockout end
+  "locked_at" timestamptz,	--set when the lockout threshold was reached
+  "created_at" timestamptz NOT NULL DEFAULT 'now()',
+  "updated_at" timestamptz NOT NULL DEFAULT 'now()'
+);
+
+--// audit trail (lockouts, unlocks, ...), append only
+CREATE TABLE "audit_logs" (
+  "id" varchar PRIMARY KEY,
+  "actor_id" varchar,	--empty when the system acted
+  "action" varchar(50) NOT NULL,
+  "target_type" varchar(20) NOT NULL,	--customer, ip
+  "target_id" varchar NOT NULL,
+  "detail" text,
+  "ip" varchar(50),
+  "created_at" timestamptz NOT NULL DEFAULT 'now()'
+);
+
+CREATE INDEX ON "audit_logs" ("target_id");
+
+--// loyalty balance and tier (Silver, Gold, Platinum) per customer
+CREATE TABLE "loyalty_accounts" (
+  "customer_id" varchar PRIMARY KEY,
+  "balance" bigint NOT NULL DEFAULT 0,	--may go negative when spent points are reversed
+  "qualifying_points" bigint NOT NULL DEFAULT 0,	--accruals of the last 12 months, not reversed
+  "tier" varchar(20) NOT NULL,
+  "tier_updated_at" timestamptz NOT NULL,
+  "created_at" timestamptz NOT NULL DEFAULT 'now()',
+  "updated_at" timestamptz NOT NULL DEFAULT 'now()'
+);
+
+ALTER TABLE "loyalty_accounts" ADD FOREIGN KEY ("customer_id") REFERENCES "customers" ("id");
+
+--// loyalty ledger, append only
+CREATE TABLE "loyalty_transactions" (
+  "id" varchar PRIMARY KEY,
+  "customer_id" varchar NOT NULL,
+  "booking_id" varchar,	--empty for redemptions
+  "kind" varchar(20) NOT NULL,	--accrual, reversal, redemption
+  "points" bigint NOT NULL,	--negative for reversal and redemption
+  "description" text,
+  "request_id" varchar,	--idempotency key of a redemption, chosen by the client
+  "created_at" timestamptz NOT NULL DEFAULT 'now()'
+);
+
+CREATE INDEX ON "loyalty_transactions" ("customer_id");
+CREATE UNIQUE INDEX "loyalty_transactions_booking_kind_key" ON "loyalty_transactions" ("booking_id", "kind") WHERE "booking_id" <> '';
+CREATE UNIQUE INDEX "loyalty_transactions_request_key" ON "loyalty_transactions" ("customer_id", "request_id") WHERE "request_id" <> '';
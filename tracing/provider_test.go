@@ -0,0 +1,37 @@
+package tracing
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+)
+
+func TestInit(t *testing.T) {
+	t.Cleanup(func() {
+		viper.Set("tracing.exporter", nil)
+		viper.Set("tracing.file_path", nil)
+	})
+
+	viper.Set("tracing.exporter", "jaeger")
+	_, err := Init("test")
+	assert.NotNil(t, err)
+
+	viper.Set("tracing.exporter", "none")
+	shutdown, err := Init("test")
+	assert.Nil(t, err)
+	assert.Nil(t, shutdown(context.Background()))
+
+	viper.Set("tracing.exporter", "file")
+	viper.Set("tracing.file_path", filepath.Join(t.TempDir(), "traces.log"))
+	shutdown, err = Init("test")
+	assert.Nil(t, err)
+
+	_, span := otel.Tracer("test").Start(context.Background(), "span")
+	assert.True(t, span.SpanContext().IsSampled())
+	span.End()
+	assert.Nil(t, shutdown(context.Background()))
+}
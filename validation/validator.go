@@ -0,0 +1,211 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"mock-golang/apperror"
+	"mock-golang/rules"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError is one entry of a validation error response
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Custom tags, registered on gin's validator when the package is imported. The rules
+// themselves live in package rules, the gRPC services check them without gin:
+//
+//	phone      a phone number that normalizes to E.164
+//	isodate    a YYYY-MM-DD date
+//	idcard     an identity card number, idcard=SG checks another country than customer.default_country
+//	email      overrides the built-in tag, a bare address without display name
+func init() {
+	engine, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+
+	if err := Register(engine); err != nil {
+		panic(err)
+	}
+}
+
+// Register adds the custom tags to v and reports fields under their json (or form) name
+func Register(v *validator.Validate) error {
+	v.RegisterTagNameFunc(fieldName)
+
+	rules := map[string]func(value string, param string) error{
+		"phone":   func(value string, _ string) error { return rules.Phone(value) },
+		"isodate": func(value string, _ string) error { return rules.ISODate(value) },
+		"email":   func(value string, _ string) error { return rules.Email(value) },
+		"idcard":  func(value string, param string) error { return rules.IdCard(param, value) },
+	}
+
+	for tag, rule := range rules {
+		rule := rule
+		err := v.RegisterValidation(tag, func(fl validator.FieldLevel) bool {
+			return rule(fl.Field().String(), fl.Param()) == nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name := strings.Split(field.Tag.Get(tag), ",")[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+
+	return field.Name
+}
+
+// AbortWithBindError answers a failed ShouldBind: 400 when the body can not be decoded,
+// 422 with one entry per field when it breaks the validation rules
+func AbortWithBindError(c *gin.Context, err error) {
+	var validateErrors validator.ValidationErrors
+	if errors.As(err, &validateErrors) {
+		AbortWithFieldErrors(c, http.StatusUnprocessableEntity, FieldErrors(validateErrors)...)
+		return
+	}
+
+	AbortWithFieldErrors(c, http.StatusBadRequest, decodeError(err))
+}
+
+// AbortWithFieldErrors is used by handlers checking fields by hand. The body is the
+// apperror envelope, error and field describe the first problem and details lists all of them.
+func AbortWithFieldErrors(c *gin.Context, code int, fieldErrors ...FieldError) {
+	errorCode := "invalid_argument"
+	if code == http.StatusUnprocessableEntity {
+		errorCode = "validation_failed"
+	}
+
+	messages := make([]string, 0, len(fieldErrors))
+	for _, fe := range fieldErrors {
+		messages = append(messages, strings.TrimSpace(fe.Field+" "+fe.Message))
+	}
+
+	field := ""
+	if len(fieldErrors) > 0 {
+		field = fieldErrors[0].Field
+	}
+
+	body := apperror.Body(code, errorCode, strings.Join(messages, "; "), field)
+	body["details"] = fieldErrors
+	c.AbortWithStatusJSON(code, body)
+}
+
+func FieldErrors(validateErrors validator.ValidationErrors) []FieldError {
+	res := make([]FieldError, 0, len(validateErrors))
+	for _, fe := range validateErrors {
+		res = append(res, FieldError{
+			Field:   fieldPath(fe),
+			Code:    fe.Tag(),
+			Message: message(fe),
+		})
+	}
+
+	return res
+}
+
+// fieldPath drops the struct name from the namespace, passengers[0].name instead of Request.passengers[0].name
+func fieldPath(fe validator.FieldError) string {
+	namespace := fe.Namespace()
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+
+	return fe.Field()
+}
+
+func message(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "required_without":
+		return fmt.Sprintf("is required when %s is empty", lowerFirst(fe.Param()))
+	case "phone":
+		return "must be a phone number, e.g. +84912345678 or 0912345678"
+	case "isodate":
+		return "must be a date formatted YYYY-MM-DD"
+	case "email":
+		return "must be a valid email address"
+	case "idcard":
+		country := fe.Param()
+		if country == "" {
+			country = rules.DefaultCountry()
+		}
+		return fmt.Sprintf("must be a valid identity card number for %s", country)
+	case "oneof":
+		return fmt.Sprintf("must be one of: %s", strings.ReplaceAll(fe.Param(), " ", ", "))
+	case "eqfield":
+		return fmt.Sprintf("must match %s", lowerFirst(fe.Param()))
+	case "datetime":
+		return fmt.Sprintf("must match the layout %s", fe.Param())
+	case "numeric":
+		return "must contain digits only"
+	case "len", "min", "max":
+		return lengthMessage(fe)
+	}
+
+	return fmt.Sprintf("failed the %s rule", fe.Tag())
+}
+
+// lowerFirst turns a Go field name given as tag parameter into its json name
+func lowerFirst(name string) string {
+	if name == "" {
+		return name
+	}
+
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+func lengthMessage(fe validator.FieldError) string {
+	bound := map[string]string{"len": "exactly", "min": "at least", "max": "at most"}[fe.Tag()]
+
+	switch fe.Kind() {
+	case reflect.String:
+		return fmt.Sprintf("must be %s %s characters long", bound, fe.Param())
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return fmt.Sprintf("must contain %s %s items", bound, fe.Param())
+	}
+
+	return fmt.Sprintf("must be %s %s", bound, fe.Param())
+}
+
+func decodeError(err error) FieldError {
+	var typeError *json.UnmarshalTypeError
+	if errors.As(err, &typeError) {
+		return FieldError{
+			Field:   typeError.Field,
+			Code:    "type",
+			Message: fmt.Sprintf("must be a %s", typeError.Type.Kind()),
+		}
+	}
+
+	if errors.Is(err, io.EOF) {
+		return FieldError{Code: "body", Message: "request body is empty"}
+	}
+
+	return FieldError{Code: "malformed", Message: err.Error()}
+}
@@ -0,0 +1,69 @@
+package validation
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type customerRequest struct {
+	Email        string `json:"email" binding:"required,email"`
+	PhoneNumber  string `json:"phoneNumber" binding:"required,phone"`
+	DateOfBith   string `json:"dateOfBith" binding:"required,isodate"`
+	IdentityCard string `json:"identityCard" binding:"required,idcard"`
+	Passport     string `json:"passport" binding:"omitempty,idcard=SG"`
+}
+
+func TestAbortWithBindError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	viper.Set("customer.default_country_code", "84")
+
+	g := gin.New()
+	g.POST("/", func(c *gin.Context) {
+		req := customerRequest{}
+		if err := c.ShouldBind(&req); err != nil {
+			AbortWithBindError(c, err)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	post := func(body string) (int, string, []FieldError) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		g.ServeHTTP(w, r)
+
+		res := struct {
+			Field   string       `json:"field"`
+			Details []FieldError `json:"details"`
+		}{}
+		json.Unmarshal(w.Body.Bytes(), &res)
+		return w.Code, res.Field, res.Details
+	}
+
+	code, _, errs := post(`{"email":"an@example.com","phoneNumber":"0912345678","dateOfBith":"1990-01-31","identityCard":"001099012345"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Empty(t, errs)
+
+	code, field, errs := post(`{"email":"nope","phoneNumber":"0912345678","dateOfBith":"31/01/1990","passport":"123"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+	assert.Equal(t, "email", field)
+	assert.Equal(t, []FieldError{
+		{Field: "email", Code: "email", Message: "must be a valid email address"},
+		{Field: "dateOfBith", Code: "isodate", Message: "must be a date formatted YYYY-MM-DD"},
+		{Field: "identityCard", Code: "required", Message: "is required"},
+		{Field: "passport", Code: "idcard", Message: "must be a valid identity card number for SG"},
+	}, errs)
+
+	code, _, errs = post(`{"email":1}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, "email", errs[0].Field)
+	assert.Equal(t, "type", errs[0].Code)
+}